@@ -8,20 +8,86 @@ import (
 	"syscall"
 
 	"docs-hub/cmd"
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/audit"
+	"docs-hub/internal/auth"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/cloud/inmemory"
+	"docs-hub/internal/cloud/localfs"
 	"docs-hub/internal/cloud/s3minio"
+	"docs-hub/internal/expiry"
+	"docs-hub/internal/lifecycle"
+	"docs-hub/internal/rbac"
 	"docs-hub/internal/server"
 	"docs-hub/internal/server/httpserv"
+	"docs-hub/internal/share"
+	"docs-hub/internal/trash"
 )
 
 func main() {
 	servConfig := cmd.Execute()
 
-	cloudService := s3minio.New(&servConfig.Cloud)
+	cloudService := initCloud(&servConfig.Cloud)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	go awaitSystemSignals(cancel)
 
-	httpServer := httpserv.Init(&servConfig.Server, cloudService)
+	trashBin := trash.New(&servConfig.Trash, cloudService)
+	if servConfig.Trash.Enabled {
+		go trashBin.Run(ctx)
+	}
+
+	if servConfig.Expiry.Enabled {
+		sweeper := expiry.New(&servConfig.Expiry, cloudService)
+		go sweeper.Run(ctx)
+	}
+
+	if servConfig.Lifecycle.Enabled {
+		scheduler := lifecycle.New(&servConfig.Lifecycle, cloudService)
+		go scheduler.Run(ctx)
+	}
+
+	authenticator, err := auth.New(&servConfig.Auth)
+	if err != nil {
+		log.Fatalln("failed to init authentication: ", err)
+	}
+
+	policy, err := rbac.New(&servConfig.RBAC)
+	if err != nil {
+		log.Fatalln("failed to init access control: ", err)
+	}
+
+	apiKeys, err := apikey.New(&servConfig.APIKeys)
+	if err != nil {
+		log.Fatalln("failed to init api keys: ", err)
+	}
+
+	auditLog, err := audit.New(&servConfig.Audit)
+	if err != nil {
+		log.Fatalln("failed to init audit log: ", err)
+	}
+
+	shares, err := share.New(&servConfig.Shares)
+	if err != nil {
+		log.Fatalln("failed to init share links: ", err)
+	}
+
+	uploadLinks, err := share.NewUploadStore(&servConfig.Shares)
+	if err != nil {
+		log.Fatalln("failed to init upload links: ", err)
+	}
+
+	httpServer := httpserv.Init(
+		&servConfig.Server,
+		cloudService,
+		trashBin,
+		authenticator,
+		policy,
+		apiKeys,
+		auditLog,
+		shares,
+		uploadLinks,
+	)
 	go func() {
 		err := httpServer.Server.Start(ctx)
 		if err != nil {
@@ -33,6 +99,28 @@ func main() {
 	<-ctx.Done()
 	cancel()
 	shutdownServices(ctx, httpServer)
+
+	if err = shares.Close(); err != nil {
+		log.Println("failed to save share links: ", err)
+	}
+
+	if err = auditLog.Close(); err != nil {
+		log.Println("failed to close audit log: ", err)
+	}
+}
+
+func initCloud(config *cloud.CloudConfig) *cloud.DocumentHub {
+	switch config.Provider {
+	case "", "s3":
+		return s3minio.New(config)
+	case "fs":
+		return localfs.New(config)
+	case "memory":
+		return inmemory.New(config)
+	default:
+		log.Fatalln("unknown cloud provider: ", config.Provider)
+		return nil
+	}
 }
 
 func awaitSystemSignals(cancel context.CancelFunc) {
@@ -15,36 +15,66 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/cloud/bucket": {
-            "put": {
-                "description": "Create new bucket into cloud",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/audit": {
+            "get": {
+                "description": "Get audit events newest first, filtered by time range, actor, bucket\nand operation. Next page is requested by next cursor of previous page.\nAdmin role on all buckets is required.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "buckets"
+                    "admin"
                 ],
-                "summary": "Create new bucket into cloud",
-                "operationId": "create-bucket",
+                "summary": "Get audit events",
+                "operationId": "get-audit-events",
                 "parameters": [
                     {
-                        "description": "Bucket name to create",
-                        "name": "jsonQuery",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpserv.CreateBucketForm"
-                        }
+                        "type": "string",
+                        "description": "Events recorded at or after time, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Events recorded before time, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject or client IP of anonymous caller",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket which is source or destination of operation",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation, like file.download",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of events, 100 by default and 1000 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Next cursor of previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "$ref": "#/definitions/httpserv.ResponseForm"
+                            "$ref": "#/definitions/audit.EventsPage"
                         }
                     },
                     "400": {
@@ -53,6 +83,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -62,25 +98,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/buckets": {
+        "/admin/audit/verify": {
             "get": {
-                "description": "Get watched bucket list",
+                "description": "Check hash chain of audit log, chain is broken when any event has been\nchanged or removed. Admin role on all buckets is required.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "buckets"
+                    "admin"
                 ],
-                "summary": "Get watched bucket list",
-                "operationId": "get-buckets",
+                "summary": "Verify audit log",
+                "operationId": "verify-audit-log",
                 "responses": {
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/httpserv.AuditVerifyForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
                     "503": {
@@ -92,31 +137,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}": {
-            "delete": {
-                "description": "Remove bucket from cloud",
+        "/admin/keys": {
+            "get": {
+                "description": "Get API keys including revoked ones, key values are never returned",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "buckets"
+                    "admin"
                 ],
-                "summary": "Remove bucket from cloud",
-                "operationId": "remove-bucket",
+                "summary": "Get API keys",
+                "operationId": "get-api-keys",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bucket name to remove",
-                        "name": "bucket",
-                        "in": "path",
-                        "required": true
+                        "description": "Bootstrap token while access control is disabled",
+                        "name": "X-Bootstrap-Token",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "$ref": "#/definitions/httpserv.ResponseForm"
+                            "$ref": "#/definitions/httpserv.APIKeysForm"
                         }
                     },
                     "400": {
@@ -125,6 +169,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "401": {
+                        "description": "Authentication is required",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -132,11 +188,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/cloud/{bucket}/file/copy": {
+            },
             "post": {
-                "description": "Copy file to another location into bucket",
+                "description": "Create API key scoped to buckets and operations for service which calls API\nwithout user. Bucket \"*\" scopes key to all buckets, prefixes scope key to objects\nunder these folders only. Key value is returned only once and must be passed\nby X-API-Key header. Keys are managed by global admin users, or by\nX-Bootstrap-Token header while access control is disabled.",
                 "consumes": [
                     "application/json"
                 ],
@@ -144,33 +198,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Copy file to another location into bucket",
-                "operationId": "copy-file",
+                "summary": "Create API key",
+                "operationId": "create-api-key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bucket name of src file",
-                        "name": "bucket",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Params to copy file",
+                        "description": "Key name and scope",
                         "name": "jsonQuery",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpserv.CopyFileForm"
+                            "$ref": "#/definitions/httpserv.CreateAPIKeyForm"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bootstrap token while access control is disabled",
+                        "name": "X-Bootstrap-Token",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "$ref": "#/definitions/httpserv.ResponseForm"
+                            "$ref": "#/definitions/httpserv.APIKeyCreatedForm"
                         }
                     },
                     "400": {
@@ -179,6 +232,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "401": {
+                        "description": "Authentication is required",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -188,43 +253,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}/file/download": {
-            "post": {
-                "description": "Download file from cloud",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/keys/{id}": {
+            "delete": {
+                "description": "Revoke API key by its id, revoked key is kept in list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Download file from cloud",
-                "operationId": "download-file",
+                "summary": "Revoke API key",
+                "operationId": "revoke-api-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bucket name to download file",
-                        "name": "bucket",
+                        "description": "Key id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Parameters to download file",
-                        "name": "jsonQuery",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpserv.DownloadFileForm"
-                        }
+                        "type": "string",
+                        "description": "Bootstrap token while access control is disabled",
+                        "name": "X-Bootstrap-Token",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/httpserv.ResponseForm"
                         }
                     },
                     "400": {
@@ -233,6 +292,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "401": {
+                        "description": "Authentication is required",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Key does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -242,9 +319,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}/file/move": {
+        "/admin/policy/bindings": {
+            "get": {
+                "description": "Get role bindings of access control policy, admin role on all buckets is required",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get role bindings",
+                "operationId": "get-policy-bindings",
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.PolicyBindingsForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Move file to another location into bucket",
+                "description": "Grant role to user or group on bucket objects under prefix.\nBucket \"*\" grants role on all buckets, empty prefix grants role on whole bucket.",
                 "consumes": [
                     "application/json"
                 ],
@@ -252,25 +366,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Move file to another location into bucket",
-                "operationId": "move-file",
+                "summary": "Add role binding",
+                "operationId": "add-policy-binding",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bucket name of src file",
-                        "name": "bucket",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Params to move file",
+                        "description": "Role binding",
                         "name": "jsonQuery",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpserv.CopyFileForm"
+                            "$ref": "#/definitions/rbac.Binding"
                         }
                     }
                 ],
@@ -278,7 +385,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "$ref": "#/definitions/httpserv.ResponseForm"
+                            "$ref": "#/definitions/rbac.Binding"
                         }
                     },
                     "400": {
@@ -287,6 +394,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -296,33 +409,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}/file/remove": {
+        "/admin/policy/bindings/{id}": {
             "delete": {
-                "description": "Remove file from cloud",
+                "description": "Revoke role binding by its id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Remove file from cloud",
-                "operationId": "remove-file",
+                "summary": "Remove role binding",
+                "operationId": "remove-policy-binding",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bucket name to remove file",
-                        "name": "bucket",
+                        "description": "Binding id",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Parameters to remove file",
-                        "name": "jsonQuery",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpserv.RemoveFileForm"
-                        }
                     }
                 ],
                 "responses": {
@@ -338,6 +442,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Binding does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -347,38 +463,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}/file/share": {
+        "/admin/policy/reload": {
             "post": {
-                "description": "Get share URL for file",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Replace role bindings by content of policy file after it was edited manually",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "share"
-                ],
-                "summary": "Get share URL for file",
-                "operationId": "share-file",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bucket name to share file",
-                        "name": "bucket",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Parameters to share file",
-                        "name": "jsonQuery",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpserv.ShareFileForm"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Reload policy file",
+                "operationId": "reload-policy",
                 "responses": {
                     "200": {
                         "description": "Ok",
@@ -392,6 +487,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -401,40 +502,107 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}/file/upload": {
+        "/cloud/bucket": {
             "put": {
-                "description": "Upload files to cloud",
+                "description": "Create new bucket into cloud",
                 "consumes": [
-                    "multipart/form"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "buckets"
                 ],
-                "summary": "Upload files to cloud",
-                "operationId": "upload-files",
+                "summary": "Create new bucket into cloud",
+                "operationId": "create-bucket",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bucket name to upload files",
+                        "description": "Bucket name to create and its versioning status",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CreateBucketForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/buckets": {
+            "get": {
+                "description": "Get watched bucket list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "buckets"
+                ],
+                "summary": "Get watched bucket list",
+                "operationId": "get-buckets",
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}": {
+            "delete": {
+                "description": "Remove bucket from cloud. Non-empty bucket is removed only with force flag,\nthen all its objects, their versions and incomplete uploads are removed too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "buckets"
+                ],
+                "summary": "Remove bucket from cloud",
+                "operationId": "remove-bucket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to remove",
                         "name": "bucket",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "File datetime expired like 2025-01-01T12:01:01Z",
-                        "name": "expired",
+                        "type": "boolean",
+                        "description": "Remove bucket with all its content",
+                        "name": "force",
                         "in": "query"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Files multipart form",
-                        "name": "files",
-                        "in": "formData",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -450,6 +618,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "409": {
+                        "description": "Bucket is not empty",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -459,9 +633,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/cloud/{bucket}/files": {
+        "/cloud/{bucket}/file/copy": {
             "post": {
-                "description": "Get files list into bucket",
+                "description": "Copy file server side to another location into bucket or another bucket of the same storage",
                 "consumes": [
                     "application/json"
                 ],
@@ -471,23 +645,23 @@ const docTemplate = `{
                 "tags": [
                     "files"
                 ],
-                "summary": "Get files list into bucket",
-                "operationId": "get-list-files",
+                "summary": "Copy file to another location into bucket or another bucket",
+                "operationId": "copy-file",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bucket name to get list files",
+                        "description": "Bucket name of src file",
                         "name": "bucket",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Parameters to get list files",
+                        "description": "Params to copy file",
                         "name": "jsonQuery",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpserv.GetFilesForm"
+                            "$ref": "#/definitions/httpserv.CopyFileForm"
                         }
                     }
                 ],
@@ -495,7 +669,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Ok",
                         "schema": {
-                            "$ref": "#/definitions/httpserv.ResponseForm"
+                            "$ref": "#/definitions/httpserv.FileResponseForm"
                         }
                     },
                     "400": {
@@ -504,6 +678,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserv.BadRequestForm"
                         }
                     },
+                    "409": {
+                        "description": "File already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
                     "503": {
                         "description": "Server does not available",
                         "schema": {
@@ -512,68 +692,3913 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "httpserv.BadRequestForm": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Bad Request message"
-                },
-                "status": {
-                    "type": "integer",
-                    "example": 400
-                }
-            }
-        },
-        "httpserv.CopyFileForm": {
-            "type": "object",
-            "properties": {
-                "dst_path": {
-                    "type": "string",
-                    "example": "test-document.docx"
-                },
-                "src_path": {
-                    "type": "string",
-                    "example": "old-test-document.docx"
-                }
-            }
-        },
-        "httpserv.CreateBucketForm": {
-            "type": "object",
-            "properties": {
-                "bucket_name": {
-                    "type": "string",
-                    "example": "test-bucket"
-                }
-            }
-        },
-        "httpserv.DownloadFileForm": {
-            "type": "object",
-            "properties": {
-                "file_name": {
-                    "type": "string",
-                    "example": "test-file.docx"
-                }
-            }
         },
-        "httpserv.GetFilesForm": {
-            "type": "object",
-            "properties": {
-                "directory": {
-                    "type": "string",
-                    "example": "test-folder/"
+        "/cloud/{bucket}/file/download": {
+            "post": {
+                "description": "Download file from cloud",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Download file from cloud",
+                "operationId": "download-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to download file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters to download file",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.DownloadFileForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
                 }
             }
         },
-        "httpserv.RemoveFileForm": {
-            "type": "object",
-            "properties": {
-                "file_name": {
-                    "type": "string",
-                    "example": "test-file.docx"
+        "/cloud/{bucket}/file/expiry": {
+            "put": {
+                "description": "Extend or shorten expiry of existing file, null expires_at clears expiry\nso file is kept until it is removed explicitly",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Set file expiry",
+                "operationId": "set-file-expiry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File and its new expiry",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileExpiryForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/move": {
+            "post": {
+                "description": "Move file server side to another location into bucket or another bucket of the same storage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Move file to another location into bucket or another bucket",
+                "operationId": "move-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of src file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Params to move file",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CopyFileForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "409": {
+                        "description": "File already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/presign-upload": {
+            "post": {
+                "description": "Sign upload which client sends directly to cloud. PUT uploads given file path\nand can be constrained by content type, POST form uploads file path or any file\ninto folder and can be constrained by content type and max size.\nClouds which can not presign uploads answer 400, use upload links for them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Presign upload to cloud",
+                "operationId": "presign-upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to upload into",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Upload constraints",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.PresignUploadForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/cloud.PresignedUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/remove": {
+            "delete": {
+                "description": "Move file into trash of bucket when trash is enabled, otherwise remove it permanently",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Remove file from cloud",
+                "operationId": "remove-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to remove file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Remove file permanently bypassing trash, admin role is required",
+                        "name": "permanent",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Parameters to remove file",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.RemoveFileForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/share": {
+            "post": {
+                "description": "Get share URL for file. When share links are enabled URL is managed share link\nof docs-hub which expires after given seconds, otherwise it is cloud presigned URL.\nManaged link without expired_secs expires after default expiry of share links.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Get share URL for file",
+                "operationId": "share-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to share file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters to share file",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ShareFileForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "500": {
+                        "description": "Base URL of links is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/stat": {
+            "post": {
+                "description": "Get file metadata without downloading file content",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get file metadata",
+                "operationId": "stat-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters to get file metadata",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.StatFileForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/cloud.StorageItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/upload": {
+            "put": {
+                "description": "Upload files to cloud and report result of every file. With atomic mode\nfiles are uploaded to staging area and are moved to their paths only if\nevery file has been uploaded, so existing files are never lost by rollback.",
+                "consumes": [
+                    "multipart/form"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Upload files to cloud",
+                "operationId": "upload-files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to upload files",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File datetime expired like 2025-01-01T12:01:01Z, expired files are removed by sweeper",
+                        "name": "expired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Rollback uploaded files if any file failed",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Directory to upload files into",
+                        "name": "directory",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "overwrite",
+                            "skip",
+                            "rename",
+                            "fail"
+                        ],
+                        "type": "string",
+                        "default": "fail",
+                        "description": "Policy if file already exists",
+                        "name": "on_conflict",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Files multipart form",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadFilesForm"
+                        }
+                    },
+                    "207": {
+                        "description": "Some files have not been uploaded",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadFilesForm"
+                        }
+                    },
+                    "400": {
+                        "description": "No file has been uploaded",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadFilesForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/version/download": {
+            "post": {
+                "description": "Download content of specified file version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Download version of file",
+                "operationId": "download-file-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File version to download",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileVersionForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/version/remove": {
+            "delete": {
+                "description": "Permanently remove specified file version or delete marker",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Remove version of file",
+                "operationId": "remove-file-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File version to remove",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileVersionForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/version/restore": {
+            "post": {
+                "description": "Make copy of specified file version the current version, other versions are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Restore version of file",
+                "operationId": "restore-file-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File version to restore",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileVersionForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/versions": {
+            "post": {
+                "description": "Get all versions and delete markers of file, the newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Get versions of file",
+                "operationId": "list-file-versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File to get versions of",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileVersionsForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cloud.FileVersion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/file/{path}": {
+            "get": {
+                "description": "Get file content with support of byte ranges and conditional requests",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get file content from cloud",
+                "operationId": "get-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to get file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path into bucket",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range like bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cached file etag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cached file modification time",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "416": {
+                        "description": "Range Not Satisfiable message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Get file content with support of byte ranges and conditional requests",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get file content from cloud",
+                "operationId": "get-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to get file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path into bucket",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range like bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cached file etag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cached file modification time",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "416": {
+                        "description": "Range Not Satisfiable message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/files": {
+            "post": {
+                "description": "Get paginated files list into bucket filtered and sorted by parameters.\nListing sorted by name ascending continues after last listed file. Other sort orders\nload and sort whole listing for every page, so they are limited to 10000 files matching\ndirectory and filters, larger listing is rejected with 400. Their cursor is offset,\nso pages may skip or repeat files if listing is changed between requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get files list into bucket",
+                "operationId": "get-list-files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to get list files",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters to get list files",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.GetFilesForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/cloud.FilesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/files/move": {
+            "post": {
+                "description": "Move documents from source folder into target folder keeping their relative paths,\noptionally into another bucket, and report result of every document",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Move many documents to another folder",
+                "operationId": "move-files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of source documents",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Params to move documents",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.MoveFilesForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.MoveFilesResponseForm"
+                        }
+                    },
+                    "207": {
+                        "description": "Some documents have not been moved",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.MoveFilesResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "No document has been moved",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.MoveFilesResponseForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/files/share": {
+            "post": {
+                "description": "Get managed share link of folder or set of files which expires after given seconds.\nLink opens page listing shared files which are downloaded one by one or as ZIP.\nExactly one of folder and file_names is shared, share links must be enabled.\nLink without expired_secs expires after default expiry of share links.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Get share URL for folder or set of files",
+                "operationId": "share-files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to share files",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters to share files",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ShareFilesForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "File does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "500": {
+                        "description": "Base URL of links is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/folder": {
+            "put": {
+                "description": "Create empty folder which is kept by placeholder object until it is removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Create empty folder",
+                "operationId": "create-folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to create folder",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Params to create folder",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CreateFolderForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/folder/copy": {
+            "post": {
+                "description": "Copy folder tree to another location into bucket or another bucket\nand report every object which has not been copied",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Copy folder with all its content",
+                "operationId": "copy-folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of source folder",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Stream progress records as JSON lines before final report",
+                        "name": "progress",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Params to copy folder",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CopyFolderForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "207": {
+                        "description": "Some objects have not been copied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "No object has been copied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/folder/move": {
+            "post": {
+                "description": "Move folder tree to another location into bucket or another bucket\nand report every object which has not been moved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Move or rename folder with all its content",
+                "operationId": "move-folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of source folder",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Stream progress records as JSON lines before final report",
+                        "name": "progress",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Params to move folder",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CopyFolderForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "207": {
+                        "description": "Some objects have not been moved",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "No object has been moved",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/folder/remove": {
+            "delete": {
+                "description": "Remove folder tree by batches of objects and report every object which has not been removed.\nFiles are moved into trash of bucket when trash is enabled, unless removed permanently.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Remove folder with all its content",
+                "operationId": "remove-folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to remove folder",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Stream progress records as JSON lines before final report",
+                        "name": "progress",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Remove files permanently bypassing trash, admin role is required",
+                        "name": "permanent",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Params to remove folder",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.RemoveFolderForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "207": {
+                        "description": "Some objects have not been removed",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "No object has been removed",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FolderResponseForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/lifecycle": {
+            "get": {
+                "description": "Get lifecycle rules of bucket, empty list means no rule is set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "buckets"
+                ],
+                "summary": "Get bucket lifecycle rules",
+                "operationId": "get-bucket-lifecycle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BucketLifecycleForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace lifecycle rules of bucket, empty rules list removes all rules.\nEach rule applies to objects under its prefix: expire objects, remove\nnoncurrent versions, abort incomplete uploads or transition objects\nto another storage class after given number of days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "buckets"
+                ],
+                "summary": "Set bucket lifecycle rules",
+                "operationId": "set-bucket-lifecycle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lifecycle rules",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BucketLifecycleForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/purge": {
+            "post": {
+                "description": "Remove all objects of bucket, their versions and incomplete uploads but keep bucket",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "buckets"
+                ],
+                "summary": "Remove all content of bucket",
+                "operationId": "purge-bucket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to purge",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/share/{path}": {
+            "get": {
+                "description": "Download file by share URL signed by docs-hub for clouds without presigning",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download file by signed share URL",
+                "operationId": "download-shared-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of shared file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shared file path",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Share URL expiration unix timestamp",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share URL signature",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/shares": {
+            "get": {
+                "description": "Get managed share links of bucket including revoked ones, expired links are\npruned. Bucket admins get all links, other callers get links they have created.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Get share links of bucket",
+                "operationId": "get-share-links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ShareLinksForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create share link of file, folder or set of files served by docs-hub, link may\nbe limited by expiry, number of downloads and password and can be revoked.\nExactly one of file_name, folder and file_names is shared. Link token is returned\nonly once, recipients download file or list shared files by GET /s/{token}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Create managed share link",
+                "operationId": "create-share-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of shared file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File and limits of link",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CreateShareLinkForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ShareLinkCreatedForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "File does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "500": {
+                        "description": "Base URL of links is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/shares/{id}": {
+            "delete": {
+                "description": "Revoke managed share link, only its creator or bucket admin can revoke it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Revoke share link",
+                "operationId": "revoke-share-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/trash": {
+            "get": {
+                "description": "Get paginated list of deleted files kept into trash of bucket, the oldest first.\nOnly files which caller may read at their original paths are listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get trash entries of bucket",
+                "operationId": "get-trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max entries count of page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Continuation cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/trash.EntriesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently remove all files kept into trash of bucket",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Empty trash of bucket",
+                "operationId": "empty-trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/trash/{id}/restore": {
+            "post": {
+                "description": "Move deleted file back to its original path, conflict policy is applied\nif another file has been stored there since deletion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore file from trash",
+                "operationId": "restore-trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trash entry id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Params to restore file",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.RestoreTrashForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.FileResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "409": {
+                        "description": "File already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/upload-links": {
+            "get": {
+                "description": "Get upload links of bucket including revoked and expired ones.\nBucket admins get all links, other callers get links they have created.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Get upload links of bucket",
+                "operationId": "get-upload-links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadLinksForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create link which external contributor uploads files into folder by without\naccount. Link may be limited by expiry, number of files and size of every file.\nLink token is returned only once, files are uploaded by POST /u/{token}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Create upload link",
+                "operationId": "create-upload-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to upload into",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Folder and limits of link",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CreateUploadLinkForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadLinkCreatedForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "500": {
+                        "description": "Base URL of links is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/upload-links/{id}": {
+            "delete": {
+                "description": "Revoke upload link, only its creator or bucket admin can revoke it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Revoke upload link",
+                "operationId": "revoke-upload-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload link id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload link does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/uploads": {
+            "post": {
+                "description": "Start resumable multipart upload session of single file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Start resumable upload session",
+                "operationId": "create-upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name to upload file",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters to start upload",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.CreateUploadForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadSessionForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/uploads/{id}": {
+            "get": {
+                "description": "Get already uploaded parts of session to resume upload",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Get resumable upload session state",
+                "operationId": "get-upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of upload",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadSessionForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Abort upload session and remove already uploaded parts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Abort resumable upload session",
+                "operationId": "abort-upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of upload",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/uploads/{id}/complete": {
+            "post": {
+                "description": "Concatenate uploaded parts into file and close session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Complete resumable upload session",
+                "operationId": "complete-upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of upload",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/uploads/{id}/parts/{n}": {
+            "put": {
+                "description": "Upload numbered chunk of file, every part except last must be at least 5MiB",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Upload part of file",
+                "operationId": "upload-part",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name of upload",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Part number from 1 to 10000",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/cloud.UploadPart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "411": {
+                        "description": "Length Required message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/cloud/{bucket}/versioning": {
+            "get": {
+                "description": "Get bucket versioning status, empty status means versioning has never been enabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Get bucket versioning status",
+                "operationId": "get-bucket-versioning",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BucketVersioningForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Enable or suspend bucket versioning, versions kept so far are not removed on suspend",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Enable or suspend bucket versioning",
+                "operationId": "set-bucket-versioning",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Versioning status",
+                        "name": "jsonQuery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BucketVersioningForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request message",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "503": {
+                        "description": "Server does not available",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check that service is up, the route does not require authentication",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Check service health",
+                "operationId": "health",
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ResponseForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/s/{token}": {
+            "get": {
+                "description": "Download file shared by managed link, the route does not require authentication.\nLink of folder or set of files renders page listing them, listing is not counted\nas download. Password of protected link is passed by X-Share-Password header\nor by password field of POST form, browser is asked for it by form page.\nLink is locked for a while after several wrong passwords.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/octet-stream",
+                    "text/html"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download file by share link",
+                "operationId": "download-share-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Password is required or wrong",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Share link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "429": {
+                        "description": "Share link is locked after wrong passwords",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Download file shared by managed link, the route does not require authentication.\nLink of folder or set of files renders page listing them, listing is not counted\nas download. Password of protected link is passed by X-Share-Password header\nor by password field of POST form, browser is asked for it by form page.\nLink is locked for a while after several wrong passwords.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/octet-stream",
+                    "text/html"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download file by share link",
+                "operationId": "download-share-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Password is required or wrong",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Share link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "429": {
+                        "description": "Share link is locked after wrong passwords",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/s/{token}/file/{path}": {
+            "get": {
+                "description": "Download single file listed by share link, the route does not require authentication",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download file of shared folder or set of files",
+                "operationId": "download-share-link-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shared file path",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file path",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "401": {
+                        "description": "Password is required or wrong",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link or file does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Share link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "429": {
+                        "description": "Share link is locked after wrong passwords",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Download single file listed by share link, the route does not require authentication",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download file of shared folder or set of files",
+                "operationId": "download-share-link-file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shared file path",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file path",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "401": {
+                        "description": "Password is required or wrong",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link or file does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Share link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "429": {
+                        "description": "Share link is locked after wrong passwords",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/s/{token}/zip": {
+            "get": {
+                "description": "Download all files shared by link as single ZIP archive, it is counted as one\ndownload. Archive is limited by number and total size of files by config.\nThe route does not require authentication.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download shared files as ZIP",
+                "operationId": "download-share-link-zip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Password is required or wrong",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link does not exist or has no files",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Share link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "413": {
+                        "description": "Shared files exceed limits of archive",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "429": {
+                        "description": "Share link is locked after wrong passwords",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "500": {
+                        "description": "Shared files can not be listed",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Download all files shared by link as single ZIP archive, it is counted as one\ndownload. Archive is limited by number and total size of files by config.\nThe route does not require authentication.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Download shared files as ZIP",
+                "operationId": "download-share-link-zip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share link password",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Password is required or wrong",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link does not exist or has no files",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Share link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "413": {
+                        "description": "Shared files exceed limits of archive",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "429": {
+                        "description": "Share link is locked after wrong passwords",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "500": {
+                        "description": "Shared files can not be listed",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.ServerErrorForm"
+                        }
+                    }
+                }
+            }
+        },
+        "/u/{token}": {
+            "get": {
+                "description": "Get folder and remaining limits of upload link, the route does not require authentication",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Get upload link limits",
+                "operationId": "get-upload-link-info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadLinkInfoForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload link does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Upload files into folder of upload link, the route does not require authentication.\nExisting files are never replaced, uploaded file is renamed instead.",
+                "consumes": [
+                    "multipart/form"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "Upload files by upload link",
+                "operationId": "upload-by-link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Files multipart form",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadFilesForm"
+                        }
+                    },
+                    "207": {
+                        "description": "Some files have not been uploaded",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadFilesForm"
+                        }
+                    },
+                    "400": {
+                        "description": "No file has been uploaded",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.UploadFilesForm"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload link does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload link is revoked, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/httpserv.BadRequestForm"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikey.Key": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "contracts",
+                        "archive"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:01:01Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-01T12:01:01Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c4a9e2f1b3d5a60"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2025-03-01T08:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "indexer"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "read",
+                            "write",
+                            "admin"
+                        ],
+                        "$ref": "#/definitions/apikey.Operation"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                },
+                "prefixes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024/"
+                    ]
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2025-04-01T10:00:00Z"
+                }
+            }
+        },
+        "apikey.Operation": {
+            "type": "string",
+            "enum": [
+                "read",
+                "write",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "OperationRead",
+                "OperationWrite",
+                "OperationAdmin"
+            ]
+        },
+        "audit.Event": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "bucket": {
+                    "type": "string",
+                    "example": "contracts"
+                },
+                "client_ip": {
+                    "type": "string",
+                    "example": "10.0.0.12"
+                },
+                "dst_bucket": {
+                    "type": "string",
+                    "example": "archive"
+                },
+                "dst_path": {
+                    "type": "string",
+                    "example": "2024/supply.docx"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "access denied"
+                },
+                "hash": {
+                    "type": "string",
+                    "example": "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "file.download"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "2024/supply.docx"
+                },
+                "prev_hash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "Jf3bQ2sLzWc8XyN1hKpR4tVmA6dE9uGo"
+                },
+                "result": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "failure"
+                    ],
+                    "example": "success"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2025-01-01T12:01:01Z"
+                }
+            }
+        },
+        "audit.EventsPage": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/audit.Event"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "1024"
+                }
+            }
+        },
+        "cloud.FileVersion": {
+            "type": "object",
+            "properties": {
+                "etag": {
+                    "type": "string"
+                },
+                "is_delete_marker": {
+                    "type": "boolean"
+                },
+                "is_latest": {
+                    "type": "boolean"
+                },
+                "last_modified": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "version_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "cloud.FilesPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cloud.StorageItem"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "cloud.LifecycleRule": {
+            "type": "object",
+            "properties": {
+                "abort_incomplete_upload_days": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "expiration_days": {
+                    "type": "integer",
+                    "example": 365
+                },
+                "id": {
+                    "type": "string",
+                    "example": "expire-reports"
+                },
+                "noncurrent_expiration_days": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "reports/"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Enabled",
+                        "Disabled"
+                    ],
+                    "example": "Enabled"
+                },
+                "transition_days": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "transition_storage_class": {
+                    "type": "string",
+                    "example": "GLACIER"
+                }
+            }
+        },
+        "cloud.PresignedUpload": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-01T13:01:01Z"
+                },
+                "form_data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "PUT",
+                        "POST"
+                    ],
+                    "example": "POST"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:9000/contracts"
+                }
+            }
+        },
+        "cloud.StorageItem": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "directory_name": {
+                    "type": "string"
+                },
+                "etag": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "is_directory": {
+                    "type": "boolean"
+                },
+                "last_modified": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "storage_class": {
+                    "type": "string"
+                },
+                "user_metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "cloud.UploadPart": {
+            "type": "object",
+            "properties": {
+                "etag": {
+                    "type": "string"
+                },
+                "last_modified": {
+                    "type": "string"
+                },
+                "part_number": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserv.APIKeyCreatedForm": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "$ref": "#/definitions/apikey.Key"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "dhk_9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822c"
+                }
+            }
+        },
+        "httpserv.APIKeysForm": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apikey.Key"
+                    }
+                }
+            }
+        },
+        "httpserv.AuditVerifyForm": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "error": {
+                    "type": "string",
+                    "example": "event 12 of request Jf3bQ2sL breaks hash chain"
+                },
+                "is_valid": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "httpserv.BadRequestForm": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Bad Request message"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "httpserv.BucketLifecycleForm": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cloud.LifecycleRule"
+                    }
+                }
+            }
+        },
+        "httpserv.BucketVersioningForm": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Enabled",
+                        "Suspended"
+                    ],
+                    "example": "Enabled"
+                }
+            }
+        },
+        "httpserv.CopyFileForm": {
+            "type": "object",
+            "properties": {
+                "dst_bucket": {
+                    "type": "string",
+                    "example": "archive"
+                },
+                "dst_path": {
+                    "type": "string",
+                    "example": "test-document.docx"
+                },
+                "on_conflict": {
+                    "type": "string",
+                    "default": "fail",
+                    "enum": [
+                        "overwrite",
+                        "skip",
+                        "rename",
+                        "fail"
+                    ],
+                    "example": "rename"
+                },
+                "src_path": {
+                    "type": "string",
+                    "example": "old-test-document.docx"
+                }
+            }
+        },
+        "httpserv.CopyFolderForm": {
+            "type": "object",
+            "properties": {
+                "dst_bucket": {
+                    "type": "string",
+                    "example": "archive"
+                },
+                "dst_folder": {
+                    "type": "string",
+                    "example": "reports/archive/2024"
+                },
+                "on_conflict": {
+                    "type": "string",
+                    "default": "fail",
+                    "enum": [
+                        "overwrite",
+                        "skip",
+                        "rename",
+                        "fail"
+                    ],
+                    "example": "rename"
+                },
+                "src_folder": {
+                    "type": "string",
+                    "example": "reports/2024"
+                }
+            }
+        },
+        "httpserv.CreateAPIKeyForm": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "contracts",
+                        "archive"
+                    ]
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-01T12:01:01Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "indexer"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "read",
+                            "write",
+                            "admin"
+                        ],
+                        "$ref": "#/definitions/apikey.Operation"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                },
+                "prefixes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024/"
+                    ]
+                }
+            }
+        },
+        "httpserv.CreateBucketForm": {
+            "type": "object",
+            "properties": {
+                "bucket_name": {
+                    "type": "string",
+                    "example": "test-bucket"
+                },
+                "versioning": {
+                    "type": "string",
+                    "enum": [
+                        "Enabled",
+                        "Suspended"
+                    ],
+                    "example": "Enabled"
+                }
+            }
+        },
+        "httpserv.CreateFolderForm": {
+            "type": "object",
+            "properties": {
+                "folder_path": {
+                    "type": "string",
+                    "example": "reports/2024"
+                }
+            }
+        },
+        "httpserv.CreateShareLinkForm": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-08T12:01:01Z"
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "2024/supply.docx"
+                },
+                "file_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024/supply.docx",
+                        "2024/invoice.pdf"
+                    ]
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "2024/"
+                },
+                "max_downloads": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "password": {
+                    "type": "string",
+                    "example": "s3cr3t-phrase"
+                }
+            }
+        },
+        "httpserv.CreateUploadForm": {
+            "type": "object",
+            "properties": {
+                "file_path": {
+                    "type": "string",
+                    "example": "archives/field-data.zip"
+                }
+            }
+        },
+        "httpserv.CreateUploadLinkForm": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-08T12:01:01Z"
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "incoming/acme/"
+                },
+                "max_files": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_size": {
+                    "type": "integer",
+                    "example": 104857600
+                }
+            }
+        },
+        "httpserv.DownloadFileForm": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string",
+                    "example": "test-file.docx"
+                }
+            }
+        },
+        "httpserv.FileExpiryForm": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:01:01Z"
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "test-file.docx"
+                }
+            }
+        },
+        "httpserv.FileResponseForm": {
+            "type": "object",
+            "properties": {
+                "file_path": {
+                    "type": "string",
+                    "example": "test-document (1).docx"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Ok"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "httpserv.FileVersionForm": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string",
+                    "example": "contracts/supply-2024.docx"
+                },
+                "version_id": {
+                    "type": "string",
+                    "example": "3f1d0c5e9a7b4e21b6a8d2c4f0e9a1b7"
+                }
+            }
+        },
+        "httpserv.FileVersionsForm": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string",
+                    "example": "contracts/supply-2024.docx"
+                }
+            }
+        },
+        "httpserv.FolderObjectError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "file already exists"
+                },
+                "file_path": {
+                    "type": "string",
+                    "example": "reports/2024/summary.xlsx"
+                }
+            }
+        },
+        "httpserv.FolderResponseForm": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserv.FolderObjectError"
+                    }
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 207
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "httpserv.GetFilesForm": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string",
+                    "example": ""
+                },
+                "directory": {
+                    "type": "string",
+                    "example": "test-folder/"
+                },
+                "extensions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "docx",
+                        "pdf"
+                    ]
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "max_size": {
+                    "type": "integer",
+                    "example": 1048576
+                },
+                "min_size": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "modified_after": {
+                    "type": "string",
+                    "example": "2025-01-01T12:01:01Z"
+                },
+                "name_glob": {
+                    "type": "string",
+                    "example": "report-*"
+                },
+                "recursive": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "sort_by": {
+                    "type": "string",
+                    "enum": [
+                        "name",
+                        "size",
+                        "modified"
+                    ],
+                    "example": "name"
+                },
+                "sort_desc": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "httpserv.MoveFileResult": {
+            "type": "object",
+            "properties": {
+                "document_id": {
+                    "type": "string",
+                    "example": "./indexer/watcher/test.txt"
+                },
+                "dst_path": {
+                    "type": "string",
+                    "example": "common-folder/indexer/watcher/test.txt"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "file already exists"
+                },
+                "src_path": {
+                    "type": "string",
+                    "example": "unrecognized/indexer/watcher/test.txt"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "moved",
+                        "skipped",
+                        "failed"
+                    ],
+                    "example": "moved"
+                }
+            }
+        },
+        "httpserv.MoveFilesForm": {
+            "type": "object",
+            "properties": {
+                "document_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "./indexer/watcher/test.txt"
+                    ]
+                },
+                "location": {
+                    "type": "string",
+                    "example": "common-folder"
+                },
+                "on_conflict": {
+                    "type": "string",
+                    "default": "fail",
+                    "enum": [
+                        "overwrite",
+                        "skip",
+                        "rename",
+                        "fail"
+                    ],
+                    "example": "rename"
+                },
+                "src_folder_id": {
+                    "type": "string",
+                    "example": "unrecognized"
+                },
+                "target_bucket": {
+                    "type": "string",
+                    "example": "archive"
+                }
+            }
+        },
+        "httpserv.MoveFilesResponseForm": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserv.MoveFileResult"
+                    }
+                },
+                "moved": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 207
+                }
+            }
+        },
+        "httpserv.PolicyBindingsForm": {
+            "type": "object",
+            "properties": {
+                "bindings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rbac.Binding"
+                    }
+                }
+            }
+        },
+        "httpserv.PresignUploadForm": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "expired_secs": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "file_path": {
+                    "type": "string",
+                    "example": "incoming/report.pdf"
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "incoming/"
+                },
+                "max_size": {
+                    "type": "integer",
+                    "example": 104857600
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "PUT",
+                        "POST"
+                    ],
+                    "example": "POST"
+                }
+            }
+        },
+        "httpserv.RemoveFileForm": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string",
+                    "example": "test-file.docx"
+                }
+            }
+        },
+        "httpserv.RemoveFolderForm": {
+            "type": "object",
+            "properties": {
+                "folder_path": {
+                    "type": "string",
+                    "example": "reports/2024"
                 }
             }
         },
@@ -590,6 +4615,22 @@ const docTemplate = `{
                 }
             }
         },
+        "httpserv.RestoreTrashForm": {
+            "type": "object",
+            "properties": {
+                "on_conflict": {
+                    "type": "string",
+                    "default": "fail",
+                    "enum": [
+                        "overwrite",
+                        "skip",
+                        "rename",
+                        "fail"
+                    ],
+                    "example": "rename"
+                }
+            }
+        },
         "httpserv.ServerErrorForm": {
             "type": "object",
             "properties": {
@@ -619,6 +4660,383 @@ const docTemplate = `{
                     "example": "test-file.docx"
                 }
             }
+        },
+        "httpserv.ShareFilesForm": {
+            "type": "object",
+            "properties": {
+                "expired_secs": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "file_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024/supply.docx",
+                        "2024/invoice.pdf"
+                    ]
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "2024/"
+                }
+            }
+        },
+        "httpserv.ShareLinkCreatedForm": {
+            "type": "object",
+            "properties": {
+                "link": {
+                    "$ref": "#/definitions/share.Link"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://docs.example.com/s/q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"
+                }
+            }
+        },
+        "httpserv.ShareLinksForm": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/share.Link"
+                    }
+                }
+            }
+        },
+        "httpserv.StatFileForm": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string",
+                    "example": "test-file.docx"
+                }
+            }
+        },
+        "httpserv.UploadFileResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to upload file"
+                },
+                "etag": {
+                    "type": "string",
+                    "example": "d41d8cd98f00b204e9800998ecf8427e"
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "test-file.docx"
+                },
+                "file_path": {
+                    "type": "string",
+                    "example": "test-file.docx"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "uploaded",
+                        "failed",
+                        "skipped",
+                        "rolled_back"
+                    ],
+                    "example": "uploaded"
+                }
+            }
+        },
+        "httpserv.UploadFilesForm": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserv.UploadFileResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 207
+                },
+                "uploaded": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "httpserv.UploadLinkCreatedForm": {
+            "type": "object",
+            "properties": {
+                "link": {
+                    "$ref": "#/definitions/share.UploadLink"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://docs.example.com/u/q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"
+                }
+            }
+        },
+        "httpserv.UploadLinkInfoForm": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-08T12:01:01Z"
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "incoming/acme/"
+                },
+                "max_size": {
+                    "type": "integer",
+                    "example": 104857600
+                },
+                "remaining_files": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "httpserv.UploadLinksForm": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/share.UploadLink"
+                    }
+                }
+            }
+        },
+        "httpserv.UploadSessionForm": {
+            "type": "object",
+            "properties": {
+                "file_path": {
+                    "type": "string",
+                    "example": "archives/field-data.zip"
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cloud.UploadPart"
+                    }
+                },
+                "upload_id": {
+                    "type": "string",
+                    "example": "eyJwIjoiYXJjaGl2ZXMvZmllbGQtZGF0YS56aXAifQ"
+                }
+            }
+        },
+        "rbac.Binding": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "contracts"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "legal"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f2b9c1e4a7d3e80"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "2024/"
+                },
+                "role": {
+                    "enum": [
+                        "reader",
+                        "writer",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/rbac.Role"
+                        }
+                    ],
+                    "example": "writer"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "rbac.Role": {
+            "type": "string",
+            "enum": [
+                "reader",
+                "writer",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "RoleReader",
+                "RoleWriter",
+                "RoleAdmin"
+            ]
+        },
+        "share.Link": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "contracts"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:01:01Z"
+                },
+                "creator": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "downloads": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-08T12:01:01Z"
+                },
+                "file_path": {
+                    "type": "string",
+                    "example": "2024/supply.docx"
+                },
+                "file_paths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024/supply.docx",
+                        "2024/invoice.pdf"
+                    ]
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "2024/"
+                },
+                "has_password": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "string",
+                    "example": "4b1e7d2a9c3f5e80"
+                },
+                "max_downloads": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2025-01-02T09:00:00Z"
+                }
+            }
+        },
+        "share.UploadLink": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "contracts"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:01:01Z"
+                },
+                "creator": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-08T12:01:01Z"
+                },
+                "folder": {
+                    "type": "string",
+                    "example": "incoming/acme/"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "8d3a1f6b2c7e4a90"
+                },
+                "max_files": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_size": {
+                    "type": "integer",
+                    "example": 104857600
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2025-01-02T09:00:00Z"
+                },
+                "uploads": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "trash.EntriesPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trash.Entry"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "trash.Entry": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "original_path": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
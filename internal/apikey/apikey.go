@@ -0,0 +1,390 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"docs-hub/internal/auth"
+)
+
+type Operation string
+
+const (
+	OperationRead  Operation = "read"
+	OperationWrite Operation = "write"
+	OperationAdmin Operation = "admin"
+)
+
+// AnyBucket scopes key to all buckets.
+const AnyBucket = "*"
+
+const (
+	keyPrefix       = "dhk_"
+	subjectPrefix   = "apikey:"
+	keySecretLength = 24
+	// lastUsedPrecision limits how often store file is rewritten
+	// to track usage of keys.
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	ErrKeyNotFound = errors.New("api key does not exist")
+	ErrInvalidKey  = errors.New("invalid api key")
+)
+
+// Key grants operations on buckets to services which call API
+// without user, only hash of key value is kept. Key with prefixes
+// is scoped to objects under these folders of its buckets only.
+type Key struct {
+	ID         string      `json:"id" example:"7c4a9e2f1b3d5a60"`
+	Name       string      `json:"name" example:"indexer"`
+	Hash       string      `json:"hash,omitempty" swaggerignore:"true"`
+	Buckets    []string    `json:"buckets" example:"contracts,archive"`
+	Prefixes   []string    `json:"prefixes,omitempty" example:"2024/"`
+	Operations []Operation `json:"operations" example:"read,write" enums:"read,write,admin"`
+	CreatedAt  time.Time   `json:"created_at" example:"2025-01-01T12:01:01Z"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty" example:"2026-01-01T12:01:01Z"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty" example:"2025-03-01T08:30:00Z"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty" example:"2025-04-01T10:00:00Z"`
+}
+
+func (k *Key) check() error {
+	if k.Name == "" {
+		return errors.New("key name must not be empty")
+	}
+
+	if len(k.Buckets) == 0 {
+		return errors.New("key must be scoped to at least one bucket")
+	}
+
+	if len(k.Operations) == 0 {
+		return errors.New("key must be scoped to at least one operation")
+	}
+
+	for _, operation := range k.Operations {
+		switch operation {
+		case OperationRead, OperationWrite, OperationAdmin:
+		default:
+			return fmt.Errorf("unknown operation: %s", operation)
+		}
+	}
+
+	for _, prefix := range k.Prefixes {
+		if normalizePrefix(prefix) == "" {
+			return errors.New("key prefix must not be empty, omit prefixes to scope key to whole buckets")
+		}
+	}
+
+	return nil
+}
+
+// normalizePrefix makes prefix folder key, so that prefix "2024"
+// covers "2024/a.txt" but not "2024-secret/a.txt".
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+
+	return prefix + "/"
+}
+
+// IsActive reports whether key is neither revoked nor expired at moment.
+func (k *Key) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Allows reports whether key is scoped to operation on object path
+// of bucket, empty path means whole bucket including its settings.
+func (k *Key) Allows(bucket, objPath string, operation Operation) bool {
+	return k.CoversBucket(bucket) && k.hasOperation(operation) && k.coversPath(objPath)
+}
+
+// CanBrowse reports whether key may see object or folder in listing:
+// it is readable itself or it is folder leading to key prefix.
+func (k *Key) CanBrowse(bucket, objPath string) bool {
+	if k.Allows(bucket, objPath, OperationRead) {
+		return true
+	}
+	if !k.CoversBucket(bucket) || !k.hasOperation(OperationRead) {
+		return false
+	}
+
+	folderKey := normalizePrefix(objPath)
+	for _, prefix := range k.Prefixes {
+		if strings.HasPrefix(prefix, folderKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *Key) CoversBucket(bucket string) bool {
+	for _, keyBucket := range k.Buckets {
+		if keyBucket == AnyBucket || keyBucket == bucket {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *Key) coversPath(objPath string) bool {
+	if len(k.Prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range k.Prefixes {
+		if strings.HasPrefix(objPath, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *Key) hasOperation(operation Operation) bool {
+	for _, keyOperation := range k.Operations {
+		if keyOperation == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Identity returns caller identity which requests are made on behalf of.
+func (k *Key) Identity() *auth.Identity {
+	return &auth.Identity{Subject: subjectPrefix + k.ID}
+}
+
+type storeFile struct {
+	Keys []*Key `json:"keys"`
+}
+
+type Store struct {
+	config *Config
+
+	mu   sync.Mutex
+	keys []*Key
+	// isUsageSaving is set while usage of keys is waiting to be saved.
+	isUsageSaving bool
+}
+
+// New loads store file, missing file means there are no keys yet.
+func New(config *Config) (*Store, error) {
+	store := &Store{config: config, keys: make([]*Key, 0)}
+	if !config.Enabled {
+		return store, nil
+	}
+
+	if config.StoreFile == "" {
+		return nil, errors.New("store file is required for api keys")
+	}
+
+	data, err := os.ReadFile(config.StoreFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := &storeFile{}
+	if err = json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys file %s: %w", config.StoreFile, err)
+	}
+
+	if file.Keys != nil {
+		store.keys = file.Keys
+	}
+
+	return store, nil
+}
+
+func (s *Store) IsEnabled() bool {
+	return s.config.Enabled
+}
+
+// Keys returns all keys including revoked ones, without their hashes.
+func (s *Store) Keys() []*Key {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key.withoutHash())
+	}
+
+	return keys
+}
+
+// Create generates value for key and stores it, value is returned
+// to caller only here and can not be restored later.
+func (s *Store) Create(key *Key) (*Key, string, error) {
+	if err := key.check(); err != nil {
+		return nil, "", err
+	}
+
+	keyID, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := randomHex(keySecretLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	value := keyPrefix + secret
+	for index, prefix := range key.Prefixes {
+		key.Prefixes[index] = normalizePrefix(prefix)
+	}
+	key.ID = keyID
+	key.Hash = hashValue(value)
+	key.CreatedAt = time.Now().UTC()
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.save(append(s.keys, key)); err != nil {
+		return nil, "", err
+	}
+
+	return key.withoutHash(), value, nil
+}
+
+// Revoke disables key, it is kept in store to show when it was revoked.
+func (s *Store) Revoke(keyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.ID != keyID {
+			continue
+		}
+
+		if key.RevokedAt == nil {
+			now := time.Now().UTC()
+			key.RevokedAt = &now
+		}
+
+		return s.save(s.keys)
+	}
+
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+}
+
+// IsBootstrapToken reports whether token is configured bootstrap token.
+func (s *Store) IsBootstrapToken(token string) bool {
+	bootstrap := s.config.BootstrapToken
+	if bootstrap == "" || token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(bootstrap)) == 1
+}
+
+// Authenticate finds active key by its value and tracks its usage.
+// Usage is saved in background, so failed write never rejects key.
+func (s *Store) Authenticate(value string) (*Key, error) {
+	hash := hashValue(value)
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.Hash != hash {
+			continue
+		}
+
+		if !key.IsActive(now) {
+			return nil, fmt.Errorf("%w: key is revoked or expired", ErrInvalidKey)
+		}
+
+		if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedPrecision {
+			key.LastUsedAt = &now
+			if !s.isUsageSaving {
+				s.isUsageSaving = true
+				go s.saveUsage()
+			}
+		}
+
+		return key.withoutHash(), nil
+	}
+
+	return nil, ErrInvalidKey
+}
+
+// saveUsage writes last usage of keys into store file, usage
+// is kept in memory only if it fails until next write.
+func (s *Store) saveUsage() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isUsageSaving = false
+	if err := s.save(s.keys); err != nil {
+		log.Println("failed to save usage of api keys: ", err)
+	}
+}
+
+func (k *Key) withoutHash() *Key {
+	key := *k
+	key.Hash = ""
+	return &key
+}
+
+// save writes keys into store file and applies them,
+// must be called with lock held.
+func (s *Store) save(keys []*Key) error {
+	data, err := json.MarshalIndent(&storeFile{Keys: keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	storePath := s.config.StoreFile
+	if err = os.MkdirAll(filepath.Dir(storePath), 0o755); err != nil {
+		return err
+	}
+
+	tmpPath := storePath + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpPath, storePath); err != nil {
+		return err
+	}
+
+	s.keys = keys
+	return nil
+}
+
+func hashValue(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+func randomHex(length int) (string, error) {
+	data := make([]byte, length)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
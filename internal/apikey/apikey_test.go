@@ -0,0 +1,205 @@
+package apikey
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := New(&Config{Enabled: true, StoreFile: filepath.Join(t.TempDir(), "keys.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Usage is saved in background, it must be written before
+	// temporary directory is removed.
+	t.Cleanup(func() {
+		for {
+			store.mu.Lock()
+			isSaving := store.isUsageSaving
+			store.mu.Unlock()
+			if !isSaving {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	return store
+}
+
+func TestKeyAllows(t *testing.T) {
+	key := &Key{
+		Buckets:    []string{"contracts"},
+		Prefixes:   []string{"2024/"},
+		Operations: []Operation{OperationRead, OperationWrite},
+	}
+	bucketKey := &Key{Buckets: []string{AnyBucket}, Operations: []Operation{OperationAdmin}}
+
+	tests := []struct {
+		name      string
+		key       *Key
+		bucket    string
+		objPath   string
+		operation Operation
+		expected  bool
+	}{
+		{name: "object under prefix", key: key, bucket: "contracts", objPath: "2024/a.txt", operation: OperationWrite, expected: true},
+		{name: "prefix folder key itself", key: key, bucket: "contracts", objPath: "2024/", operation: OperationRead, expected: true},
+		{name: "object outside prefix", key: key, bucket: "contracts", objPath: "2023/a.txt", operation: OperationRead, expected: false},
+		{name: "prefix is not path boundary", key: key, bucket: "contracts", objPath: "2024-secret/x", operation: OperationRead, expected: false},
+		{name: "whole bucket", key: key, bucket: "contracts", objPath: "", operation: OperationRead, expected: false},
+		{name: "operation out of scope", key: key, bucket: "contracts", objPath: "2024/a.txt", operation: OperationAdmin, expected: false},
+		{name: "other bucket", key: key, bucket: "archive", objPath: "2024/a.txt", operation: OperationRead, expected: false},
+		{name: "key without prefixes", key: bucketKey, bucket: "archive", objPath: "", operation: OperationAdmin, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if allowed := test.key.Allows(test.bucket, test.objPath, test.operation); allowed != test.expected {
+				t.Fatalf("Allows(%s, %s, %s) = %v, expected %v", test.bucket, test.objPath, test.operation, allowed, test.expected)
+			}
+		})
+	}
+}
+
+func TestKeyCanBrowse(t *testing.T) {
+	key := &Key{Buckets: []string{"contracts"}, Prefixes: []string{"2024/legal/"}, Operations: []Operation{OperationRead}}
+
+	tests := []struct {
+		name     string
+		key      *Key
+		objPath  string
+		expected bool
+	}{
+		{name: "bucket root leads to prefix", key: key, objPath: "", expected: true},
+		{name: "parent folder leads to prefix", key: key, objPath: "2024/", expected: true},
+		{name: "parent folder without slash", key: key, objPath: "2024", expected: true},
+		{name: "readable file", key: key, objPath: "2024/legal/nda.pdf", expected: true},
+		{name: "sibling folder", key: key, objPath: "2024/sales/", expected: false},
+		{name: "similar folder", key: key, objPath: "2024/legal-old/", expected: false},
+		{
+			name:     "key without read",
+			key:      &Key{Buckets: []string{"contracts"}, Prefixes: []string{"2024/"}, Operations: []Operation{OperationWrite}},
+			objPath:  "",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if allowed := test.key.CanBrowse("contracts", test.objPath); allowed != test.expected {
+				t.Fatalf("CanBrowse(%s) = %v, expected %v", test.objPath, allowed, test.expected)
+			}
+		})
+	}
+}
+
+func TestCreateKey(t *testing.T) {
+	store := newTestStore(t)
+
+	tests := []struct {
+		name string
+		key  *Key
+	}{
+		{name: "empty name", key: &Key{Buckets: []string{"b1"}, Operations: []Operation{OperationRead}}},
+		{name: "no buckets", key: &Key{Name: "k", Operations: []Operation{OperationRead}}},
+		{name: "no operations", key: &Key{Name: "k", Buckets: []string{"b1"}}},
+		{name: "unknown operation", key: &Key{Name: "k", Buckets: []string{"b1"}, Operations: []Operation{"delete"}}},
+		{name: "empty prefix", key: &Key{Name: "k", Buckets: []string{"b1"}, Prefixes: []string{"/"}, Operations: []Operation{OperationRead}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := store.Create(test.key); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	created, value, err := store.Create(&Key{
+		Name:       "indexer",
+		Buckets:    []string{"b1"},
+		Prefixes:   []string{"/2024"},
+		Operations: []Operation{OperationRead},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.Hash != "" || value == "" {
+		t.Fatalf("unexpected created key %+v, value %q", created, value)
+	}
+	if created.Prefixes[0] != "2024/" {
+		t.Fatalf("prefix is not folder key: %q", created.Prefixes[0])
+	}
+
+	reloaded, err := New(store.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := reloaded.Keys(); len(keys) != 1 || keys[0].ID != created.ID || keys[0].Hash != "" {
+		t.Fatalf("unexpected stored keys %+v", keys)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	store := newTestStore(t)
+
+	active, activeValue, err := store.Create(&Key{Name: "active", Buckets: []string{"b1"}, Operations: []Operation{OperationRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, revokedValue, err := store.Create(&Key{Name: "revoked", Buckets: []string{"b1"}, Operations: []Operation{OperationRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Revoke(revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(-time.Minute)
+	_, expiredValue, err := store.Create(&Key{Name: "expired", Buckets: []string{"b1"}, Operations: []Operation{OperationRead}, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := store.Authenticate(activeValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != active.ID || key.Identity().Subject != "apikey:"+active.ID {
+		t.Fatalf("unexpected key %+v", key)
+	}
+
+	for _, value := range []string{revokedValue, expiredValue, "dhk_unknown"} {
+		if _, err = store.Authenticate(value); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("key %s is accepted: %v", value, err)
+		}
+	}
+
+	if err = store.Revoke("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("unexpected error of missing key: %v", err)
+	}
+}
+
+func TestIsBootstrapToken(t *testing.T) {
+	store, err := New(&Config{BootstrapToken: "bootstrap"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !store.IsBootstrapToken("bootstrap") || store.IsBootstrapToken("other") || store.IsBootstrapToken("") {
+		t.Fatal("unexpected bootstrap token check")
+	}
+
+	store, err = New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.IsBootstrapToken("") {
+		t.Fatal("empty token is accepted without bootstrap token")
+	}
+}
@@ -0,0 +1,11 @@
+package apikey
+
+type Config struct {
+	Enabled bool
+	// StoreFile is JSON file keeping hashes and scopes of keys,
+	// key values themselves are shown only once when key is created.
+	StoreFile string
+	// BootstrapToken lets keys be managed while access control is
+	// disabled, keys can not be managed then if it is empty.
+	BootstrapToken string
+}
@@ -0,0 +1,327 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	OperationCreateBucket     = "bucket.create"
+	OperationRemoveBucket     = "bucket.remove"
+	OperationPurgeBucket      = "bucket.purge"
+	OperationSetVersioning    = "bucket.versioning"
+	OperationSetLifecycle     = "bucket.lifecycle"
+	OperationUpload           = "file.upload"
+	OperationDownload         = "file.download"
+	OperationCopy             = "file.copy"
+	OperationMove             = "file.move"
+	OperationRemove           = "file.remove"
+	OperationShare            = "file.share"
+	OperationRevokeShare      = "share.revoke"
+	OperationPresignUpload    = "file.presign_upload"
+	OperationCreateUploadLink = "upload_link.create"
+	OperationRevokeUploadLink = "upload_link.revoke"
+	OperationSetExpiry        = "file.expiry"
+	OperationCreateFolder     = "folder.create"
+	OperationCopyFolder       = "folder.copy"
+	OperationMoveFolder       = "folder.move"
+	OperationRemoveFolder     = "folder.remove"
+	OperationRestoreVersion   = "version.restore"
+	OperationRemoveVersion    = "version.remove"
+	OperationRestoreTrash     = "trash.restore"
+	OperationEmptyTrash       = "trash.empty"
+	OperationAuthFailure      = "auth.failure"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+const (
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+	maxEventLineSize  = 1024 * 1024
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Event is record of operation, events are chained by hashes so that
+// changed or removed event breaks chain of all events after it.
+type Event struct {
+	Time      time.Time `json:"time" example:"2025-01-01T12:01:01Z"`
+	RequestID string    `json:"request_id" example:"Jf3bQ2sLzWc8XyN1hKpR4tVmA6dE9uGo"`
+	Actor     string    `json:"actor" example:"alice"`
+	ClientIP  string    `json:"client_ip" example:"10.0.0.12"`
+	Operation string    `json:"operation" example:"file.download"`
+	Bucket    string    `json:"bucket" example:"contracts"`
+	Path      string    `json:"path,omitempty" example:"2024/supply.docx"`
+	DstBucket string    `json:"dst_bucket,omitempty" example:"archive"`
+	DstPath   string    `json:"dst_path,omitempty" example:"2024/supply.docx"`
+	Size      int64     `json:"size" example:"1024"`
+	Result    string    `json:"result" example:"success" enums:"success,failure"`
+	Status    int       `json:"status" example:"200"`
+	Error     string    `json:"error,omitempty" example:"access denied"`
+	PrevHash  string    `json:"prev_hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Hash      string    `json:"hash" example:"60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"`
+}
+
+// computeHash hashes event content together with hash of previous event.
+func (e *Event) computeHash() (string, error) {
+	content := *e
+	content.Hash = ""
+
+	data, err := json.Marshal(&content)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Filter selects events of query, zero fields match all events.
+type Filter struct {
+	From      time.Time
+	To        time.Time
+	Actor     string
+	Bucket    string
+	Operation string
+	Limit     int
+	// Cursor is next cursor of previous page, events older
+	// than last event of that page are returned.
+	Cursor string
+}
+
+// EventsPage is page of events, newest event goes first.
+type EventsPage struct {
+	Events     []*Event `json:"events"`
+	NextCursor string   `json:"next_cursor,omitempty" example:"1024"`
+}
+
+func (f *Filter) matches(event *Event) bool {
+	switch {
+	case !f.From.IsZero() && event.Time.Before(f.From):
+		return false
+	case !f.To.IsZero() && !event.Time.Before(f.To):
+		return false
+	case f.Actor != "" && event.Actor != f.Actor:
+		return false
+	case f.Bucket != "" && event.Bucket != f.Bucket && event.DstBucket != f.Bucket:
+		return false
+	case f.Operation != "" && event.Operation != f.Operation:
+		return false
+	default:
+		return true
+	}
+}
+
+// Log appends events to log file, file is only appended to
+// and never rewritten by service.
+type Log struct {
+	config *Config
+
+	mu       sync.Mutex
+	file     *os.File
+	lastHash string
+}
+
+func New(config *Config) (*Log, error) {
+	auditLog := &Log{config: config}
+	if !config.Enabled {
+		return auditLog, nil
+	}
+
+	if config.LogFile == "" {
+		return nil, errors.New("log file is required for audit")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.LogFile), 0o755); err != nil {
+		return nil, err
+	}
+
+	// Chain is continued from last event written before restart.
+	err := auditLog.scan(func(event *Event) bool {
+		auditLog.lastHash = event.Hash
+		return true
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(config.LogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	auditLog.file = file
+
+	return auditLog, nil
+}
+
+func (l *Log) IsEnabled() bool {
+	return l.config.Enabled
+}
+
+// Record chains event to previous one and appends it to log file.
+func (l *Log) Record(event *Event) error {
+	if !l.config.Enabled {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.PrevHash = l.lastHash
+	hash, err := event.computeHash()
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err = l.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	if err = l.file.Sync(); err != nil {
+		return err
+	}
+
+	l.lastHash = hash
+	return nil
+}
+
+// Query returns page of events matched by filter, newest first. Cursor
+// is line of event in log file, lines are stable as file is only appended.
+func (l *Log) Query(filter *Filter) (*EventsPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	limit = min(limit, maxQueryLimit)
+
+	beforeLine := 0
+	if filter.Cursor != "" {
+		var err error
+		if beforeLine, err = strconv.Atoi(filter.Cursor); err != nil || beforeLine < 1 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, filter.Cursor)
+		}
+	}
+
+	type lineEvent struct {
+		line  int
+		event *Event
+	}
+
+	// Newest limit+1 matched events are kept while log is read from
+	// its start, extra event tells that there is next page.
+	matched := make([]lineEvent, 0, limit+1)
+	line := 0
+	err := l.scan(func(event *Event) bool {
+		line++
+		if beforeLine > 0 && line >= beforeLine {
+			return false
+		}
+
+		if filter.matches(event) {
+			matched = append(matched, lineEvent{line: line, event: event})
+			if len(matched) > limit+1 {
+				matched = matched[1:]
+			}
+		}
+		return true
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	page := &EventsPage{Events: make([]*Event, 0, len(matched))}
+	if len(matched) > limit {
+		matched = matched[1:]
+		page.NextCursor = strconv.Itoa(matched[0].line)
+	}
+
+	for index := len(matched) - 1; index >= 0; index-- {
+		page.Events = append(page.Events, matched[index].event)
+	}
+
+	return page, nil
+}
+
+// Verify checks hash chain of all events and returns number of
+// checked events, error points to first event which breaks chain.
+func (l *Log) Verify() (int, error) {
+	checked := 0
+	prevHash := ""
+
+	var chainErr error
+	err := l.scan(func(event *Event) bool {
+		hash, err := event.computeHash()
+		if err != nil {
+			chainErr = err
+			return false
+		}
+
+		if event.PrevHash != prevHash || event.Hash != hash {
+			chainErr = fmt.Errorf("event %d of request %s breaks hash chain", checked+1, event.RequestID)
+			return false
+		}
+
+		prevHash = event.Hash
+		checked++
+		return true
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return checked, err
+	}
+
+	return checked, chainErr
+}
+
+// scan reads events of log file until handle returns false.
+func (l *Log) scan(handle func(event *Event) bool) error {
+	file, err := os.Open(l.config.LogFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		event := &Event{}
+		if err = json.Unmarshal(scanner.Bytes(), event); err != nil {
+			return fmt.Errorf("failed to parse event at line %d: %w", line, err)
+		}
+
+		if !handle(event) {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (l *Log) Close() error {
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Close()
+}
@@ -0,0 +1,294 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEventsCount = 4
+
+// newTestLog records chain of events into log file in temp directory.
+func newTestLog(t *testing.T) *Log {
+	t.Helper()
+
+	config := &Config{Enabled: true, LogFile: filepath.Join(t.TempDir(), "audit.log")}
+	auditLog, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	t.Cleanup(func() { _ = auditLog.Close() })
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for index := range testEventsCount {
+		event := &Event{
+			Time:      start.Add(time.Duration(index) * time.Minute),
+			RequestID: fmt.Sprintf("req-%d", index+1),
+			Actor:     "alice",
+			Operation: OperationDownload,
+			Bucket:    "contracts",
+			Path:      fmt.Sprintf("file-%d.txt", index+1),
+			Size:      int64(100 * (index + 1)),
+			Result:    ResultSuccess,
+			Status:    200,
+		}
+		if err = auditLog.Record(event); err != nil {
+			t.Fatalf("failed to record event: %v", err)
+		}
+	}
+
+	return auditLog
+}
+
+func readLines(t *testing.T, auditLog *Log) [][]byte {
+	t.Helper()
+
+	data, err := os.ReadFile(auditLog.config.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+}
+
+func writeLines(t *testing.T, auditLog *Log, lines [][]byte) {
+	t.Helper()
+
+	data := append(bytes.Join(lines, []byte("\n")), '\n')
+	if err := os.WriteFile(auditLog.config.LogFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(lines [][]byte) [][]byte
+		isBroken   bool
+		checked    int
+		errContent string
+	}{
+		{
+			name:    "untouched log",
+			tamper:  func(lines [][]byte) [][]byte { return lines },
+			checked: testEventsCount,
+		},
+		{
+			name: "changed field",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte(`"size":200`), []byte(`"size":1`), 1)
+				return lines
+			},
+			isBroken:   true,
+			checked:    1,
+			errContent: "event 2 of request req-2",
+		},
+		{
+			name: "changed actor and rehashed event",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[2] = bytes.Replace(lines[2], []byte(`"actor":"alice"`), []byte(`"actor":"bob"`), 1)
+				return rehashLine(t, lines, 2)
+			},
+			isBroken:   true,
+			checked:    3,
+			errContent: "event 4 of request req-4",
+		},
+		{
+			name: "removed event",
+			tamper: func(lines [][]byte) [][]byte {
+				return append(lines[:1:1], lines[2:]...)
+			},
+			isBroken:   true,
+			checked:    1,
+			errContent: "event 2 of request req-3",
+		},
+		{
+			name: "swapped events",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[0], lines[1] = lines[1], lines[0]
+				return lines
+			},
+			isBroken:   true,
+			checked:    0,
+			errContent: "event 1 of request req-2",
+		},
+		{
+			name: "malformed line",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[3] = []byte("{not json")
+				return lines
+			},
+			isBroken:   true,
+			checked:    3,
+			errContent: "line 4",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			auditLog := newTestLog(t)
+			writeLines(t, auditLog, test.tamper(readLines(t, auditLog)))
+
+			checked, err := auditLog.Verify()
+			if checked != test.checked {
+				t.Errorf("checked %d events, expected %d", checked, test.checked)
+			}
+			if !test.isBroken {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.errContent) {
+				t.Fatalf("expected error containing %q, got %v", test.errContent, err)
+			}
+		})
+	}
+}
+
+// rehashLine recomputes hash of changed event like forger would do,
+// chain is still broken by next event which refers to original hash.
+func rehashLine(t *testing.T, lines [][]byte, index int) [][]byte {
+	t.Helper()
+
+	event := &Event{}
+	if err := json.Unmarshal(lines[index], event); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := event.computeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Hash = hash
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines[index] = data
+
+	return lines
+}
+
+func TestRecordContinuesChainAfterReopen(t *testing.T) {
+	auditLog := newTestLog(t)
+	if err := auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := New(auditLog.config)
+	if err != nil {
+		t.Fatalf("failed to reopen audit log: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+
+	event := &Event{RequestID: "req-after-restart", Operation: OperationRemove, Result: ResultSuccess}
+	if err = reopened.Record(event); err != nil {
+		t.Fatal(err)
+	}
+
+	checked, err := reopened.Verify()
+	if err != nil || checked != testEventsCount+1 {
+		t.Fatalf("verified %d events with error %v, expected %d", checked, err, testEventsCount+1)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	auditLog := newTestLog(t)
+	other := &Event{Time: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC), RequestID: "req-bob", Actor: "bob", Operation: OperationRemove}
+	if err := auditLog.Record(other); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   *Filter
+		expected string
+	}{
+		{name: "all events", filter: &Filter{}, expected: "req-bob,req-4,req-3,req-2,req-1"},
+		{name: "actor", filter: &Filter{Actor: "alice"}, expected: "req-4,req-3,req-2,req-1"},
+		{name: "operation", filter: &Filter{Operation: OperationRemove}, expected: "req-bob"},
+		{
+			name:     "time range",
+			filter:   &Filter{From: time.Date(2025, 1, 1, 12, 1, 0, 0, time.UTC), To: time.Date(2025, 1, 1, 12, 3, 0, 0, time.UTC)},
+			expected: "req-3,req-2",
+		},
+		{name: "other bucket", filter: &Filter{Bucket: "archive"}, expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := auditLog.Query(test.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := requestIDs(page.Events); ids != test.expected {
+				t.Fatalf("events %s, expected %s", ids, test.expected)
+			}
+			if page.NextCursor != "" {
+				t.Fatalf("unexpected next cursor %s", page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestQueryPages(t *testing.T) {
+	auditLog := newTestLog(t)
+
+	pages := make([]string, 0)
+	filter := &Filter{Actor: "alice", Limit: 3}
+	for len(pages) < testEventsCount {
+		page, err := auditLog.Query(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, requestIDs(page.Events))
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	if strings.Join(pages, "|") != "req-4,req-3,req-2|req-1" {
+		t.Fatalf("unexpected pages %v", pages)
+	}
+
+	// Events recorded after first page do not shift next pages.
+	page, err := auditLog.Query(&Filter{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = auditLog.Record(&Event{RequestID: "req-5", Actor: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	page, err = auditLog.Query(&Filter{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := requestIDs(page.Events); ids != "req-2,req-1" || page.NextCursor != "" {
+		t.Fatalf("second page %s, next cursor %q", ids, page.NextCursor)
+	}
+
+	for _, cursor := range []string{"x", "0", "-1"} {
+		if _, err = auditLog.Query(&Filter{Cursor: cursor}); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("cursor %q: unexpected error %v", cursor, err)
+		}
+	}
+}
+
+func requestIDs(events []*Event) string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.RequestID)
+	}
+
+	return strings.Join(ids, ",")
+}
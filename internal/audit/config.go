@@ -0,0 +1,7 @@
+package audit
+
+type Config struct {
+	Enabled bool
+	// LogFile is JSON lines file events are appended to.
+	LogFile string
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+
+	groupsClaim = "groups"
+)
+
+// DefaultPublicRoutes are served without authentication unless
+// public routes are set by config.
+var DefaultPublicRoutes = []string{
+	"/health",
+	"/swagger/*",
+	"/cloud/:bucket/share/*",
+	"/s/:token",
+	"/s/:token/zip",
+	"/s/:token/file/*",
+	"/u/:token",
+	"/presigned/*",
+}
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Identity is authenticated caller of API.
+type Identity struct {
+	Subject string
+	Groups  []string
+}
+
+type Authenticator struct {
+	config       *Config
+	parser       *jwt.Parser
+	keyFunc      jwt.Keyfunc
+	publicRoutes map[string]bool
+}
+
+func New(config *Config) (*Authenticator, error) {
+	routes := config.PublicRoutes
+	if len(routes) == 0 {
+		routes = DefaultPublicRoutes
+	}
+
+	authenticator := &Authenticator{
+		config:       config,
+		parser:       &jwt.Parser{ValidMethods: []string{config.Algorithm}},
+		publicRoutes: make(map[string]bool, len(routes)),
+	}
+	for _, route := range routes {
+		authenticator.publicRoutes[route] = true
+	}
+
+	if !config.Enabled {
+		return authenticator, nil
+	}
+
+	keyFunc, err := newKeyFunc(config)
+	if err != nil {
+		return nil, err
+	}
+	authenticator.keyFunc = keyFunc
+
+	return authenticator, nil
+}
+
+func (a *Authenticator) IsEnabled() bool {
+	return a.config.Enabled
+}
+
+func (a *Authenticator) IsPublicRoute(routePath string) bool {
+	return a.publicRoutes[routePath]
+}
+
+// Authenticate verifies signature and claims of token and
+// returns identity of its subject, token must have expiry.
+func (a *Authenticator) Authenticate(tokenString string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(tokenString, claims, a.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	// Parser checks expiry only if token has it, token without
+	// expiry would be valid forever.
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrInvalidToken)
+	}
+
+	if a.config.Issuer != "" && !claims.VerifyIssuer(a.config.Issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if a.config.Audience != "" && !claims.VerifyAudience(a.config.Audience, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: subject is empty", ErrInvalidToken)
+	}
+
+	return &Identity{Subject: subject, Groups: claimStrings(claims[groupsClaim])}, nil
+}
+
+func newKeyFunc(config *Config) (jwt.Keyfunc, error) {
+	switch config.Algorithm {
+	case AlgorithmHS256:
+		if config.Secret == "" {
+			return nil, errors.New("secret is required for HS256 tokens")
+		}
+		secret := []byte(config.Secret)
+		return func(_ *jwt.Token) (interface{}, error) {
+			return secret, nil
+		}, nil
+	case AlgorithmRS256:
+		if config.JWKSFile != "" {
+			keys, err := loadJWKS(config.JWKSFile)
+			if err != nil {
+				return nil, err
+			}
+			return keys.keyFunc, nil
+		}
+
+		if config.PublicKeyFile == "" {
+			return nil, errors.New("public key or JWKS file is required for RS256 tokens")
+		}
+
+		keyData, err := os.ReadFile(config.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(keyData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %s: %w", config.PublicKeyFile, err)
+		}
+		return func(_ *jwt.Token) (interface{}, error) {
+			return publicKey, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown token algorithm: %s", config.Algorithm)
+	}
+}
+
+// claimStrings reads claim which is either string or list of strings.
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,308 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, keyID string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+func TestAuthenticateHS256(t *testing.T) {
+	authenticator, err := New(&Config{
+		Enabled:   true,
+		Algorithm: AlgorithmHS256,
+		Secret:    "secret",
+		Issuer:    "docs-hub",
+		Audience:  "api",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := func(changes jwt.MapClaims) jwt.MapClaims {
+		result := validClaims()
+		result["iss"], result["aud"] = "docs-hub", "api"
+		for name, value := range changes {
+			if value == nil {
+				delete(result, name)
+				continue
+			}
+			result[name] = value
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "valid", token: signHS256(t, "secret", claims(nil)), valid: true},
+		{name: "wrong secret", token: signHS256(t, "other", claims(nil))},
+		{name: "expired", token: signHS256(t, "secret", claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))},
+		{name: "no expiry", token: signHS256(t, "secret", claims(jwt.MapClaims{"exp": nil}))},
+		{name: "wrong issuer", token: signHS256(t, "secret", claims(jwt.MapClaims{"iss": "other"}))},
+		{name: "wrong audience", token: signHS256(t, "secret", claims(jwt.MapClaims{"aud": "other"}))},
+		{name: "empty subject", token: signHS256(t, "secret", claims(jwt.MapClaims{"sub": ""}))},
+		{name: "malformed", token: "not.a.token"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(test.token)
+			if !test.valid {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("expected invalid token, got identity %+v, error %v", identity, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity.Subject != "alice" {
+				t.Fatalf("subject %q", identity.Subject)
+			}
+		})
+	}
+}
+
+func TestAuthenticateGroups(t *testing.T) {
+	authenticator, err := New(&Config{Enabled: true, Algorithm: AlgorithmHS256, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		groups interface{}
+		result []string
+	}{
+		{name: "list", groups: []string{"dev", "ops"}, result: []string{"dev", "ops"}},
+		{name: "single", groups: "dev", result: []string{"dev"}},
+		{name: "missing", groups: nil, result: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := validClaims()
+			if test.groups != nil {
+				claims[groupsClaim] = test.groups
+			}
+
+			identity, err := authenticator.Authenticate(signHS256(t, "secret", claims))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(identity.Groups, test.result) {
+				t.Fatalf("groups %v, expected %v", identity.Groups, test.result)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsOtherAlgorithm(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := New(&Config{Enabled: true, Algorithm: AlgorithmHS256, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = authenticator.Authenticate(signRS256(t, key, "", validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("RS256 token is accepted by HS256 authenticator: %v", err)
+	}
+}
+
+func TestAuthenticateRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writeFile(t, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+
+	jwks, err := json.Marshal(map[string][]*jsonWebKey{"keys": {
+		{
+			KeyType: "RSA",
+			KeyID:   "k1",
+			Use:     "sig",
+			N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		},
+		{
+			KeyType: "RSA",
+			KeyID:   "k2",
+			Use:     "sig",
+			N:       base64.RawURLEncoding.EncodeToString(otherKey.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(otherKey.E)).Bytes()),
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := writeFile(t, "jwks.json", jwks)
+
+	tests := []struct {
+		name   string
+		config *Config
+		token  string
+		valid  bool
+	}{
+		{
+			name:   "public key",
+			config: &Config{PublicKeyFile: keyFile},
+			token:  signRS256(t, key, "", validClaims()),
+			valid:  true,
+		},
+		{
+			name:   "public key of other signer",
+			config: &Config{PublicKeyFile: keyFile},
+			token:  signRS256(t, otherKey, "", validClaims()),
+		},
+		{
+			name:   "JWKS key by id",
+			config: &Config{JWKSFile: jwksFile},
+			token:  signRS256(t, otherKey, "k2", validClaims()),
+			valid:  true,
+		},
+		{
+			name:   "JWKS key of other id",
+			config: &Config{JWKSFile: jwksFile},
+			token:  signRS256(t, otherKey, "k1", validClaims()),
+		},
+		{
+			name:   "JWKS token without id",
+			config: &Config{JWKSFile: jwksFile},
+			token:  signRS256(t, key, "", validClaims()),
+		},
+		{
+			name:   "HS256 token",
+			config: &Config{PublicKeyFile: keyFile},
+			token:  signHS256(t, "secret", validClaims()),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.Enabled, test.config.Algorithm = true, AlgorithmRS256
+			authenticator, err := New(test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			identity, err := authenticator.Authenticate(test.token)
+			if test.valid != (err == nil) {
+				t.Fatalf("valid %v, identity %+v, error %v", test.valid, identity, err)
+			}
+		})
+	}
+}
+
+func TestNewValidatesKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{name: "HS256 without secret", config: &Config{Enabled: true, Algorithm: AlgorithmHS256}},
+		{name: "RS256 without key", config: &Config{Enabled: true, Algorithm: AlgorithmRS256}},
+		{name: "missing key file", config: &Config{Enabled: true, Algorithm: AlgorithmRS256, PublicKeyFile: "missing.pem"}},
+		{name: "malformed JWKS", config: &Config{Enabled: true, Algorithm: AlgorithmRS256, JWKSFile: writeFile(t, "jwks.json", []byte(`{"keys":[]}`))}},
+		{name: "unknown algorithm", config: &Config{Enabled: true, Algorithm: "none"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := New(test.config); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	if _, err := New(&Config{}); err != nil {
+		t.Fatalf("disabled authentication needs no keys: %v", err)
+	}
+}
+
+func TestIsPublicRoute(t *testing.T) {
+	authenticator, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !authenticator.IsPublicRoute("/health") || authenticator.IsPublicRoute("/cloud/:bucket/files") {
+		t.Fatal("default public routes are not used")
+	}
+
+	authenticator, err = New(&Config{PublicRoutes: []string{"/metrics"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !authenticator.IsPublicRoute("/metrics") || authenticator.IsPublicRoute("/health") {
+		t.Fatal("configured public routes do not replace defaults")
+	}
+}
@@ -0,0 +1,15 @@
+package auth
+
+type Config struct {
+	Enabled bool
+	// Algorithm is signing method of accepted tokens: HS256 or RS256.
+	Algorithm     string
+	Secret        string
+	PublicKeyFile string
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+	// PublicRoutes are route paths, like they are registered into
+	// router, which are served without authentication.
+	PublicRoutes []string
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt"
+)
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+// jwkSet keeps RSA public keys of JWKS file by key id.
+type jwkSet map[string]*rsa.PublicKey
+
+func loadJWKS(filePath string) (jwkSet, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}{}
+	if err = json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file %s: %w", filePath, err)
+	}
+
+	keys := make(jwkSet, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.KeyType != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		publicKey, err := key.rsaPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s of JWKS file: %w", key.KeyID, err)
+		}
+		keys[key.KeyID] = publicKey
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS file %s has no RSA signing keys", filePath)
+	}
+
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(modulus) == 0 || len(exponent) == 0 || len(exponent) > 4 {
+		return nil, errors.New("invalid RSA key parameters")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+// keyFunc selects key by kid header of token, token without kid
+// is accepted only when set has single key.
+func (keys jwkSet) keyFunc(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	if keyID == "" && len(keys) == 1 {
+		for _, publicKey := range keys {
+			return publicKey, nil
+		}
+	}
+
+	publicKey, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", keyID)
+	}
+
+	return publicKey, nil
+}
@@ -0,0 +1,380 @@
+package cloud_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/cloud/inmemory"
+	"docs-hub/internal/cloud/localfs"
+)
+
+type testBackend struct {
+	name string
+	hub  *cloud.DocumentHub
+}
+
+// testBackends returns clouds which run without external services,
+// every test gets empty clouds with buckets b1 and b2.
+func testBackends(t *testing.T) []*testBackend {
+	t.Helper()
+
+	backends := []*testBackend{
+		{name: "inmemory", hub: inmemory.New(&cloud.CloudConfig{ShareSecret: "test"})},
+		{name: "localfs", hub: localfs.New(&cloud.CloudConfig{ShareSecret: "test", RootPath: t.TempDir()})},
+	}
+
+	for _, backend := range backends {
+		for _, bucket := range []string{"b1", "b2"} {
+			if err := backend.hub.Cloud.CreateBucket(context.Background(), bucket); err != nil {
+				t.Fatalf("%s: failed to create bucket %s: %v", backend.name, bucket, err)
+			}
+		}
+	}
+
+	return backends
+}
+
+func uploadText(t *testing.T, doc cloud.IDocument, bucket, filePath, content string) {
+	t.Helper()
+
+	err := doc.UploadFile(context.Background(), bucket, filePath, strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("failed to upload %s/%s: %v", bucket, filePath, err)
+	}
+}
+
+func readText(t *testing.T, doc cloud.IDocument, bucket, filePath string) (string, error) {
+	t.Helper()
+
+	fileData, err := doc.DownloadFile(context.Background(), bucket, filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = fileData.Close() }()
+
+	data, err := io.ReadAll(fileData)
+	return string(data), err
+}
+
+func TestCopyAndMoveFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		move      bool
+		srcBucket string
+		srcPath   string
+		dstBucket string
+		dstPath   string
+		// existing is content of destination before operation.
+		existing string
+	}{
+		{name: "copy in bucket", srcBucket: "b1", srcPath: "docs/a.txt", dstBucket: "b1", dstPath: "docs/copy.txt"},
+		{name: "copy to other bucket", srcBucket: "b1", srcPath: "docs/a.txt", dstBucket: "b2", dstPath: "in/a.txt"},
+		{name: "copy over existing", srcBucket: "b1", srcPath: "docs/a.txt", dstBucket: "b1", dstPath: "old.txt", existing: "old"},
+		{name: "move in bucket", move: true, srcBucket: "b1", srcPath: "docs/a.txt", dstBucket: "b1", dstPath: "moved/a.txt"},
+		{name: "move to other bucket", move: true, srcBucket: "b1", srcPath: "docs/a.txt", dstBucket: "b2", dstPath: "a.txt"},
+		{name: "move over existing", move: true, srcBucket: "b1", srcPath: "docs/a.txt", dstBucket: "b2", dstPath: "old.txt", existing: "old"},
+	}
+
+	for _, test := range tests {
+		for _, backend := range testBackends(t) {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				doc := backend.hub.Cloud
+				uploadText(t, doc, test.srcBucket, test.srcPath, "content")
+				if test.existing != "" {
+					uploadText(t, doc, test.dstBucket, test.dstPath, test.existing)
+				}
+
+				var err error
+				if test.move {
+					err = doc.MoveFile(ctx, test.srcBucket, test.srcPath, test.dstBucket, test.dstPath)
+				} else {
+					err = doc.CopyFile(ctx, test.srcBucket, test.srcPath, test.dstBucket, test.dstPath)
+				}
+				if err != nil {
+					t.Fatalf("operation failed: %v", err)
+				}
+
+				if content, err := readText(t, doc, test.dstBucket, test.dstPath); err != nil || content != "content" {
+					t.Fatalf("destination content %q, error %v", content, err)
+				}
+
+				_, err = doc.StatFile(ctx, test.srcBucket, test.srcPath)
+				if test.move && err == nil {
+					t.Fatal("moved file still exists at source")
+				}
+				if !test.move && err != nil {
+					t.Fatalf("copied file is missing at source: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestCopyMissingFile(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			doc := backend.hub.Cloud
+			if err := doc.CopyFile(ctx, "b1", "missing.txt", "b1", "copy.txt"); err == nil {
+				t.Fatal("copy of missing file succeeded")
+			}
+			if err := doc.MoveFile(ctx, "b1", "missing.txt", "b1", "moved.txt"); err == nil {
+				t.Fatal("move of missing file succeeded")
+			}
+			if _, err := doc.StatFile(ctx, "b1", "copy.txt"); err == nil {
+				t.Fatal("failed copy created destination")
+			}
+		})
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		filePath string
+		expected string
+		err      error
+	}{
+		{name: "free path", policy: cloud.ConflictFail, filePath: "docs/new.txt", expected: "docs/new.txt"},
+		{name: "overwrite", policy: cloud.ConflictOverwrite, filePath: "docs/a.txt", expected: "docs/a.txt"},
+		{name: "default policy", policy: "", filePath: "docs/a.txt", err: cloud.ErrFileExists},
+		{name: "skip", policy: cloud.ConflictSkip, filePath: "docs/a.txt", err: cloud.ErrFileSkipped},
+		{name: "fail", policy: cloud.ConflictFail, filePath: "docs/a.txt", err: cloud.ErrFileExists},
+		{name: "rename", policy: cloud.ConflictRename, filePath: "docs/a.txt", expected: "docs/a (2).txt"},
+		{name: "rename without extension", policy: cloud.ConflictRename, filePath: "docs/readme", expected: "docs/readme (1)"},
+	}
+
+	for _, backend := range testBackends(t) {
+		doc := backend.hub.Cloud
+		uploadText(t, doc, "b1", "docs/a.txt", "a")
+		uploadText(t, doc, "b1", "docs/a (1).txt", "a1")
+		uploadText(t, doc, "b1", "docs/readme", "r")
+
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				filePath, err := cloud.ResolveConflict(context.Background(), doc, "b1", test.filePath, test.policy)
+				if !errors.Is(err, test.err) {
+					t.Fatalf("error %v, expected %v", err, test.err)
+				}
+				if filePath != test.expected {
+					t.Fatalf("path %q, expected %q", filePath, test.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestNewFileWrites(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			doc := backend.hub.Cloud
+			uploadText(t, doc, "b1", "a.txt", "a")
+			uploadText(t, doc, "b1", "b.txt", "b")
+
+			err := doc.UploadNewFile(ctx, "b1", "a.txt", strings.NewReader("x"), 1)
+			if !errors.Is(err, cloud.ErrFileExists) {
+				t.Fatalf("upload: expected ErrFileExists, got %v", err)
+			}
+			if err = doc.CopyNewFile(ctx, "b1", "b.txt", "b1", "a.txt"); !errors.Is(err, cloud.ErrFileExists) {
+				t.Fatalf("copy: expected ErrFileExists, got %v", err)
+			}
+			if err = doc.MoveNewFile(ctx, "b1", "b.txt", "b1", "a.txt"); !errors.Is(err, cloud.ErrFileExists) {
+				t.Fatalf("move: expected ErrFileExists, got %v", err)
+			}
+			if content, err := readText(t, doc, "b1", "a.txt"); err != nil || content != "a" {
+				t.Fatalf("existing file has been changed: %q, %v", content, err)
+			}
+			if content, err := readText(t, doc, "b1", "b.txt"); err != nil || content != "b" {
+				t.Fatalf("source of failed move has been changed: %q, %v", content, err)
+			}
+
+			if err = doc.UploadNewFile(ctx, "b1", "c.txt", strings.NewReader("c"), 1); err != nil {
+				t.Fatal(err)
+			}
+			if err = doc.CopyNewFile(ctx, "b1", "c.txt", "b2", "c.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if err = doc.MoveNewFile(ctx, "b1", "b.txt", "b2", "docs/b.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = doc.StatFile(ctx, "b1", "b.txt"); err == nil {
+				t.Fatal("source of move still exists")
+			}
+			for _, filePath := range []string{"c.txt", "docs/b.txt"} {
+				if _, err = doc.StatFile(ctx, "b2", filePath); err != nil {
+					t.Fatalf("file %s has not been written: %v", filePath, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteByPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		policy   string
+		expected string
+		err      error
+	}{
+		{policy: cloud.ConflictRename, expected: "docs/a (1).txt"},
+		{policy: cloud.ConflictSkip, err: cloud.ErrFileSkipped},
+		{policy: cloud.ConflictFail, err: cloud.ErrFileExists},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			doc := testBackends(t)[0].hub.Cloud
+
+			// Other writer takes free path between conflict check and write.
+			attempts := 0
+			write := func(filePath string) error {
+				attempts++
+				if attempts == 1 {
+					uploadText(t, doc, "b1", filePath, "other")
+				}
+				return doc.UploadNewFile(ctx, "b1", filePath, strings.NewReader("a"), 1)
+			}
+
+			filePath, err := cloud.WriteByPolicy(ctx, doc, "b1", "docs/a.txt", test.policy, write)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error %v, expected %v", err, test.err)
+			}
+			if filePath != test.expected {
+				t.Fatalf("path %q, expected %q", filePath, test.expected)
+			}
+			if content, err := readText(t, doc, "b1", "docs/a.txt"); err != nil || content != "other" {
+				t.Fatalf("file of other writer has been changed: %q, %v", content, err)
+			}
+		})
+	}
+}
+
+func TestPurgeBucket(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			doc := backend.hub.Cloud
+			if err := doc.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+				t.Fatal(err)
+			}
+			uploadText(t, doc, "b1", "a.txt", "a1")
+			uploadText(t, doc, "b1", "a.txt", "a2")
+			uploadText(t, doc, "b1", "docs/b.txt", "b")
+			if err := doc.CreateFolder(ctx, "b1", "empty"); err != nil {
+				t.Fatal(err)
+			}
+			uploadID, err := doc.CreateUpload(ctx, "b1", "big.bin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			uploadText(t, doc, "b2", "a.txt", "other")
+
+			if err = doc.RemoveBucket(ctx, "b1"); !errors.Is(err, cloud.ErrBucketNotEmpty) {
+				t.Fatalf("expected ErrBucketNotEmpty, got %v", err)
+			}
+
+			if err = doc.PurgeBucket(ctx, "b1"); err != nil {
+				t.Fatal(err)
+			}
+			page, err := doc.GetFiles(ctx, "b1", &cloud.ListFilesParams{Recursive: true})
+			if err != nil || len(page.Items) != 0 {
+				t.Fatalf("purged bucket is not empty: %+v, error %v", page, err)
+			}
+			if versions, _ := doc.ListFileVersions(ctx, "b1", "a.txt"); len(versions) != 0 {
+				t.Fatalf("versions of purged file are left: %+v", versions)
+			}
+			if _, err = doc.ListUploadParts(ctx, "b1", "big.bin", uploadID); err == nil {
+				t.Fatal("incomplete upload of purged bucket is left")
+			}
+			if content, err := readText(t, doc, "b2", "a.txt"); err != nil || content != "other" {
+				t.Fatalf("file of other bucket has been purged: %q, %v", content, err)
+			}
+
+			if err = doc.RemoveBucket(ctx, "b1"); err != nil {
+				t.Fatalf("failed to remove purged bucket: %v", err)
+			}
+			if err = doc.PurgeBucket(ctx, "b1"); err == nil {
+				t.Fatal("missing bucket has been purged")
+			}
+		})
+	}
+}
+
+func TestRemoveExpired(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			doc := backend.hub.Cloud
+			now := time.Now()
+
+			err := doc.UploadExpired(ctx, "b1", "old.txt", now.Add(-time.Minute), strings.NewReader("old"), 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = doc.UploadExpired(ctx, "b1", "fresh.txt", now.Add(time.Hour), strings.NewReader("new"), 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			uploadText(t, doc, "b1", "kept.txt", "kept")
+
+			removed, err := doc.RemoveExpired(ctx, "b1", now)
+			if err != nil || removed != 1 {
+				t.Fatalf("removed %d files with error %v, expected 1", removed, err)
+			}
+
+			for filePath, isExpected := range map[string]bool{"old.txt": false, "fresh.txt": true, "kept.txt": true} {
+				if _, err = doc.StatFile(ctx, "b1", filePath); (err == nil) != isExpected {
+					t.Errorf("%s exists: %v, expected %v", filePath, err == nil, isExpected)
+				}
+			}
+		})
+	}
+}
+
+func TestSetFileExpiry(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			doc := backend.hub.Cloud
+			uploadText(t, doc, "b1", "docs/a.txt", "a")
+
+			expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+			if err := doc.SetFileExpiry(ctx, "b1", "docs/a.txt", &expiresAt); err != nil {
+				t.Fatal(err)
+			}
+			page, err := doc.GetFiles(ctx, "b1", &cloud.ListFilesParams{Prefix: "docs/"})
+			if err != nil || len(page.Items) != 1 {
+				t.Fatalf("listing %+v, error %v", page, err)
+			}
+			if item := page.Items[0]; item.ExpiresAt == nil || !item.ExpiresAt.Equal(expiresAt) {
+				t.Fatalf("listed expiry %v, expected %v", item.ExpiresAt, expiresAt)
+			}
+
+			if err = doc.SetFileExpiry(ctx, "b1", "docs/a.txt", nil); err != nil {
+				t.Fatal(err)
+			}
+			item, err := doc.StatFile(ctx, "b1", "docs/a.txt")
+			if err != nil || item.ExpiresAt != nil {
+				t.Fatalf("expiry is not cleared: %+v, error %v", item, err)
+			}
+			if removed, err := doc.RemoveExpired(ctx, "b1", expiresAt.Add(time.Hour)); err != nil || removed != 0 {
+				t.Fatalf("file without expiry is removed: %d, error %v", removed, err)
+			}
+
+			if err = doc.SetFileExpiry(ctx, "b1", "missing.txt", &expiresAt); err == nil {
+				t.Fatal("expiry of missing file has been set")
+			}
+		})
+	}
+}
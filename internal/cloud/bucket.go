@@ -0,0 +1,5 @@
+package cloud
+
+import "errors"
+
+var ErrBucketNotEmpty = errors.New("bucket is not empty")
@@ -1,8 +1,23 @@
 package cloud
 
 type CloudConfig struct {
+	Provider  string
 	Address   string
 	Username  string
 	Password  string
 	EnableSSL bool
+	RootPath  string
+	// ShareSecret signs share URLs served by docs-hub for clouds without
+	// presigning. It is required by local storage, in-memory cloud uses
+	// random secret if it is empty.
+	ShareSecret string
+	// PublicEndpoint is address users reach cloud by, e.g. https://files.example.com,
+	// presigned URLs are signed for it instead of Address if it is set.
+	PublicEndpoint string
+	// Region is signed into presigned URLs of PublicEndpoint, because
+	// public host may be unreachable for docs-hub to look region up.
+	Region string
+	// ProxyPresigned serves presigned URLs back through docs-hub under
+	// PresignProxyPath, PublicEndpoint is docs-hub address then.
+	ProxyPresigned bool
 }
@@ -0,0 +1,141 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	ConflictOverwrite = "overwrite"
+	ConflictSkip      = "skip"
+	ConflictRename    = "rename"
+	ConflictFail      = "fail"
+)
+
+// DefaultConflictPolicy is applied if caller has not chosen policy,
+// so existing files are replaced only if it is asked for explicitly.
+const DefaultConflictPolicy = ConflictFail
+
+const maxRenameAttempts = 1000
+
+var (
+	ErrFileExists  = errors.New("file already exists")
+	ErrFileSkipped = errors.New("file already exists and has been skipped")
+)
+
+func CheckConflictPolicy(policy string) error {
+	switch policy {
+	case "", ConflictOverwrite, ConflictSkip, ConflictRename, ConflictFail:
+		return nil
+	default:
+		return fmt.Errorf("unknown conflict policy: %s", policy)
+	}
+}
+
+// IsOverwrite reports whether policy replaces existing file,
+// files are written only to free paths by other policies.
+func IsOverwrite(policy string) bool {
+	return policy == ConflictOverwrite
+}
+
+// ResolveConflict returns file path to store file into according to
+// conflict policy when file with the same path already exists.
+func ResolveConflict(ctx context.Context, doc IDocument, bucket, filePath, policy string) (string, error) {
+	if policy == "" {
+		policy = DefaultConflictPolicy
+	}
+
+	if IsOverwrite(policy) {
+		return filePath, nil
+	}
+
+	if !isFileExist(ctx, doc, bucket, filePath) {
+		return filePath, nil
+	}
+
+	switch policy {
+	case ConflictSkip:
+		return "", ErrFileSkipped
+	case ConflictFail:
+		return "", fmt.Errorf("%w: %s", ErrFileExists, filePath)
+	case ConflictRename:
+		dirPath, fileName := path.Split(filePath)
+		fileExt := path.Ext(fileName)
+		baseName := strings.TrimSuffix(fileName, fileExt)
+		for index := 1; index <= maxRenameAttempts; index++ {
+			newPath := fmt.Sprintf("%s%s (%d)%s", dirPath, baseName, index, fileExt)
+			if !isFileExist(ctx, doc, bucket, newPath) {
+				return newPath, nil
+			}
+		}
+		return "", fmt.Errorf("failed to find free name for file: %s", filePath)
+	default:
+		return "", fmt.Errorf("unknown conflict policy: %s", policy)
+	}
+}
+
+// CopyByPolicy copies file to path resolved by conflict policy and returns
+// that path, see WriteByPolicy.
+func CopyByPolicy(ctx context.Context, doc IDocument, srcBucket, srcPath, dstBucket, dstPath, policy string) (string, error) {
+	if IsOverwrite(policy) {
+		return dstPath, doc.CopyFile(ctx, srcBucket, srcPath, dstBucket, dstPath)
+	}
+
+	return WriteByPolicy(ctx, doc, dstBucket, dstPath, policy, func(filePath string) error {
+		return doc.CopyNewFile(ctx, srcBucket, srcPath, dstBucket, filePath)
+	})
+}
+
+// MoveByPolicy moves file to path resolved by conflict policy and returns
+// that path, see WriteByPolicy.
+func MoveByPolicy(ctx context.Context, doc IDocument, srcBucket, srcPath, dstBucket, dstPath, policy string) (string, error) {
+	if IsOverwrite(policy) {
+		return dstPath, doc.MoveFile(ctx, srcBucket, srcPath, dstBucket, dstPath)
+	}
+
+	return WriteByPolicy(ctx, doc, dstBucket, dstPath, policy, func(filePath string) error {
+		return doc.MoveNewFile(ctx, srcBucket, srcPath, dstBucket, filePath)
+	})
+}
+
+// WriteByPolicy resolves conflict of file path by policy other than overwrite
+// and writes file to resolved path by write, which must fail with ErrFileExists
+// if path has been taken after check. Then rename policy looks for next free
+// name, so write must be repeatable, and skip policy skips file.
+func WriteByPolicy(ctx context.Context, doc IDocument, bucket, filePath, policy string, write func(string) error) (string, error) {
+	for range maxRenameAttempts {
+		dstPath, err := ResolveConflict(ctx, doc, bucket, filePath, policy)
+		if err != nil {
+			return "", err
+		}
+
+		err = write(dstPath)
+		if !errors.Is(err, ErrFileExists) {
+			return dstPath, err
+		}
+
+		switch policy {
+		case ConflictSkip:
+			return "", ErrFileSkipped
+		case ConflictRename:
+			continue
+		default:
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("failed to find free name for file: %s", filePath)
+}
+
+func isFileExist(ctx context.Context, doc IDocument, bucket, filePath string) bool {
+	_, err := doc.StatFile(ctx, bucket, filePath)
+	return err == nil
+}
+
+// JoinFilePath builds object key of file stored into directory.
+func JoinFilePath(dirPath, fileName string) string {
+	return strings.TrimPrefix(path.Join("/", dirPath, fileName), "/")
+}
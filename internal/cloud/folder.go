@@ -0,0 +1,73 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const listFolderPageSize = 1000
+
+var ErrInvalidFilePath = errors.New("invalid file path")
+
+// CheckFilePath rejects keys with empty, "." or ".." segments. Such keys
+// are never normalized, because prefix checks of callers run on raw keys
+// and must match object which is accessed. Folder keys keep trailing slash,
+// empty key is root of bucket.
+func CheckFilePath(filePath string) error {
+	trimmed := strings.TrimSuffix(filePath, "/")
+	if trimmed == "" {
+		if filePath == "/" {
+			return fmt.Errorf("%w: %s", ErrInvalidFilePath, filePath)
+		}
+		return nil
+	}
+
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("%w: %s", ErrInvalidFilePath, filePath)
+		}
+	}
+
+	return nil
+}
+
+// FolderKey returns key of folder placeholder object, like S3 console
+// does it is folder path with trailing slash, e.g. "docs/" for "/docs".
+func FolderKey(folderPath string) string {
+	cleanPath := JoinFilePath("", folderPath)
+	if cleanPath == "" {
+		return ""
+	}
+
+	return cleanPath + "/"
+}
+
+func IsFolderKey(filePath string) bool {
+	return strings.HasSuffix(filePath, "/")
+}
+
+// ListFolder returns all objects of folder tree including placeholders
+// of folder itself and its subfolders.
+func ListFolder(ctx context.Context, doc IDocument, bucket, folderPath string) ([]*StorageItem, error) {
+	params := &ListFilesParams{
+		Prefix:    FolderKey(folderPath),
+		Recursive: true,
+		Limit:     listFolderPageSize,
+	}
+
+	items := make([]*StorageItem, 0)
+	for {
+		page, err := doc.GetFiles(ctx, bucket, params)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Items...)
+		if page.NextCursor == "" {
+			return items, nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,61 @@
+package cloud
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckFilePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		isValid  bool
+	}{
+		{name: "bucket root", filePath: "", isValid: true},
+		{name: "file", filePath: "a.txt", isValid: true},
+		{name: "nested file", filePath: "docs/2024/a.txt", isValid: true},
+		{name: "folder key", filePath: "docs/", isValid: true},
+		{name: "hidden name", filePath: ".trash/a.txt", isValid: true},
+		{name: "dots in name", filePath: "docs/a..b.txt", isValid: true},
+		{name: "slash only", filePath: "/", isValid: false},
+		{name: "leading slash", filePath: "/docs/a.txt", isValid: false},
+		{name: "empty segment", filePath: "docs//a.txt", isValid: false},
+		{name: "dot segment", filePath: "docs/./a.txt", isValid: false},
+		{name: "parent segment", filePath: "docs/../a.txt", isValid: false},
+		{name: "parent escapes bucket", filePath: "../other/a.txt", isValid: false},
+		{name: "parent only", filePath: "..", isValid: false},
+		{name: "parent folder key", filePath: "docs/../", isValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckFilePath(test.filePath)
+			if test.isValid && err != nil {
+				t.Fatalf("path %q: unexpected error: %v", test.filePath, err)
+			}
+			if !test.isValid && !errors.Is(err, ErrInvalidFilePath) {
+				t.Fatalf("path %q: expected ErrInvalidFilePath, got %v", test.filePath, err)
+			}
+		})
+	}
+}
+
+func TestFolderKey(t *testing.T) {
+	tests := []struct {
+		folderPath string
+		expected   string
+	}{
+		{folderPath: "", expected: ""},
+		{folderPath: "/", expected: ""},
+		{folderPath: "docs", expected: "docs/"},
+		{folderPath: "/docs", expected: "docs/"},
+		{folderPath: "docs/", expected: "docs/"},
+		{folderPath: "docs/2024", expected: "docs/2024/"},
+	}
+
+	for _, test := range tests {
+		if key := FolderKey(test.folderPath); key != test.expected {
+			t.Errorf("FolderKey(%q) = %q, expected %q", test.folderPath, key, test.expected)
+		}
+	}
+}
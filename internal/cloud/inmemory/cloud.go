@@ -0,0 +1,585 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+type memObject struct {
+	data     []byte
+	etag     string
+	modified time.Time
+	expires  *time.Time
+	// storageClass is set by lifecycle transition, default class otherwise.
+	storageClass string
+
+	versionID    string
+	deleteMarker bool
+}
+
+func newMemObject(data []byte, expires *time.Time) *memObject {
+	return &memObject{
+		data:     data,
+		etag:     fmt.Sprintf("%x", md5.Sum(data)),
+		modified: time.Now().UTC(),
+		expires:  expires,
+	}
+}
+
+func (mo *memObject) isExpired(now time.Time) bool {
+	return mo.expires != nil && !now.Before(*mo.expires)
+}
+
+func (mo *memObject) storageItem(filePath string) *cloud.StorageItem {
+	return &cloud.StorageItem{
+		FileName:      filePath,
+		DirectoryName: cloud.DirectoryOf(filePath),
+		IsDirectory:   cloud.IsFolderKey(filePath),
+		Size:          int64(len(mo.data)),
+		LastModified:  mo.modified,
+		ETag:          mo.etag,
+		ContentType:   cloud.ContentTypeOf(filePath),
+		StorageClass:  mo.getStorageClass(),
+		ExpiresAt:     mo.expires,
+	}
+}
+
+func (mo *memObject) getStorageClass() string {
+	if mo.storageClass == "" {
+		return cloud.DefaultStorageClass
+	}
+
+	return mo.storageClass
+}
+
+type InMemory struct {
+	config *cloud.CloudConfig
+	signer *cloud.URLSigner
+
+	mu         sync.RWMutex
+	buckets    map[string]map[string]*memObject
+	uploads    map[string]*memUpload
+	versioning map[string]string
+	versions   map[string]map[string][]*memObject
+	lifecycle  map[string][]*cloud.LifecycleRule
+}
+
+func New(config *cloud.CloudConfig) *cloud.DocumentHub {
+	inMemory := &InMemory{
+		config:     config,
+		signer:     cloud.NewURLSigner(config),
+		buckets:    make(map[string]map[string]*memObject),
+		uploads:    make(map[string]*memUpload),
+		versioning: make(map[string]string),
+		versions:   make(map[string]map[string][]*memObject),
+		lifecycle:  make(map[string][]*cloud.LifecycleRule),
+	}
+
+	return &cloud.DocumentHub{Cloud: inMemory}
+}
+
+func (im *InMemory) GetBuckets(_ context.Context) ([]string, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	bucketNames := make([]string, 0, len(im.buckets))
+	for bucketName := range im.buckets {
+		bucketNames = append(bucketNames, bucketName)
+	}
+
+	sort.Strings(bucketNames)
+	return bucketNames, nil
+}
+
+func (im *InMemory) CreateBucket(_ context.Context, bucket string) error {
+	if bucket == "" || strings.Contains(bucket, "/") {
+		return fmt.Errorf("invalid bucket name: %s", bucket)
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, ok := im.buckets[bucket]; ok {
+		return fmt.Errorf("bucket %s already exists", bucket)
+	}
+
+	im.buckets[bucket] = make(map[string]*memObject)
+	return nil
+}
+
+func (im *InMemory) RemoveBucket(_ context.Context, bucket string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	im.dropExpired(objects)
+	if len(objects) > 0 || len(im.versions[bucket]) > 0 {
+		return fmt.Errorf("%w: %s", cloud.ErrBucketNotEmpty, bucket)
+	}
+
+	delete(im.buckets, bucket)
+	delete(im.versioning, bucket)
+	delete(im.versions, bucket)
+	delete(im.lifecycle, bucket)
+	return nil
+}
+
+func (im *InMemory) PurgeBucket(_ context.Context, bucket string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, err := im.getBucket(bucket); err != nil {
+		return err
+	}
+
+	im.buckets[bucket] = make(map[string]*memObject)
+	delete(im.versions, bucket)
+	for uploadID, upload := range im.uploads {
+		if upload.bucket == bucket {
+			delete(im.uploads, uploadID)
+		}
+	}
+
+	return nil
+}
+
+func (im *InMemory) IsBucketExist(_ context.Context, bucket string) (bool, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	_, ok := im.buckets[bucket]
+	return ok, nil
+}
+
+func (im *InMemory) GetFiles(_ context.Context, bucket string, params *cloud.ListFilesParams) (*cloud.FilesPage, error) {
+	pager, err := cloud.NewFilesPager(params)
+	if err != nil {
+		return nil, err
+	}
+
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := params.Prefix
+	now := time.Now()
+	dirNames := make(map[string]struct{})
+	dirObjects := make([]*cloud.StorageItem, 0)
+	for objKey, obj := range objects {
+		if !strings.HasPrefix(objKey, prefix) || obj.isExpired(now) {
+			continue
+		}
+
+		if params.Recursive {
+			dirObjects = append(dirObjects, obj.storageItem(objKey))
+			continue
+		}
+
+		// Placeholder of listed folder is not its own child.
+		if objKey == prefix {
+			continue
+		}
+
+		// Collapse nested keys into common prefix like S3 delimiter does.
+		relPath := strings.TrimPrefix(objKey, prefix)
+		if index := strings.Index(relPath, "/"); index >= 0 {
+			dirNames[prefix+relPath[:index+1]] = struct{}{}
+			continue
+		}
+
+		item := obj.storageItem(objKey)
+		item.DirectoryName = prefix
+		dirObjects = append(dirObjects, item)
+	}
+
+	for dirName := range dirNames {
+		dirObjects = append(dirObjects, &cloud.StorageItem{
+			FileName:      dirName,
+			DirectoryName: prefix,
+			IsDirectory:   true,
+		})
+	}
+
+	sort.Slice(dirObjects, func(i, j int) bool {
+		return dirObjects[i].FileName < dirObjects[j].FileName
+	})
+
+	for _, item := range dirObjects {
+		if !pager.Add(item) {
+			break
+		}
+	}
+
+	return pager.Page()
+}
+
+func (im *InMemory) RemoveFile(_ context.Context, bucket, filePath string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	return im.deleteObject(bucket, objects, filePath)
+}
+
+func (im *InMemory) UploadFile(_ context.Context, bucket, filePath string, data io.Reader, _ int64) error {
+	objData, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	return im.putObject(bucket, filePath, newMemObject(objData, nil), false)
+}
+
+func (im *InMemory) UploadNewFile(_ context.Context, bucket, filePath string, data io.Reader, _ int64) error {
+	objData, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	return im.putObject(bucket, filePath, newMemObject(objData, nil), true)
+}
+
+func (im *InMemory) CopyFile(_ context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return im.copyObject(srcBucket, srcPath, dstBucket, dstPath, false)
+}
+
+func (im *InMemory) CopyNewFile(_ context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return im.copyObject(srcBucket, srcPath, dstBucket, dstPath, true)
+}
+
+func (im *InMemory) copyObject(srcBucket, srcPath, dstBucket, dstPath string, isNew bool) error {
+	if err := checkFilePath(dstPath); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	srcObjects, err := im.getBucket(srcBucket)
+	if err != nil {
+		return err
+	}
+
+	dstObjects, err := im.getBucket(dstBucket)
+	if err != nil {
+		return err
+	}
+
+	obj, err := im.getObject(srcObjects, srcPath)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		if err = checkFree(dstObjects, dstPath); err != nil {
+			return err
+		}
+	}
+
+	return im.storeObject(dstBucket, dstObjects, dstPath, newMemObject(obj.data, obj.expires))
+}
+
+func (im *InMemory) MoveFile(_ context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return im.moveObject(srcBucket, srcPath, dstBucket, dstPath, false)
+}
+
+func (im *InMemory) MoveNewFile(_ context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return im.moveObject(srcBucket, srcPath, dstBucket, dstPath, true)
+}
+
+func (im *InMemory) moveObject(srcBucket, srcPath, dstBucket, dstPath string, isNew bool) error {
+	if err := checkFilePath(dstPath); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	srcObjects, err := im.getBucket(srcBucket)
+	if err != nil {
+		return err
+	}
+
+	dstObjects, err := im.getBucket(dstBucket)
+	if err != nil {
+		return err
+	}
+
+	obj, err := im.getObject(srcObjects, srcPath)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		if err = checkFree(dstObjects, dstPath); err != nil {
+			return err
+		}
+	}
+
+	if err = im.deleteObject(srcBucket, srcObjects, srcPath); err != nil {
+		return err
+	}
+
+	// Moved object gets its own version, so history of source is kept intact.
+	movedObj := *obj
+	return im.storeObject(dstBucket, dstObjects, dstPath, &movedObj)
+}
+
+func (im *InMemory) CreateFolder(_ context.Context, bucket, folderPath string) error {
+	folderKey := cloud.FolderKey(folderPath)
+	if folderKey == "" {
+		return fmt.Errorf("invalid folder path: %s", folderPath)
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	return im.storeObject(bucket, objects, folderKey, newMemObject(nil, nil))
+}
+
+func (im *InMemory) RemoveFiles(_ context.Context, bucket string, filePaths []string) map[string]error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	removeErrs := make(map[string]error)
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		for _, filePath := range filePaths {
+			removeErrs[filePath] = err
+		}
+		return removeErrs
+	}
+
+	for _, filePath := range filePaths {
+		if err = im.deleteObject(bucket, objects, filePath); err != nil {
+			removeErrs[filePath] = err
+		}
+	}
+
+	return removeErrs
+}
+
+func (im *InMemory) DownloadFile(_ context.Context, bucket, filePath string) (io.ReadCloser, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := im.getObject(objects, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stored data is never mutated in place, so it is safe to share.
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (im *InMemory) DownloadRange(_ context.Context, bucket, filePath string, offset, length int64) (io.ReadCloser, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := im.getObject(objects, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dataLen := int64(len(obj.data))
+	if offset < 0 || offset > dataLen {
+		return nil, fmt.Errorf("invalid range offset %d of object %s", offset, filePath)
+	}
+
+	end := min(offset+length, dataLen)
+	return io.NopCloser(bytes.NewReader(obj.data[offset:end])), nil
+}
+
+func (im *InMemory) StatFile(_ context.Context, bucket, filePath string) (*cloud.StorageItem, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := im.getObject(objects, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.storageItem(filePath), nil
+}
+
+func (im *InMemory) GetShareURL(_ context.Context, bucket, filePath string, expired time.Duration) (string, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = im.getObject(objects, filePath); err != nil {
+		return "", err
+	}
+
+	return im.signer.SignURL(bucket, filePath, expired), nil
+}
+
+func (im *InMemory) VerifyShareURL(_ context.Context, bucket, filePath string, expires int64, signature string) error {
+	return im.signer.VerifyURL(bucket, filePath, expires, signature)
+}
+
+func (im *InMemory) UploadExpired(_ context.Context, bucket, filePath string, expired time.Time, data io.Reader, _ int64) error {
+	objData, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	return im.putObject(bucket, filePath, newMemObject(objData, &expired), false)
+}
+
+func (im *InMemory) SetFileExpiry(_ context.Context, bucket, filePath string, expiresAt *time.Time) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	obj, err := im.getObject(objects, filePath)
+	if err != nil {
+		return err
+	}
+
+	obj.expires = expiresAt
+	return nil
+}
+
+func (im *InMemory) RemoveExpired(_ context.Context, bucket string, now time.Time) (int, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for objKey, obj := range objects {
+		if !obj.isExpired(now) {
+			continue
+		}
+
+		if err = im.deleteObject(bucket, objects, objKey); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// putObject stores object, existing object is replaced unless isNew is set.
+func (im *InMemory) putObject(bucket, filePath string, obj *memObject, isNew bool) error {
+	if err := checkFilePath(filePath); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		if err = checkFree(objects, filePath); err != nil {
+			return err
+		}
+	}
+
+	return im.storeObject(bucket, objects, filePath, obj)
+}
+
+func (im *InMemory) getBucket(bucket string) (map[string]*memObject, error) {
+	objects, ok := im.buckets[bucket]
+	if !ok {
+		return nil, fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	return objects, nil
+}
+
+func (im *InMemory) getObject(objects map[string]*memObject, filePath string) (*memObject, error) {
+	obj, ok := objects[filePath]
+	if !ok || obj.isExpired(time.Now()) {
+		return nil, fmt.Errorf("object %s does not exist", filePath)
+	}
+
+	return obj, nil
+}
+
+// checkFree fails with ErrFileExists if object exists,
+// must be called with lock held.
+func checkFree(objects map[string]*memObject, filePath string) error {
+	if obj, ok := objects[filePath]; ok && !obj.isExpired(time.Now()) {
+		return fmt.Errorf("%w: %s", cloud.ErrFileExists, filePath)
+	}
+
+	return nil
+}
+
+func checkFilePath(filePath string) error {
+	if filePath == "" || strings.HasSuffix(filePath, "/") {
+		return fmt.Errorf("invalid file path: %s", filePath)
+	}
+
+	return nil
+}
+
+// dropExpired removes objects which expiration time has passed,
+// must be called with write lock held.
+func (im *InMemory) dropExpired(objects map[string]*memObject) {
+	now := time.Now()
+	for objKey, obj := range objects {
+		if obj.isExpired(now) {
+			delete(objects, objKey)
+		}
+	}
+}
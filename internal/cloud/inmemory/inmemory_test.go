@@ -0,0 +1,452 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+// newTestMemory returns empty in-memory cloud with buckets b1 and b2.
+func newTestMemory(t *testing.T) *InMemory {
+	t.Helper()
+
+	im := New(&cloud.CloudConfig{ShareSecret: "test"}).Cloud.(*InMemory)
+	for _, bucket := range []string{"b1", "b2"} {
+		if err := im.CreateBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("failed to create bucket %s: %v", bucket, err)
+		}
+	}
+
+	return im
+}
+
+func uploadText(t *testing.T, im *InMemory, bucket, filePath, content string) {
+	t.Helper()
+
+	err := im.UploadFile(context.Background(), bucket, filePath, strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("failed to upload %s/%s: %v", bucket, filePath, err)
+	}
+}
+
+func readText(t *testing.T, im *InMemory, bucket, filePath string) (string, error) {
+	t.Helper()
+
+	fileData, err := im.DownloadFile(context.Background(), bucket, filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = fileData.Close() }()
+
+	data, err := io.ReadAll(fileData)
+	return string(data), err
+}
+
+func listNames(t *testing.T, im *InMemory, bucket string, params *cloud.ListFilesParams) []string {
+	t.Helper()
+
+	page, err := im.GetFiles(context.Background(), bucket, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(page.Items))
+	for _, item := range page.Items {
+		names = append(names, item.FileName)
+	}
+
+	return names
+}
+
+func TestBuckets(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	buckets, err := im.GetBuckets(ctx)
+	if err != nil || strings.Join(buckets, ",") != "b1,b2" {
+		t.Fatalf("buckets %v, error %v", buckets, err)
+	}
+
+	for _, bucket := range []string{"b1", "", "a/b"} {
+		if err = im.CreateBucket(ctx, bucket); err == nil {
+			t.Fatalf("bucket %q has been created", bucket)
+		}
+	}
+
+	uploadText(t, im, "b1", "a.txt", "a")
+	if err = im.RemoveBucket(ctx, "b1"); !errors.Is(err, cloud.ErrBucketNotEmpty) {
+		t.Fatalf("expected ErrBucketNotEmpty, got %v", err)
+	}
+	if err = im.RemoveBucket(ctx, "b2"); err != nil {
+		t.Fatalf("failed to remove empty bucket: %v", err)
+	}
+
+	if exist, err := im.IsBucketExist(ctx, "b2"); err != nil || exist {
+		t.Fatalf("removed bucket exists: %v, error %v", exist, err)
+	}
+}
+
+func TestUploadDownloadAndRemove(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+	uploadText(t, im, "b1", "docs/a.txt", "0123456789")
+
+	if content, err := readText(t, im, "b1", "docs/a.txt"); err != nil || content != "0123456789" {
+		t.Fatalf("content %q, error %v", content, err)
+	}
+
+	item, err := im.StatFile(ctx, "b1", "docs/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Size != 10 || item.DirectoryName != "docs/" || item.ETag == "" {
+		t.Fatalf("unexpected item %+v", item)
+	}
+
+	fileData, err := im.DownloadRange(ctx, "b1", "docs/a.txt", 8, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(fileData)
+	if string(data) != "89" {
+		t.Fatalf("range content %q", data)
+	}
+	if _, err = im.DownloadRange(ctx, "b1", "docs/a.txt", 11, 1); err == nil {
+		t.Fatal("range beyond end of file has been read")
+	}
+
+	for _, filePath := range []string{"", "docs/"} {
+		if err = im.UploadFile(ctx, "b1", filePath, strings.NewReader("x"), 1); err == nil {
+			t.Fatalf("file %q has been uploaded", filePath)
+		}
+	}
+	if err = im.UploadFile(ctx, "b3", "a.txt", strings.NewReader("x"), 1); err == nil {
+		t.Fatal("file has been uploaded to missing bucket")
+	}
+
+	if err = im.RemoveFile(ctx, "b1", "docs/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = im.StatFile(ctx, "b1", "docs/a.txt"); err == nil {
+		t.Fatal("removed file still exists")
+	}
+}
+
+func TestGetFilesCollapsesFolders(t *testing.T) {
+	im := newTestMemory(t)
+	for _, filePath := range []string{"a.txt", "docs/b.txt", "docs/2024/c.txt", "other/d.txt"} {
+		uploadText(t, im, "b1", filePath, filePath)
+	}
+	if err := im.CreateFolder(context.Background(), "b1", "docs/empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		params   *cloud.ListFilesParams
+		expected string
+	}{
+		{name: "root", params: &cloud.ListFilesParams{}, expected: "a.txt,docs/,other/"},
+		{name: "folder", params: &cloud.ListFilesParams{Prefix: "docs/"}, expected: "docs/2024/,docs/b.txt,docs/empty/"},
+		{name: "empty folder", params: &cloud.ListFilesParams{Prefix: "docs/empty/"}, expected: ""},
+		{
+			name:     "recursive",
+			params:   &cloud.ListFilesParams{Prefix: "docs/", Recursive: true},
+			expected: "docs/2024/c.txt,docs/b.txt,docs/empty/",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if names := strings.Join(listNames(t, im, "b1", test.params), ","); names != test.expected {
+				t.Fatalf("listed %q, expected %q", names, test.expected)
+			}
+		})
+	}
+}
+
+func TestExpiredFilesAreHidden(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	err := im.UploadExpired(ctx, "b1", "old.txt", time.Now().Add(-time.Second), strings.NewReader("old"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = im.StatFile(ctx, "b1", "old.txt"); err == nil {
+		t.Fatal("expired file is found")
+	}
+	if names := listNames(t, im, "b1", &cloud.ListFilesParams{}); len(names) != 0 {
+		t.Fatalf("expired file is listed: %v", names)
+	}
+	if err = im.RemoveBucket(ctx, "b1"); err != nil {
+		t.Fatalf("bucket with only expired files is not removed: %v", err)
+	}
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	uploadID, err := im.CreateUpload(ctx, "b1", "docs/big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parts are concatenated by number, not by order of upload.
+	for _, part := range []struct {
+		number int
+		data   string
+	}{{number: 3, data: "ccc"}, {number: 1, data: "a"}, {number: 2, data: "bb"}, {number: 1, data: "aa"}} {
+		_, err = im.UploadPart(ctx, "b1", "docs/big.bin", uploadID, part.number, strings.NewReader(part.data), int64(len(part.data)))
+		if err != nil {
+			t.Fatalf("failed to upload part %d: %v", part.number, err)
+		}
+	}
+
+	if _, err = im.UploadPart(ctx, "b1", "docs/big.bin", uploadID, 0, strings.NewReader("x"), 1); err == nil {
+		t.Fatal("part 0 has been uploaded")
+	}
+	if _, err = im.ListUploadParts(ctx, "b1", "docs/other.bin", uploadID); err == nil {
+		t.Fatal("upload is found by other file path")
+	}
+
+	parts, err := im.ListUploadParts(ctx, "b1", "docs/big.bin", uploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := make([]int64, 0, len(parts))
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.ETag == "" {
+			t.Fatalf("unexpected part %+v", part)
+		}
+		sizes = append(sizes, part.Size)
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 3 {
+		t.Fatalf("part sizes %v", sizes)
+	}
+
+	if _, err = im.StatFile(ctx, "b1", "docs/big.bin"); err == nil {
+		t.Fatal("file exists before upload is completed")
+	}
+
+	if err = im.CompleteUpload(ctx, "b1", "docs/big.bin", uploadID); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := readText(t, im, "b1", "docs/big.bin"); err != nil || content != "aabbccc" {
+		t.Fatalf("content %q, error %v", content, err)
+	}
+	if _, err = im.ListUploadParts(ctx, "b1", "docs/big.bin", uploadID); err == nil {
+		t.Fatal("completed upload still exists")
+	}
+}
+
+func TestMultipartUploadAbort(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	if _, err := im.CreateUpload(ctx, "b3", "a.bin"); err == nil {
+		t.Fatal("upload has been created in missing bucket")
+	}
+
+	uploadID, err := im.CreateUpload(ctx, "b1", "a.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = im.CompleteUpload(ctx, "b1", "a.bin", uploadID); err == nil {
+		t.Fatal("upload without parts has been completed")
+	}
+
+	_, err = im.UploadPart(ctx, "b1", "a.bin", uploadID, 1, strings.NewReader("a"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = im.AbortUpload(ctx, "b1", "a.bin", uploadID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = im.CompleteUpload(ctx, "b1", "a.bin", uploadID); err == nil {
+		t.Fatal("aborted upload has been completed")
+	}
+	if _, err = im.StatFile(ctx, "b1", "a.bin"); err == nil {
+		t.Fatal("aborted upload has created file")
+	}
+}
+
+func listVersions(t *testing.T, im *InMemory, bucket, filePath string) []*cloud.FileVersion {
+	t.Helper()
+
+	versions, err := im.ListFileVersions(context.Background(), bucket, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return versions
+}
+
+func TestVersions(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	if status, err := im.GetBucketVersioning(ctx, "b1"); err != nil || status != "" {
+		t.Fatalf("versioning of new bucket %q, error %v", status, err)
+	}
+	if err := im.SetBucketVersioning(ctx, "b1", "On"); err == nil {
+		t.Fatal("unknown versioning status has been set")
+	}
+	if err := im.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+		t.Fatal(err)
+	}
+
+	uploadText(t, im, "b1", "contract.txt", "v1")
+	uploadText(t, im, "b1", "contract.txt", "v2")
+
+	versions := listVersions(t, im, "b1", "contract.txt")
+	if len(versions) != 2 || !versions[0].IsLatest || versions[1].IsLatest || versions[0].VersionID == versions[1].VersionID {
+		t.Fatalf("unexpected versions %+v, %+v", versions[0], versions[1])
+	}
+	firstID := versions[1].VersionID
+
+	fileData, err := im.DownloadVersion(ctx, "b1", "contract.txt", firstID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(fileData); string(data) != "v1" {
+		t.Fatalf("first version content %q", data)
+	}
+
+	// Removed file is hidden behind delete marker and kept into history.
+	if err = im.RemoveFile(ctx, "b1", "contract.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = im.StatFile(ctx, "b1", "contract.txt"); err == nil {
+		t.Fatal("removed file is found")
+	}
+	versions = listVersions(t, im, "b1", "contract.txt")
+	if len(versions) != 3 || !versions[0].IsDeleteMarker || !versions[0].IsLatest {
+		t.Fatalf("removed file has versions %+v", versions)
+	}
+	if _, err = im.DownloadVersion(ctx, "b1", "contract.txt", versions[0].VersionID); err == nil {
+		t.Fatal("delete marker has been downloaded")
+	}
+
+	if err = im.RestoreVersion(ctx, "b1", "contract.txt", firstID); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := readText(t, im, "b1", "contract.txt"); err != nil || content != "v1" {
+		t.Fatalf("restored content %q, error %v", content, err)
+	}
+	if versions = listVersions(t, im, "b1", "contract.txt"); len(versions) != 4 {
+		t.Fatalf("restore has not added version: %+v", versions)
+	}
+
+	// Removing current version brings back previous one.
+	if err = im.RemoveVersion(ctx, "b1", "contract.txt", versions[0].VersionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = im.StatFile(ctx, "b1", "contract.txt"); err == nil {
+		t.Fatal("file is found while delete marker is latest version")
+	}
+	if err = im.RemoveVersion(ctx, "b1", "contract.txt", "missing"); err == nil {
+		t.Fatal("missing version has been removed")
+	}
+}
+
+func TestSuspendedVersioning(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	if err := im.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+		t.Fatal(err)
+	}
+	uploadText(t, im, "b1", "a.txt", "v1")
+	if err := im.SetBucketVersioning(ctx, "b1", cloud.VersioningSuspended); err != nil {
+		t.Fatal(err)
+	}
+	uploadText(t, im, "b1", "a.txt", "v2")
+	uploadText(t, im, "b1", "a.txt", "v3")
+
+	// Null version is replaced, versions made before suspension are kept.
+	versions := listVersions(t, im, "b1", "a.txt")
+	if len(versions) != 2 || versions[0].VersionID != cloud.NullVersionID || versions[1].VersionID == cloud.NullVersionID {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+	if content, err := readText(t, im, "b1", "a.txt"); err != nil || content != "v3" {
+		t.Fatalf("content %q, error %v", content, err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	im := newTestMemory(t)
+
+	invalid := []*cloud.LifecycleRule{{ID: "no-action", Prefix: "tmp/"}}
+	if err := im.SetBucketLifecycle(ctx, "b1", invalid); err == nil {
+		t.Fatal("rule without action has been set")
+	}
+
+	rules := []*cloud.LifecycleRule{
+		{ID: "tmp", Prefix: "tmp/", ExpirationDays: 1, AbortIncompleteUploadDays: 2},
+		{ID: "docs", Prefix: "docs/", NoncurrentExpirationDays: 7, TransitionDays: 30, TransitionStorageClass: "GLACIER"},
+		{ID: "off", Status: cloud.LifecycleDisabled, Prefix: "docs/", ExpirationDays: 1},
+	}
+	if err := im.SetBucketLifecycle(ctx, "b1", rules); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := im.GetBucketLifecycle(ctx, "b1"); err != nil || len(stored) != 3 {
+		t.Fatalf("rules %+v, error %v", stored, err)
+	}
+
+	if err := im.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+		t.Fatal(err)
+	}
+	uploadText(t, im, "b1", "tmp/a.txt", "a")
+	uploadText(t, im, "b1", "docs/b.txt", "b1")
+	uploadText(t, im, "b1", "docs/b.txt", "b2")
+	uploadText(t, im, "b1", "c.txt", "c")
+	uploadID, err := im.CreateUpload(ctx, "b1", "tmp/big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err = im.ApplyLifecycle(ctx, "b1", now.AddDate(0, 0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = im.StatFile(ctx, "b1", "tmp/a.txt"); err == nil {
+		t.Fatal("expired file still exists")
+	}
+	if _, err = im.ListUploadParts(ctx, "b1", "tmp/big.bin", uploadID); err == nil {
+		t.Fatal("incomplete upload has not been aborted")
+	}
+	if versions, _ := im.ListFileVersions(ctx, "b1", "docs/b.txt"); len(versions) != 2 {
+		t.Fatalf("noncurrent version is expired early: %+v", versions)
+	}
+
+	if err = im.ApplyLifecycle(ctx, "b1", now.AddDate(0, 0, 31)); err != nil {
+		t.Fatal(err)
+	}
+	item, err := im.StatFile(ctx, "b1", "docs/b.txt")
+	if err != nil || item.StorageClass != "GLACIER" {
+		t.Fatalf("file has not been transitioned: %+v, error %v", item, err)
+	}
+	if versions, _ := im.ListFileVersions(ctx, "b1", "docs/b.txt"); len(versions) != 1 {
+		t.Fatalf("noncurrent version has not been expired: %+v", versions)
+	}
+	if item, err = im.StatFile(ctx, "b1", "c.txt"); err != nil || item.StorageClass == "GLACIER" {
+		t.Fatalf("file out of rules has been changed: %+v, error %v", item, err)
+	}
+
+	if err = im.SetBucketLifecycle(ctx, "b1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := im.GetBucketLifecycle(ctx, "b1"); err != nil || len(stored) != 0 {
+		t.Fatalf("rules are not removed: %+v, error %v", stored, err)
+	}
+}
@@ -0,0 +1,130 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+func (im *InMemory) SetBucketLifecycle(_ context.Context, bucket string, rules []*cloud.LifecycleRule) error {
+	if err := cloud.CheckLifecycleRules(rules); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, err := im.getBucket(bucket); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		delete(im.lifecycle, bucket)
+		return nil
+	}
+
+	im.lifecycle[bucket] = rules
+	return nil
+}
+
+func (im *InMemory) GetBucketLifecycle(_ context.Context, bucket string) ([]*cloud.LifecycleRule, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	if _, err := im.getBucket(bucket); err != nil {
+		return nil, err
+	}
+
+	rules := im.lifecycle[bucket]
+	if rules == nil {
+		return make([]*cloud.LifecycleRule, 0), nil
+	}
+
+	return rules, nil
+}
+
+func (im *InMemory) ApplyLifecycle(_ context.Context, bucket string, now time.Time) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range im.lifecycle[bucket] {
+		if !rule.IsEnabled() {
+			continue
+		}
+
+		if err = im.applyCurrent(bucket, objects, rule, now); err != nil {
+			return err
+		}
+		im.expireNoncurrent(bucket, objects, rule, now)
+		im.abortUploads(bucket, rule, now)
+	}
+
+	return nil
+}
+
+// applyCurrent expires or transitions current objects matched by rule.
+func (im *InMemory) applyCurrent(bucket string, objects map[string]*memObject, rule *cloud.LifecycleRule, now time.Time) error {
+	for objKey, obj := range objects {
+		if !rule.Matches(objKey) || cloud.IsFolderKey(objKey) {
+			continue
+		}
+
+		if cloud.IsOlderThan(obj.modified, rule.ExpirationDays, now) {
+			if err := im.deleteObject(bucket, objects, objKey); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if cloud.IsOlderThan(obj.modified, rule.TransitionDays, now) {
+			obj.storageClass = rule.TransitionStorageClass
+		}
+	}
+
+	return nil
+}
+
+// expireNoncurrent removes versions which have become noncurrent, when
+// newer version has been written, earlier than rule days ago.
+func (im *InMemory) expireNoncurrent(bucket string, objects map[string]*memObject, rule *cloud.LifecycleRule, now time.Time) {
+	if rule.NoncurrentExpirationDays == 0 {
+		return
+	}
+
+	for filePath, history := range im.versions[bucket] {
+		if !rule.Matches(filePath) {
+			continue
+		}
+
+		newer, hasCurrent := objects[filePath]
+		kept := make([]*memObject, 0, len(history))
+		for index, obj := range history {
+			if index == 0 && !hasCurrent {
+				kept = append(kept, obj)
+			} else if !cloud.IsOlderThan(newer.modified, rule.NoncurrentExpirationDays, now) {
+				kept = append(kept, obj)
+			}
+			newer = obj
+		}
+
+		im.setHistory(bucket, filePath, kept)
+	}
+}
+
+func (im *InMemory) abortUploads(bucket string, rule *cloud.LifecycleRule, now time.Time) {
+	for uploadID, upload := range im.uploads {
+		if upload.bucket != bucket || !rule.Matches(upload.filePath) {
+			continue
+		}
+
+		if cloud.IsOlderThan(upload.initiated, rule.AbortIncompleteUploadDays, now) {
+			delete(im.uploads, uploadID)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+type memUpload struct {
+	bucket    string
+	filePath  string
+	initiated time.Time
+	parts     map[int]*memPart
+}
+
+type memPart struct {
+	data     []byte
+	etag     string
+	modified time.Time
+}
+
+func (mp *memPart) uploadPart(partNumber int) *cloud.UploadPart {
+	return &cloud.UploadPart{
+		PartNumber:   partNumber,
+		Size:         int64(len(mp.data)),
+		ETag:         mp.etag,
+		LastModified: mp.modified,
+	}
+}
+
+func (im *InMemory) CreateUpload(_ context.Context, bucket, filePath string) (string, error) {
+	if err := checkFilePath(filePath); err != nil {
+		return "", err
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, err := im.getBucket(bucket); err != nil {
+		return "", err
+	}
+
+	uploadID := hex.EncodeToString(idBytes)
+	im.uploads[uploadID] = &memUpload{
+		bucket:    bucket,
+		filePath:  filePath,
+		initiated: time.Now().UTC(),
+		parts:     make(map[int]*memPart),
+	}
+
+	return uploadID, nil
+}
+
+func (im *InMemory) UploadPart(
+	_ context.Context,
+	bucket, filePath, uploadID string,
+	partNumber int,
+	data io.Reader,
+	_ int64,
+) (*cloud.UploadPart, error) {
+	if partNumber < 1 {
+		return nil, fmt.Errorf("invalid part number: %d", partNumber)
+	}
+
+	partData, err := io.ReadAll(data)
+	if err != nil {
+		return nil, err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	upload, err := im.getUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	part := &memPart{
+		data:     partData,
+		etag:     fmt.Sprintf("%x", md5.Sum(partData)),
+		modified: time.Now().UTC(),
+	}
+
+	upload.parts[partNumber] = part
+	return part.uploadPart(partNumber), nil
+}
+
+func (im *InMemory) ListUploadParts(_ context.Context, bucket, filePath, uploadID string) ([]*cloud.UploadPart, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	upload, err := im.getUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*cloud.UploadPart, 0, len(upload.parts))
+	for partNumber, part := range upload.parts {
+		parts = append(parts, part.uploadPart(partNumber))
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	return parts, nil
+}
+
+func (im *InMemory) CompleteUpload(_ context.Context, bucket, filePath, uploadID string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	upload, err := im.getUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return err
+	}
+
+	if len(upload.parts) == 0 {
+		return fmt.Errorf("upload %s has no parts", uploadID)
+	}
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	partNumbers := make([]int, 0, len(upload.parts))
+	for partNumber := range upload.parts {
+		partNumbers = append(partNumbers, partNumber)
+	}
+	sort.Ints(partNumbers)
+
+	var objData bytes.Buffer
+	for _, partNumber := range partNumbers {
+		objData.Write(upload.parts[partNumber].data)
+	}
+
+	if err = im.storeObject(bucket, objects, filePath, newMemObject(objData.Bytes(), nil)); err != nil {
+		return err
+	}
+
+	delete(im.uploads, uploadID)
+	return nil
+}
+
+func (im *InMemory) AbortUpload(_ context.Context, bucket, filePath, uploadID string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, err := im.getUpload(bucket, filePath, uploadID); err != nil {
+		return err
+	}
+
+	delete(im.uploads, uploadID)
+	return nil
+}
+
+func (im *InMemory) getUpload(bucket, filePath, uploadID string) (*memUpload, error) {
+	upload, ok := im.uploads[uploadID]
+	if !ok || upload.bucket != bucket || upload.filePath != filePath {
+		return nil, fmt.Errorf("upload %s does not exist", uploadID)
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,270 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+func (mo *memObject) fileVersion(isLatest bool) *cloud.FileVersion {
+	version := &cloud.FileVersion{
+		VersionID:      mo.versionID,
+		IsLatest:       isLatest,
+		IsDeleteMarker: mo.deleteMarker,
+		Size:           int64(len(mo.data)),
+		LastModified:   mo.modified,
+		ETag:           mo.etag,
+	}
+
+	if version.VersionID == "" {
+		version.VersionID = cloud.NullVersionID
+	}
+	if mo.deleteMarker {
+		version.ETag = ""
+	}
+
+	return version
+}
+
+func (im *InMemory) SetBucketVersioning(_ context.Context, bucket, status string) error {
+	if err := cloud.CheckVersioningStatus(status); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, err := im.getBucket(bucket); err != nil {
+		return err
+	}
+
+	im.versioning[bucket] = status
+	return nil
+}
+
+func (im *InMemory) GetBucketVersioning(_ context.Context, bucket string) (string, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	if _, err := im.getBucket(bucket); err != nil {
+		return "", err
+	}
+
+	return im.versioning[bucket], nil
+}
+
+func (im *InMemory) ListFileVersions(_ context.Context, bucket, filePath string) ([]*cloud.FileVersion, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*cloud.FileVersion, 0)
+	current, hasCurrent := objects[filePath]
+	if hasCurrent {
+		versions = append(versions, current.fileVersion(true))
+	}
+
+	for index, obj := range im.versions[bucket][filePath] {
+		versions = append(versions, obj.fileVersion(index == 0 && !hasCurrent))
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("object %s does not exist", filePath)
+	}
+
+	return versions, nil
+}
+
+func (im *InMemory) DownloadVersion(_ context.Context, bucket, filePath, versionID string) (io.ReadCloser, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	obj, err := im.getVersion(bucket, filePath, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (im *InMemory) RestoreVersion(_ context.Context, bucket, filePath, versionID string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	obj, err := im.getVersion(bucket, filePath, versionID)
+	if err != nil {
+		return err
+	}
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	return im.storeObject(bucket, objects, filePath, newMemObject(obj.data, nil))
+}
+
+func (im *InMemory) RemoveVersion(_ context.Context, bucket, filePath, versionID string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	history := im.versions[bucket][filePath]
+	if current, ok := objects[filePath]; ok && current.matchVersion(versionID) {
+		delete(objects, filePath)
+	} else {
+		index := findVersion(history, versionID)
+		if index < 0 {
+			return fmt.Errorf("version %s of object %s does not exist", versionID, filePath)
+		}
+		history = append(history[:index:index], history[index+1:]...)
+	}
+
+	// Newest remaining version becomes current unless it is delete marker.
+	if _, ok := objects[filePath]; !ok && len(history) > 0 && !history[0].deleteMarker {
+		objects[filePath] = history[0]
+		history = history[1:]
+	}
+
+	im.setHistory(bucket, filePath, history)
+	return nil
+}
+
+func (mo *memObject) matchVersion(versionID string) bool {
+	if mo.versionID == "" {
+		return versionID == cloud.NullVersionID
+	}
+
+	return mo.versionID == versionID
+}
+
+func findVersion(history []*memObject, versionID string) int {
+	for index, obj := range history {
+		if obj.matchVersion(versionID) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (im *InMemory) getVersion(bucket, filePath, versionID string) (*memObject, error) {
+	objects, err := im.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok := objects[filePath]
+	if !ok || !obj.matchVersion(versionID) {
+		history := im.versions[bucket][filePath]
+		index := findVersion(history, versionID)
+		if index < 0 {
+			return nil, fmt.Errorf("version %s of object %s does not exist", versionID, filePath)
+		}
+		obj = history[index]
+	}
+
+	if obj.deleteMarker {
+		return nil, fmt.Errorf("version %s of object %s is delete marker", versionID, filePath)
+	}
+
+	return obj, nil
+}
+
+// storeObject replaces current object, previous object is kept as
+// noncurrent version when bucket versioning is on. Must be called
+// with write lock held like all other version helpers.
+func (im *InMemory) storeObject(bucket string, objects map[string]*memObject, filePath string, obj *memObject) error {
+	versionID, err := im.archiveObject(bucket, objects, filePath)
+	if err != nil {
+		return err
+	}
+
+	obj.versionID = versionID
+	objects[filePath] = obj
+	return nil
+}
+
+// deleteObject removes current object, when bucket versioning is on
+// object is kept as noncurrent version behind delete marker.
+func (im *InMemory) deleteObject(bucket string, objects map[string]*memObject, filePath string) error {
+	versionID, err := im.archiveObject(bucket, objects, filePath)
+	if err != nil {
+		return err
+	}
+
+	delete(objects, filePath)
+	if versionID == "" {
+		return nil
+	}
+
+	marker := &memObject{
+		modified:     time.Now().UTC(),
+		versionID:    versionID,
+		deleteMarker: true,
+	}
+
+	history := im.versions[bucket][filePath]
+	im.setHistory(bucket, filePath, append([]*memObject{marker}, history...))
+	return nil
+}
+
+// archiveObject moves current object into version history and returns
+// version id for object which replaces it. Null version is replaced
+// instead of being kept, like S3 does when versioning is suspended.
+func (im *InMemory) archiveObject(bucket string, objects map[string]*memObject, filePath string) (string, error) {
+	status := im.versioning[bucket]
+	if status == "" {
+		return "", nil
+	}
+
+	versionID := cloud.NullVersionID
+	if status == cloud.VersioningEnabled {
+		var err error
+		if versionID, err = cloud.NewVersionID(); err != nil {
+			return "", err
+		}
+	}
+
+	history := im.versions[bucket][filePath]
+	if versionID == cloud.NullVersionID {
+		if index := findVersion(history, versionID); index >= 0 {
+			history = append(history[:index:index], history[index+1:]...)
+		}
+	}
+
+	current, ok := objects[filePath]
+	if ok && !(versionID == cloud.NullVersionID && current.matchVersion(versionID)) {
+		history = append([]*memObject{current}, history...)
+	}
+
+	im.setHistory(bucket, filePath, history)
+	return versionID, nil
+}
+
+func (im *InMemory) setHistory(bucket, filePath string, history []*memObject) {
+	bucketVersions, ok := im.versions[bucket]
+	if !ok {
+		bucketVersions = make(map[string][]*memObject)
+		im.versions[bucket] = bucketVersions
+	}
+
+	if len(history) == 0 {
+		delete(bucketVersions, filePath)
+		return
+	}
+
+	bucketVersions[filePath] = history
+}
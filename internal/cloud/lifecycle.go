@@ -0,0 +1,90 @@
+package cloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	LifecycleEnabled  = "Enabled"
+	LifecycleDisabled = "Disabled"
+)
+
+// LifecycleRule describes actions applied to objects under prefix once they
+// are older than given number of days, zero days means action is not set.
+type LifecycleRule struct {
+	ID     string `json:"id" example:"expire-reports"`
+	Status string `json:"status" example:"Enabled" enums:"Enabled,Disabled"`
+	Prefix string `json:"prefix" example:"reports/"`
+
+	ExpirationDays            int    `json:"expiration_days,omitempty" example:"365"`
+	NoncurrentExpirationDays  int    `json:"noncurrent_expiration_days,omitempty" example:"30"`
+	AbortIncompleteUploadDays int    `json:"abort_incomplete_upload_days,omitempty" example:"7"`
+	TransitionDays            int    `json:"transition_days,omitempty" example:"90"`
+	TransitionStorageClass    string `json:"transition_storage_class,omitempty" example:"GLACIER"`
+}
+
+func (lr *LifecycleRule) IsEnabled() bool {
+	return lr.Status != LifecycleDisabled
+}
+
+func (lr *LifecycleRule) Matches(filePath string) bool {
+	return strings.HasPrefix(filePath, lr.Prefix)
+}
+
+func (lr *LifecycleRule) check() error {
+	switch lr.Status {
+	case "", LifecycleEnabled, LifecycleDisabled:
+	default:
+		return fmt.Errorf("unknown status of lifecycle rule %s: %s", lr.ID, lr.Status)
+	}
+
+	if lr.ExpirationDays < 0 || lr.NoncurrentExpirationDays < 0 ||
+		lr.AbortIncompleteUploadDays < 0 || lr.TransitionDays < 0 {
+		return fmt.Errorf("days of lifecycle rule %s must not be negative", lr.ID)
+	}
+
+	if lr.ExpirationDays == 0 && lr.NoncurrentExpirationDays == 0 &&
+		lr.AbortIncompleteUploadDays == 0 && lr.TransitionDays == 0 {
+		return fmt.Errorf("lifecycle rule %s has no action", lr.ID)
+	}
+
+	if (lr.TransitionDays == 0) != (lr.TransitionStorageClass == "") {
+		return fmt.Errorf("lifecycle rule %s must set both transition days and storage class", lr.ID)
+	}
+
+	if lr.TransitionDays > 0 && lr.ExpirationDays > 0 && lr.TransitionDays >= lr.ExpirationDays {
+		return fmt.Errorf("lifecycle rule %s must transition objects before expiration", lr.ID)
+	}
+
+	return nil
+}
+
+// CheckLifecycleRules validates lifecycle configuration of bucket,
+// empty rules list removes configuration.
+func CheckLifecycleRules(rules []*LifecycleRule) error {
+	ruleIDs := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule == nil || rule.ID == "" {
+			return fmt.Errorf("lifecycle rule id must not be empty")
+		}
+
+		if ruleIDs[rule.ID] {
+			return fmt.Errorf("duplicate lifecycle rule id: %s", rule.ID)
+		}
+		ruleIDs[rule.ID] = true
+
+		if err := rule.check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsOlderThan reports whether time is more than days before now,
+// it is used by backends which apply lifecycle rules themselves.
+func IsOlderThan(since time.Time, days int, now time.Time) bool {
+	return days > 0 && !now.Before(since.AddDate(0, 0, days))
+}
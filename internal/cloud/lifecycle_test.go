@@ -0,0 +1,61 @@
+package cloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckLifecycleRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []*LifecycleRule
+		isValid bool
+	}{
+		{name: "no rules", isValid: true},
+		{name: "expiration", rules: []*LifecycleRule{{ID: "a", ExpirationDays: 1}}, isValid: true},
+		{
+			name:    "transition before expiration",
+			rules:   []*LifecycleRule{{ID: "a", ExpirationDays: 60, TransitionDays: 30, TransitionStorageClass: "GLACIER"}},
+			isValid: true,
+		},
+		{name: "empty id", rules: []*LifecycleRule{{ExpirationDays: 1}}},
+		{name: "duplicate id", rules: []*LifecycleRule{{ID: "a", ExpirationDays: 1}, {ID: "a", ExpirationDays: 2}}},
+		{name: "unknown status", rules: []*LifecycleRule{{ID: "a", Status: "On", ExpirationDays: 1}}},
+		{name: "negative days", rules: []*LifecycleRule{{ID: "a", ExpirationDays: -1, TransitionDays: 1}}},
+		{name: "no action", rules: []*LifecycleRule{{ID: "a", Prefix: "docs/"}}},
+		{name: "transition without class", rules: []*LifecycleRule{{ID: "a", TransitionDays: 30}}},
+		{
+			name:  "transition after expiration",
+			rules: []*LifecycleRule{{ID: "a", ExpirationDays: 30, TransitionDays: 30, TransitionStorageClass: "GLACIER"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := CheckLifecycleRules(test.rules); (err == nil) != test.isValid {
+				t.Fatalf("error %v, expected valid %v", err, test.isValid)
+			}
+		})
+	}
+}
+
+func TestIsOlderThan(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		days     int
+		now      time.Time
+		expected bool
+	}{
+		{days: 0, now: since.AddDate(1, 0, 0), expected: false},
+		{days: 1, now: since.Add(23 * time.Hour), expected: false},
+		{days: 1, now: since.Add(24 * time.Hour), expected: true},
+		{days: 30, now: since.AddDate(0, 0, 31), expected: true},
+	}
+
+	for _, test := range tests {
+		if older := IsOlderThan(since, test.days, test.now); older != test.expected {
+			t.Errorf("IsOlderThan(%d days, %v) = %v, expected %v", test.days, test.now, older, test.expected)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package cloud
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SortByName     = "name"
+	SortBySize     = "size"
+	SortByModified = "modified"
+)
+
+// MaxSortedFiles limits listing sorted other than by name ascending,
+// because such listing is loaded and sorted as a whole for every page.
+const MaxSortedFiles = 10000
+
+var ErrTooManyFiles = errors.New("too many files to sort, narrow listing by directory or filters")
+
+type ListFilesParams struct {
+	Prefix    string
+	Recursive bool
+	Limit     int
+	Cursor    string
+	Filter    ListFilesFilter
+	SortBy    string
+	SortDesc  bool
+}
+
+type ListFilesFilter struct {
+	Extensions    []string
+	MinSize       *int64
+	MaxSize       *int64
+	ModifiedAfter *time.Time
+	NameGlob      string
+	// Visible hides items from caller, like ones it has no access to.
+	// It is applied before paging, so pages are full if there are more items.
+	Visible func(item *StorageItem) bool
+}
+
+type FilesPage struct {
+	Items      []*StorageItem `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// filesCursor is opaque continuation token of files listing. Listing in
+// storage key order continues after last returned key, other orders
+// require whole listing to be sorted, so they continue from offset.
+type filesCursor struct {
+	After  string `json:"a,omitempty"`
+	Offset int    `json:"o,omitempty"`
+}
+
+func (fc *filesCursor) encode() string {
+	data, _ := json.Marshal(fc)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeFilesCursor(value string) (*filesCursor, error) {
+	cursor := &filesCursor{}
+	if value == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if err = json.Unmarshal(data, cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// FilesPager collects storage items of single listing page. Items must be
+// added in storage key order, so native listing may be stopped as soon
+// as Add returns false.
+type FilesPager struct {
+	params *ListFilesParams
+	cursor *filesCursor
+	items  []*StorageItem
+	err    error
+}
+
+func NewFilesPager(params *ListFilesParams) (*FilesPager, error) {
+	switch params.SortBy {
+	case "", SortByName, SortBySize, SortByModified:
+	default:
+		return nil, fmt.Errorf("unknown sort field: %s", params.SortBy)
+	}
+
+	if params.Filter.NameGlob != "" {
+		if _, err := path.Match(params.Filter.NameGlob, ""); err != nil {
+			return nil, fmt.Errorf("invalid name glob: %w", err)
+		}
+	}
+
+	cursor, err := decodeFilesCursor(params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	pager := &FilesPager{
+		params: params,
+		cursor: cursor,
+		items:  make([]*StorageItem, 0),
+	}
+
+	return pager, nil
+}
+
+// StartAfter returns storage key after which native listing may start.
+func (fp *FilesPager) StartAfter() string {
+	if !fp.isKeyOrder() {
+		return ""
+	}
+
+	return fp.cursor.After
+}
+
+func (fp *FilesPager) Add(item *StorageItem) bool {
+	if fp.isKeyOrder() && item.FileName <= fp.cursor.After {
+		return true
+	}
+
+	if !fp.params.Filter.Match(item) {
+		return true
+	}
+
+	fp.items = append(fp.items, item)
+	if !fp.isKeyOrder() {
+		if len(fp.items) > MaxSortedFiles {
+			fp.err = fmt.Errorf("%w: more than %d files", ErrTooManyFiles, MaxSortedFiles)
+			return false
+		}
+		return true
+	}
+
+	if fp.params.Limit <= 0 {
+		return true
+	}
+
+	return len(fp.items) <= fp.params.Limit
+}
+
+// Page returns collected page, it fails with ErrTooManyFiles if listing
+// has more than MaxSortedFiles matching files to sort.
+func (fp *FilesPager) Page() (*FilesPage, error) {
+	if fp.err != nil {
+		return nil, fp.err
+	}
+
+	limit := fp.params.Limit
+	if fp.isKeyOrder() {
+		page := &FilesPage{Items: fp.items}
+		if limit > 0 && len(fp.items) > limit {
+			page.Items = fp.items[:limit]
+			lastKey := page.Items[limit-1].FileName
+			page.NextCursor = (&filesCursor{After: lastKey}).encode()
+		}
+		return page, nil
+	}
+
+	SortFiles(fp.items, fp.params.SortBy, fp.params.SortDesc)
+
+	offset := min(fp.cursor.Offset, len(fp.items))
+	page := &FilesPage{Items: fp.items[offset:]}
+	if limit > 0 && len(page.Items) > limit {
+		page.Items = page.Items[:limit]
+		page.NextCursor = (&filesCursor{Offset: offset + limit}).encode()
+	}
+
+	return page, nil
+}
+
+func (fp *FilesPager) isKeyOrder() bool {
+	sortBy := fp.params.SortBy
+	return (sortBy == "" || sortBy == SortByName) && !fp.params.SortDesc
+}
+
+func (ff *ListFilesFilter) Match(item *StorageItem) bool {
+	if ff.Visible != nil && !ff.Visible(item) {
+		return false
+	}
+
+	if ff.NameGlob != "" {
+		baseName := path.Base(strings.TrimSuffix(item.FileName, "/"))
+		if matched, _ := path.Match(ff.NameGlob, baseName); !matched {
+			return false
+		}
+	}
+
+	// Other filters describe file content, so directories never match them.
+	if !ff.hasFileFilters() {
+		return true
+	}
+
+	if item.IsDirectory {
+		return false
+	}
+
+	if len(ff.Extensions) > 0 && !ff.matchExtension(item.FileName) {
+		return false
+	}
+
+	if ff.MinSize != nil && item.Size < *ff.MinSize {
+		return false
+	}
+
+	if ff.MaxSize != nil && item.Size > *ff.MaxSize {
+		return false
+	}
+
+	if ff.ModifiedAfter != nil && !item.LastModified.After(*ff.ModifiedAfter) {
+		return false
+	}
+
+	return true
+}
+
+func (ff *ListFilesFilter) hasFileFilters() bool {
+	return len(ff.Extensions) > 0 ||
+		ff.MinSize != nil ||
+		ff.MaxSize != nil ||
+		ff.ModifiedAfter != nil
+}
+
+func (ff *ListFilesFilter) matchExtension(filePath string) bool {
+	fileExt := strings.TrimPrefix(path.Ext(filePath), ".")
+	for _, ext := range ff.Extensions {
+		if strings.EqualFold(strings.TrimPrefix(ext, "."), fileExt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func SortFiles(items []*StorageItem, sortBy string, desc bool) {
+	less := func(a, b *StorageItem) bool {
+		switch sortBy {
+		case SortBySize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case SortByModified:
+			if !a.LastModified.Equal(b.LastModified) {
+				return a.LastModified.Before(b.LastModified)
+			}
+		}
+		return a.FileName < b.FileName
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+}
@@ -0,0 +1,195 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testItems() []*StorageItem {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*StorageItem{
+		{FileName: ".trash/x.txt", Size: 1, LastModified: base},
+		{FileName: "a.txt", Size: 30, LastModified: base.Add(3 * time.Hour)},
+		{FileName: "b.pdf", Size: 10, LastModified: base.Add(1 * time.Hour)},
+		{FileName: "c.txt", Size: 50, LastModified: base.Add(5 * time.Hour)},
+		{FileName: "d.pdf", Size: 20, LastModified: base.Add(2 * time.Hour)},
+		{FileName: "e.txt", Size: 40, LastModified: base.Add(4 * time.Hour)},
+	}
+}
+
+// listPages lists items page by page like backends do, items are added
+// in key order after start key until pager is full.
+func listPages(t *testing.T, items []*StorageItem, params *ListFilesParams) [][]string {
+	t.Helper()
+
+	pages := make([][]string, 0)
+	for range len(items) + 1 {
+		pager, err := NewFilesPager(params)
+		if err != nil {
+			t.Fatalf("failed to create pager: %v", err)
+		}
+
+		for _, item := range items {
+			if item.FileName <= pager.StartAfter() {
+				continue
+			}
+			if !pager.Add(item) {
+				break
+			}
+		}
+
+		page, err := pager.Page()
+		if err != nil {
+			t.Fatalf("failed to get page: %v", err)
+		}
+		names := make([]string, 0, len(page.Items))
+		for _, item := range page.Items {
+			names = append(names, item.FileName)
+		}
+		pages = append(pages, names)
+
+		if page.NextCursor == "" {
+			return pages
+		}
+		params.Cursor = page.NextCursor
+	}
+
+	t.Fatalf("listing has not finished after %d pages", len(pages))
+	return nil
+}
+
+func TestFilesPagerCursor(t *testing.T) {
+	isNotTrash := func(item *StorageItem) bool {
+		return !strings.HasPrefix(item.FileName, ".trash/")
+	}
+	minSize := int64(20)
+
+	tests := []struct {
+		name     string
+		params   ListFilesParams
+		expected [][]string
+	}{
+		{
+			name:     "key order",
+			params:   ListFilesParams{Limit: 2},
+			expected: [][]string{{".trash/x.txt", "a.txt"}, {"b.pdf", "c.txt"}, {"d.pdf", "e.txt"}},
+		},
+		{
+			name:     "last page is full",
+			params:   ListFilesParams{Limit: 3},
+			expected: [][]string{{".trash/x.txt", "a.txt", "b.pdf"}, {"c.txt", "d.pdf", "e.txt"}},
+		},
+		{
+			name:     "no limit",
+			params:   ListFilesParams{},
+			expected: [][]string{{".trash/x.txt", "a.txt", "b.pdf", "c.txt", "d.pdf", "e.txt"}},
+		},
+		{
+			name: "hidden items do not shorten pages",
+			params: ListFilesParams{
+				Limit:  2,
+				Filter: ListFilesFilter{Visible: isNotTrash},
+			},
+			expected: [][]string{{"a.txt", "b.pdf"}, {"c.txt", "d.pdf"}, {"e.txt"}},
+		},
+		{
+			name: "filtered by extension",
+			params: ListFilesParams{
+				Limit:  2,
+				Filter: ListFilesFilter{Extensions: []string{"txt"}, Visible: isNotTrash},
+			},
+			expected: [][]string{{"a.txt", "c.txt"}, {"e.txt"}},
+		},
+		{
+			name: "sorted by size",
+			params: ListFilesParams{
+				Limit:  2,
+				SortBy: SortBySize,
+				Filter: ListFilesFilter{MinSize: &minSize},
+			},
+			expected: [][]string{{"d.pdf", "a.txt"}, {"e.txt", "c.txt"}},
+		},
+		{
+			name: "sorted by modified desc",
+			params: ListFilesParams{
+				Limit:    4,
+				SortBy:   SortByModified,
+				SortDesc: true,
+				Filter:   ListFilesFilter{Visible: isNotTrash},
+			},
+			expected: [][]string{{"c.txt", "e.txt", "a.txt", "d.pdf"}, {"b.pdf"}},
+		},
+		{
+			name:     "sorted by name desc",
+			params:   ListFilesParams{Limit: 5, SortBy: SortByName, SortDesc: true},
+			expected: [][]string{{"e.txt", "d.pdf", "c.txt", "b.pdf", "a.txt"}, {".trash/x.txt"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := test.params
+			pages := listPages(t, testItems(), &params)
+			if !slices.EqualFunc(pages, test.expected, slices.Equal[[]string]) {
+				t.Fatalf("pages %v, expected %v", pages, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewFilesPagerInvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ListFilesParams
+	}{
+		{name: "malformed cursor", params: ListFilesParams{Cursor: "%%%"}},
+		{name: "cursor is not json", params: ListFilesParams{Cursor: "bm90LWpzb24"}},
+		{name: "unknown sort field", params: ListFilesParams{SortBy: "owner"}},
+		{name: "invalid name glob", params: ListFilesParams{Filter: ListFilesFilter{NameGlob: "["}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewFilesPager(&test.params); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestFilesPagerLimitsSortedFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ListFilesParams
+		count  int
+		err    error
+	}{
+		{name: "sorted at limit", params: ListFilesParams{SortBy: SortBySize, Limit: 10}, count: MaxSortedFiles},
+		{name: "sorted over limit", params: ListFilesParams{SortBy: SortBySize, Limit: 10}, count: MaxSortedFiles + 1, err: ErrTooManyFiles},
+		{name: "descending names over limit", params: ListFilesParams{SortDesc: true}, count: MaxSortedFiles + 1, err: ErrTooManyFiles},
+		{name: "names over limit", params: ListFilesParams{Limit: 10}, count: MaxSortedFiles + 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pager, err := NewFilesPager(&test.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range test.count {
+				if !pager.Add(&StorageItem{FileName: fmt.Sprintf("%06d.txt", i)}) {
+					break
+				}
+			}
+
+			if _, err = pager.Page(); !errors.Is(err, test.err) {
+				t.Fatalf("error %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
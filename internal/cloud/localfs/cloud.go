@@ -0,0 +1,663 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+const (
+	metaDirName   = ".meta"
+	uploadPattern = ".upload-*"
+)
+
+type rangeReader struct {
+	io.Reader
+	io.Closer
+}
+
+type LocalFS struct {
+	config *cloud.CloudConfig
+	signer *cloud.URLSigner
+	root   string
+}
+
+func New(config *cloud.CloudConfig) *cloud.DocumentHub {
+	// Files outlive restart, so must their share URLs.
+	if config.ShareSecret == "" {
+		log.Fatalln("share secret is required by local storage")
+	}
+
+	rootPath, err := filepath.Abs(config.RootPath)
+	if err != nil {
+		log.Fatalln("failed to resolve local storage root: ", err.Error())
+	}
+
+	if err = os.MkdirAll(filepath.Join(rootPath, metaDirName), 0o755); err != nil {
+		log.Fatalln("failed to create local storage root: ", err.Error())
+	}
+
+	localFS := &LocalFS{
+		config: config,
+		signer: cloud.NewURLSigner(config),
+		root:   rootPath,
+	}
+
+	return &cloud.DocumentHub{Cloud: localFS}
+}
+
+func (fs *LocalFS) GetBuckets(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(fs.root)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketNames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		bucketNames = append(bucketNames, entry.Name())
+	}
+
+	return bucketNames, nil
+}
+
+func (fs *LocalFS) CreateBucket(_ context.Context, bucket string) error {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(bucketPath); err == nil {
+		return fmt.Errorf("bucket %s already exists", bucket)
+	}
+
+	return os.Mkdir(bucketPath, 0o755)
+}
+
+func (fs *LocalFS) RemoveBucket(_ context.Context, bucket string) error {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(bucketPath)
+	if err != nil {
+		return err
+	}
+
+	versionsDir := filepath.Join(fs.root, versionsDirName, bucket)
+	versions, err := os.ReadDir(versionsDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(entries) > 0 || len(versions) > 0 {
+		return fmt.Errorf("%w: %s", cloud.ErrBucketNotEmpty, bucket)
+	}
+
+	if err = os.Remove(bucketPath); err != nil {
+		return err
+	}
+
+	if err = os.RemoveAll(versionsDir); err != nil {
+		return err
+	}
+
+	if err = os.RemoveAll(fs.bucketConfigPath(bucket)); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(filepath.Join(fs.root, metaDirName, bucket))
+}
+
+// PurgeBucket removes all objects of bucket with their metadata,
+// versions and incomplete uploads, but keeps bucket itself.
+func (fs *LocalFS) PurgeBucket(_ context.Context, bucket string) error {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(bucketPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err = os.RemoveAll(filepath.Join(bucketPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	if err = os.RemoveAll(filepath.Join(fs.root, metaDirName, bucket)); err != nil {
+		return err
+	}
+
+	if err = os.RemoveAll(filepath.Join(fs.root, versionsDirName, bucket)); err != nil {
+		return err
+	}
+
+	return fs.abortUploads(bucket)
+}
+
+func (fs *LocalFS) IsBucketExist(_ context.Context, bucket string) (bool, error) {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(bucketPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return info.IsDir(), nil
+}
+
+func (fs *LocalFS) GetFiles(_ context.Context, bucket string, params *cloud.ListFilesParams) (*cloud.FilesPage, error) {
+	pager, err := cloud.NewFilesPager(params)
+	if err != nil {
+		return nil, err
+	}
+
+	dirObjects, err := fs.listObjects(bucket, params.Prefix, params.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range dirObjects {
+		if !pager.Add(item) {
+			break
+		}
+	}
+
+	return pager.Page()
+}
+
+// listObjects returns objects with key prefix sorted by key like S3 does.
+// Nested directories are collapsed into single item unless recursive.
+func (fs *LocalFS) listObjects(bucket, prefix string, recursive bool) ([]*cloud.StorageItem, error) {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	dirPrefix, _ := path.Split(prefix)
+	dirPath, err := fs.objectPath(bucket, dirPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = os.Stat(bucketPath); err != nil {
+		return nil, fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	dirObjects := make([]*cloud.StorageItem, 0)
+	walkErr := filepath.WalkDir(dirPath, func(walkPath string, entry os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if walkPath == dirPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dirPath, walkPath)
+		if err != nil {
+			return err
+		}
+
+		objKey := dirPrefix + filepath.ToSlash(relPath)
+		if entry.Name() == folderMarker {
+			folderKey := cloud.FolderKey(path.Dir(objKey))
+			if recursive && strings.HasPrefix(folderKey, prefix) {
+				dirObjects = append(dirObjects, &cloud.StorageItem{
+					FileName:      folderKey,
+					DirectoryName: cloud.DirectoryOf(folderKey),
+					IsDirectory:   true,
+				})
+			}
+			return nil
+		}
+
+		if isTemp, _ := filepath.Match(uploadPattern, entry.Name()); isTemp {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if !strings.HasPrefix(objKey+"/", prefix) {
+				return filepath.SkipDir
+			}
+			if recursive {
+				return nil
+			}
+			if strings.HasPrefix(objKey, prefix) {
+				dirObjects = append(dirObjects, &cloud.StorageItem{
+					FileName:      objKey + "/",
+					DirectoryName: prefix,
+					IsDirectory:   true,
+				})
+			}
+			return filepath.SkipDir
+		}
+
+		if !strings.HasPrefix(objKey, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			log.Println("failed to get object: ", objKey, err)
+			return nil
+		}
+
+		item := fs.storageItem(bucket, objKey, info)
+		if !recursive {
+			item.DirectoryName = prefix
+		}
+		dirObjects = append(dirObjects, item)
+		return nil
+	})
+
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	sort.Slice(dirObjects, func(i, j int) bool {
+		return dirObjects[i].FileName < dirObjects[j].FileName
+	})
+
+	return dirObjects, nil
+}
+
+func (fs *LocalFS) RemoveFile(_ context.Context, bucket, filePath string) error {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	err = fs.versionedWrite(bucket, filePath, true, func() error {
+		return os.Remove(objPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = fs.removeMeta(bucket, filePath); err != nil {
+		log.Println("failed to remove file metadata: ", filePath, err)
+	}
+
+	fs.pruneDirs(bucket, filepath.Dir(objPath))
+	return nil
+}
+
+func (fs *LocalFS) UploadFile(_ context.Context, bucket, filePath string, data io.Reader, _ int64) error {
+	if err := fs.writeFile(bucket, filePath, data, false); err != nil {
+		return err
+	}
+
+	return fs.removeMeta(bucket, filePath)
+}
+
+func (fs *LocalFS) UploadNewFile(_ context.Context, bucket, filePath string, data io.Reader, _ int64) error {
+	if err := fs.writeFile(bucket, filePath, data, true); err != nil {
+		return err
+	}
+
+	return fs.removeMeta(bucket, filePath)
+}
+
+func (fs *LocalFS) CopyFile(_ context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return fs.copyFile(srcBucket, srcPath, dstBucket, dstPath, false)
+}
+
+func (fs *LocalFS) CopyNewFile(_ context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return fs.copyFile(srcBucket, srcPath, dstBucket, dstPath, true)
+}
+
+func (fs *LocalFS) copyFile(srcBucket, srcPath, dstBucket, dstPath string, isNew bool) error {
+	srcFile, err := fs.filePath(srcBucket, srcPath)
+	if err != nil {
+		return err
+	}
+
+	srcHandler, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := srcHandler.Close(); err != nil {
+			log.Println("failed to close file handler: ", srcPath, err)
+		}
+	}()
+
+	if err = fs.writeFile(dstBucket, dstPath, srcHandler, isNew); err != nil {
+		return err
+	}
+
+	return fs.copyMeta(srcBucket, srcPath, dstBucket, dstPath)
+}
+
+func (fs *LocalFS) MoveFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return fs.moveFile(ctx, srcBucket, srcPath, dstBucket, dstPath, false)
+}
+
+func (fs *LocalFS) MoveNewFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	return fs.moveFile(ctx, srcBucket, srcPath, dstBucket, dstPath, true)
+}
+
+func (fs *LocalFS) moveFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string, isNew bool) error {
+	srcFile, err := fs.filePath(srcBucket, srcPath)
+	if err != nil {
+		return err
+	}
+
+	dstFile, err := fs.filePath(dstBucket, dstPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(srcFile); err != nil {
+		return err
+	}
+
+	exist, err := fs.IsBucketExist(ctx, dstBucket)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("bucket %s does not exist", dstBucket)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dstFile), 0o755); err != nil {
+		return err
+	}
+
+	err = fs.versionedWrite(dstBucket, dstPath, false, func() error {
+		return fs.versionedWrite(srcBucket, srcPath, true, func() error {
+			if !isNew {
+				return os.Rename(srcFile, dstFile)
+			}
+
+			if err := linkNew(srcFile, dstFile, dstPath); err != nil {
+				return err
+			}
+			if err := os.Remove(srcFile); err != nil {
+				_ = os.Remove(dstFile)
+				return err
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = fs.copyMeta(srcBucket, srcPath, dstBucket, dstPath); err != nil {
+		return err
+	}
+
+	if err = fs.removeMeta(srcBucket, srcPath); err != nil {
+		log.Println("failed to remove file metadata: ", srcPath, err)
+	}
+
+	fs.pruneDirs(srcBucket, filepath.Dir(srcFile))
+	return nil
+}
+
+func (fs *LocalFS) DownloadFile(_ context.Context, bucket, filePath string) (io.ReadCloser, error) {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(objPath)
+}
+
+func (fs *LocalFS) DownloadRange(_ context.Context, bucket, filePath string, offset, length int64) (io.ReadCloser, error) {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fileHandler, err := os.Open(objPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = fileHandler.Seek(offset, io.SeekStart); err != nil {
+		_ = fileHandler.Close()
+		return nil, err
+	}
+
+	return &rangeReader{
+		Reader: io.LimitReader(fileHandler, length),
+		Closer: fileHandler,
+	}, nil
+}
+
+func (fs *LocalFS) StatFile(_ context.Context, bucket, filePath string) (*cloud.StorageItem, error) {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(objPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return nil, fmt.Errorf("object %s does not exist", filePath)
+	}
+
+	return fs.storageItem(bucket, filePath, info), nil
+}
+
+func (fs *LocalFS) UploadExpired(_ context.Context, bucket, filePath string, expired time.Time, data io.Reader, _ int64) error {
+	if err := fs.writeFile(bucket, filePath, data, false); err != nil {
+		return err
+	}
+
+	return fs.writeMeta(bucket, filePath, &fileMeta{Expires: &expired})
+}
+
+func (fs *LocalFS) SetFileExpiry(_ context.Context, bucket, filePath string, expiresAt *time.Time) error {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(objPath); err != nil {
+		return fmt.Errorf("object %s does not exist", filePath)
+	}
+
+	meta, err := fs.readMeta(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	meta.Expires = expiresAt
+	if meta.isEmpty() {
+		return fs.removeMeta(bucket, filePath)
+	}
+
+	return fs.writeMeta(bucket, filePath, meta)
+}
+
+func (fs *LocalFS) RemoveExpired(ctx context.Context, bucket string, now time.Time) (int, error) {
+	items, err := fs.listObjects(bucket, "", true)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, item := range items {
+		if item.IsDirectory || item.ExpiresAt == nil || now.Before(*item.ExpiresAt) {
+			continue
+		}
+
+		if err = fs.RemoveFile(ctx, bucket, item.FileName); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// fileETag builds etag from modification time and size which change
+// on every write, because files have no content hash stored.
+func fileETag(info os.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())
+}
+
+func (fs *LocalFS) storageItem(bucket, filePath string, info os.FileInfo) *cloud.StorageItem {
+	item := &cloud.StorageItem{
+		FileName:      filePath,
+		DirectoryName: cloud.DirectoryOf(filePath),
+		IsDirectory:   false,
+		Size:          info.Size(),
+		LastModified:  info.ModTime().UTC(),
+		ETag:          fileETag(info),
+		ContentType:   cloud.ContentTypeOf(filePath),
+		StorageClass:  cloud.DefaultStorageClass,
+	}
+
+	meta, err := fs.readMeta(bucket, filePath)
+	if err != nil {
+		log.Println("failed to read file metadata: ", filePath, err)
+		return item
+	}
+
+	item.UserMetadata = meta.UserMetadata
+	item.ExpiresAt = meta.Expires
+	if meta.StorageClass != "" {
+		item.StorageClass = meta.StorageClass
+	}
+	return item
+}
+
+// bucketPath returns directory path of bucket, rejecting names
+// which may escape storage root or collide with service directories.
+func (fs *LocalFS) bucketPath(bucket string) (string, error) {
+	if bucket == "" || strings.HasPrefix(bucket, ".") || strings.ContainsAny(bucket, `/\`) {
+		return "", fmt.Errorf("invalid bucket name: %s", bucket)
+	}
+
+	return filepath.Join(fs.root, bucket), nil
+}
+
+// objectPath resolves object key inside bucket directory. Keys with dot
+// or empty segments are rejected, so that they never point outside of
+// bucket and never differ from keys callers have checked access to.
+func (fs *LocalFS) objectPath(bucket, filePath string) (string, error) {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	if err = cloud.CheckFilePath(filePath); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(bucketPath, filepath.FromSlash(filePath)), nil
+}
+
+func (fs *LocalFS) filePath(bucket, filePath string) (string, error) {
+	if filePath == "" || strings.HasSuffix(filePath, "/") || path.Base(filePath) == folderMarker {
+		return "", fmt.Errorf("invalid file path: %s", filePath)
+	}
+
+	return fs.objectPath(bucket, filePath)
+}
+
+// writeFile replaces object atomically, or stores it only if its path
+// is free when isNew is set.
+func (fs *LocalFS) writeFile(bucket, filePath string, data io.Reader, isNew bool) error {
+	exist, err := fs.IsBucketExist(context.Background(), bucket)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(objPath), 0o755); err != nil {
+		return err
+	}
+
+	// Write to temporary file first to replace object atomically.
+	tmpFile, err := os.CreateTemp(filepath.Dir(objPath), uploadPattern)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	if _, err = io.Copy(tmpFile, data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return fs.versionedWrite(bucket, filePath, false, func() error {
+		if isNew {
+			return linkNew(tmpFile.Name(), objPath, filePath)
+		}
+		return os.Rename(tmpFile.Name(), objPath)
+	})
+}
+
+// linkNew links file to object path only if it is free. Hard link fails
+// if path exists like O_EXCL open does, so concurrent writer which has
+// stored object meanwhile is never replaced.
+func linkNew(srcFile, objPath, filePath string) error {
+	err := os.Link(srcFile, objPath)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w: %s", cloud.ErrFileExists, filePath)
+	}
+
+	return err
+}
+
+// pruneDirs removes empty parent directories up to bucket root
+// because object storage does not keep empty folders.
+func (fs *LocalFS) pruneDirs(bucket, dirPath string) {
+	bucketPath, err := fs.bucketPath(bucket)
+	if err != nil {
+		return
+	}
+
+	for dirPath != bucketPath && strings.HasPrefix(dirPath, bucketPath) {
+		if err := os.Remove(dirPath); err != nil {
+			return
+		}
+		dirPath = filepath.Dir(dirPath)
+	}
+}
@@ -0,0 +1,76 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"docs-hub/internal/cloud"
+)
+
+// folderMarker is empty file which keeps folder created by user,
+// otherwise empty directories are pruned like they are not exist.
+const folderMarker = ".folder"
+
+func (fs *LocalFS) CreateFolder(ctx context.Context, bucket, folderPath string) error {
+	folderKey := cloud.FolderKey(folderPath)
+	if folderKey == "" {
+		return fmt.Errorf("invalid folder path: %s", folderPath)
+	}
+
+	exist, err := fs.IsBucketExist(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	dirPath, err := fs.objectPath(bucket, folderKey)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dirPath, 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dirPath, folderMarker), nil, 0o644)
+}
+
+func (fs *LocalFS) RemoveFiles(ctx context.Context, bucket string, filePaths []string) map[string]error {
+	removeErrs := make(map[string]error)
+	for _, filePath := range filePaths {
+		var err error
+		if cloud.IsFolderKey(filePath) {
+			err = fs.removeFolder(bucket, filePath)
+		} else {
+			err = fs.RemoveFile(ctx, bucket, filePath)
+		}
+
+		if err != nil {
+			removeErrs[filePath] = err
+		}
+	}
+
+	return removeErrs
+}
+
+// removeFolder removes folder marker, folder itself is removed
+// as soon as it has no files like any other directory.
+func (fs *LocalFS) removeFolder(bucket, folderKey string) error {
+	dirPath, err := fs.objectPath(bucket, folderKey)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(dirPath, folderMarker))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	fs.pruneDirs(bucket, dirPath)
+	return nil
+}
@@ -0,0 +1,209 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+func (fs *LocalFS) SetBucketLifecycle(ctx context.Context, bucket string, rules []*cloud.LifecycleRule) error {
+	if err := cloud.CheckLifecycleRules(rules); err != nil {
+		return err
+	}
+
+	exist, err := fs.IsBucketExist(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	config, err := fs.readBucketConfig(bucket)
+	if err != nil {
+		return err
+	}
+
+	config.Lifecycle = rules
+	return fs.writeBucketConfig(bucket, config)
+}
+
+func (fs *LocalFS) GetBucketLifecycle(ctx context.Context, bucket string) ([]*cloud.LifecycleRule, error) {
+	exist, err := fs.IsBucketExist(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	config, err := fs.readBucketConfig(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Lifecycle == nil {
+		return make([]*cloud.LifecycleRule, 0), nil
+	}
+
+	return config.Lifecycle, nil
+}
+
+func (fs *LocalFS) ApplyLifecycle(ctx context.Context, bucket string, now time.Time) error {
+	config, err := fs.readBucketConfig(bucket)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range config.Lifecycle {
+		if !rule.IsEnabled() {
+			continue
+		}
+
+		if err = fs.applyCurrent(ctx, bucket, rule, now); err != nil {
+			return err
+		}
+
+		if err = fs.expireNoncurrent(bucket, rule, now); err != nil {
+			return err
+		}
+
+		if err = fs.abortExpiredUploads(bucket, rule, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyCurrent expires or transitions current objects matched by rule.
+func (fs *LocalFS) applyCurrent(ctx context.Context, bucket string, rule *cloud.LifecycleRule, now time.Time) error {
+	if rule.ExpirationDays == 0 && rule.TransitionDays == 0 {
+		return nil
+	}
+
+	items, err := fs.listObjects(bucket, rule.Prefix, true)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.IsDirectory {
+			continue
+		}
+
+		if cloud.IsOlderThan(item.LastModified, rule.ExpirationDays, now) {
+			if err = fs.RemoveFile(ctx, bucket, item.FileName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !cloud.IsOlderThan(item.LastModified, rule.TransitionDays, now) ||
+			item.StorageClass == rule.TransitionStorageClass {
+			continue
+		}
+
+		meta, err := fs.readMeta(bucket, item.FileName)
+		if err != nil {
+			return err
+		}
+
+		meta.StorageClass = rule.TransitionStorageClass
+		if err = fs.writeMeta(bucket, item.FileName, meta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expireNoncurrent removes versions which have become noncurrent, when
+// newer version has been written, earlier than rule days ago.
+func (fs *LocalFS) expireNoncurrent(bucket string, rule *cloud.LifecycleRule, now time.Time) error {
+	if rule.NoncurrentExpirationDays == 0 {
+		return nil
+	}
+
+	dirEntries, err := os.ReadDir(filepath.Join(fs.root, versionsDirName, bucket))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, dirEntry := range dirEntries {
+		versionsDir := filepath.Join(fs.root, versionsDirName, bucket, dirEntry.Name())
+		objKey, err := os.ReadFile(filepath.Join(versionsDir, versionsKey))
+		if err != nil || !rule.Matches(string(objKey)) {
+			continue
+		}
+
+		entries, err := fs.currentVersions(bucket, string(objKey))
+		if err != nil {
+			return err
+		}
+
+		kept := make([]*versionEntry, 0, len(entries))
+		kept = append(kept, entries[:min(len(entries), 1)]...)
+		for index := 1; index < len(entries); index++ {
+			if !cloud.IsOlderThan(entries[index-1].LastModified, rule.NoncurrentExpirationDays, now) {
+				kept = append(kept, entries[index])
+				continue
+			}
+
+			err = os.Remove(filepath.Join(versionsDir, entries[index].VersionID))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+
+		if len(kept) == len(entries) {
+			continue
+		}
+
+		if err = fs.writeVersions(bucket, string(objKey), kept); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fs *LocalFS) abortExpiredUploads(bucket string, rule *cloud.LifecycleRule, now time.Time) error {
+	if rule.AbortIncompleteUploadDays == 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(fs.root, uploadsDirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		uploadDir := fs.uploadDir(entry.Name())
+		session, err := readSession(uploadDir)
+		if err != nil || session.Bucket != bucket || !rule.Matches(session.FilePath) {
+			continue
+		}
+
+		if !cloud.IsOlderThan(session.Initiated, rule.AbortIncompleteUploadDays, now) {
+			continue
+		}
+
+		if err = os.RemoveAll(uploadDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
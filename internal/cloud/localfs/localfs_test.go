@@ -0,0 +1,453 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+// newTestFS returns local cloud in temporary directory with buckets b1 and b2.
+func newTestFS(t *testing.T) *LocalFS {
+	t.Helper()
+
+	fs := New(&cloud.CloudConfig{ShareSecret: "test", RootPath: t.TempDir()}).Cloud.(*LocalFS)
+	for _, bucket := range []string{"b1", "b2"} {
+		if err := fs.CreateBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("failed to create bucket %s: %v", bucket, err)
+		}
+	}
+
+	return fs
+}
+
+func uploadText(t *testing.T, fs *LocalFS, bucket, filePath, content string) {
+	t.Helper()
+
+	err := fs.UploadFile(context.Background(), bucket, filePath, strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("failed to upload %s/%s: %v", bucket, filePath, err)
+	}
+}
+
+func readText(t *testing.T, fs *LocalFS, bucket, filePath string) (string, error) {
+	t.Helper()
+
+	fileData, err := fs.DownloadFile(context.Background(), bucket, filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = fileData.Close() }()
+
+	data, err := io.ReadAll(fileData)
+	return string(data), err
+}
+
+func TestBuckets(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+
+	buckets, err := fs.GetBuckets(ctx)
+	if err != nil || strings.Join(buckets, ",") != "b1,b2" {
+		t.Fatalf("buckets %v, error %v", buckets, err)
+	}
+
+	if err = fs.CreateBucket(ctx, "b1"); err == nil {
+		t.Fatal("existing bucket has been created again")
+	}
+
+	uploadText(t, fs, "b1", "a.txt", "a")
+	if err = fs.RemoveBucket(ctx, "b1"); err == nil {
+		t.Fatal("bucket with files has been removed")
+	}
+	if err = fs.RemoveBucket(ctx, "b2"); err != nil {
+		t.Fatalf("failed to remove empty bucket: %v", err)
+	}
+
+	if exist, err := fs.IsBucketExist(ctx, "b2"); err != nil || exist {
+		t.Fatalf("removed bucket exists: %v, error %v", exist, err)
+	}
+}
+
+func TestUploadDownloadAndRemove(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+	uploadText(t, fs, "b1", "docs/2024/a.txt", "0123456789")
+
+	if content, err := readText(t, fs, "b1", "docs/2024/a.txt"); err != nil || content != "0123456789" {
+		t.Fatalf("content %q, error %v", content, err)
+	}
+
+	item, err := fs.StatFile(ctx, "b1", "docs/2024/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Size != 10 || item.DirectoryName != "docs/2024/" || item.ContentType != "text/plain; charset=utf-8" {
+		t.Fatalf("unexpected item %+v", item)
+	}
+
+	fileData, err := fs.DownloadRange(ctx, "b1", "docs/2024/a.txt", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(fileData)
+	_ = fileData.Close()
+	if string(data) != "234" {
+		t.Fatalf("range content %q", data)
+	}
+
+	if _, err = fs.StatFile(ctx, "b1", "docs/2024"); err == nil {
+		t.Fatal("directory is reported as file")
+	}
+
+	if err = fs.RemoveFile(ctx, "b1", "docs/2024/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.StatFile(ctx, "b1", "docs/2024/a.txt"); err == nil {
+		t.Fatal("removed file still exists")
+	}
+
+	page, err := fs.GetFiles(ctx, "b1", &cloud.ListFilesParams{Recursive: true})
+	if err != nil || len(page.Items) != 0 {
+		t.Fatalf("empty directories are left after remove: %+v, error %v", page, err)
+	}
+}
+
+func TestInvalidFilePath(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+	uploadText(t, fs, "b2", "secret.txt", "secret")
+
+	tests := []string{"../b2/secret.txt", "docs/../../b2/secret.txt", "./secret.txt", "docs//secret.txt", "/secret.txt"}
+	for _, filePath := range tests {
+		t.Run(filePath, func(t *testing.T) {
+			if _, err := fs.StatFile(ctx, "b1", filePath); !errors.Is(err, cloud.ErrInvalidFilePath) {
+				t.Fatalf("stat: expected ErrInvalidFilePath, got %v", err)
+			}
+			if _, err := fs.DownloadFile(ctx, "b1", filePath); !errors.Is(err, cloud.ErrInvalidFilePath) {
+				t.Fatalf("download: expected ErrInvalidFilePath, got %v", err)
+			}
+			err := fs.UploadFile(ctx, "b1", filePath, strings.NewReader("x"), 1)
+			if !errors.Is(err, cloud.ErrInvalidFilePath) {
+				t.Fatalf("upload: expected ErrInvalidFilePath, got %v", err)
+			}
+			if err = fs.RemoveFile(ctx, "b1", filePath); !errors.Is(err, cloud.ErrInvalidFilePath) {
+				t.Fatalf("remove: expected ErrInvalidFilePath, got %v", err)
+			}
+		})
+	}
+
+	if content, err := readText(t, fs, "b2", "secret.txt"); err != nil || content != "secret" {
+		t.Fatalf("file of other bucket has been changed: %q, %v", content, err)
+	}
+}
+
+func TestShareURL(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+	uploadText(t, fs, "b1", "a.txt", "a")
+
+	if _, err := fs.GetShareURL(ctx, "b1", "missing.txt", time.Minute); err == nil {
+		t.Fatal("share url of missing file has been created")
+	}
+
+	shareURL, err := fs.GetShareURL(ctx, "b1", "a.txt", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedURL, err := url.Parse(shareURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedURL.Path != "/cloud/b1/share/a.txt" {
+		t.Fatalf("share url path %q", parsedURL.Path)
+	}
+
+	expires, _ := strconv.ParseInt(parsedURL.Query().Get("expires"), 10, 64)
+	signature := parsedURL.Query().Get("signature")
+	if err = fs.VerifyShareURL(ctx, "b1", "a.txt", expires, signature); err != nil {
+		t.Fatalf("valid share url is rejected: %v", err)
+	}
+	if err = fs.VerifyShareURL(ctx, "b1", "b.txt", expires, signature); err == nil {
+		t.Fatal("signature is accepted for other file")
+	}
+	if err = fs.VerifyShareURL(ctx, "b1", "a.txt", expires+60, signature); err == nil {
+		t.Fatal("signature is accepted for other expiry")
+	}
+
+	// Other instance with the same secret accepts URL, as docs-hub
+	// after restart does.
+	restarted := New(&cloud.CloudConfig{ShareSecret: "test", RootPath: fs.root}).Cloud.(*LocalFS)
+	if err = restarted.VerifyShareURL(ctx, "b1", "a.txt", expires, signature); err != nil {
+		t.Fatalf("share url is rejected after restart: %v", err)
+	}
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+
+	uploadID, err := fs.CreateUpload(ctx, "b1", "docs/big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parts are concatenated by number, not by order of upload.
+	for _, part := range []struct {
+		number int
+		data   string
+	}{{number: 3, data: "ccc"}, {number: 1, data: "a"}, {number: 2, data: "bb"}, {number: 1, data: "aa"}} {
+		_, err = fs.UploadPart(ctx, "b1", "docs/big.bin", uploadID, part.number, strings.NewReader(part.data), int64(len(part.data)))
+		if err != nil {
+			t.Fatalf("failed to upload part %d: %v", part.number, err)
+		}
+	}
+
+	if _, err = fs.UploadPart(ctx, "b1", "docs/big.bin", uploadID, 0, strings.NewReader("x"), 1); err == nil {
+		t.Fatal("part 0 has been uploaded")
+	}
+	if _, err = fs.ListUploadParts(ctx, "b1", "docs/other.bin", uploadID); err == nil {
+		t.Fatal("upload is found by other file path")
+	}
+
+	parts, err := fs.ListUploadParts(ctx, "b1", "docs/big.bin", uploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := make([]int64, 0, len(parts))
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.ETag == "" {
+			t.Fatalf("unexpected part %+v", part)
+		}
+		sizes = append(sizes, part.Size)
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 3 {
+		t.Fatalf("part sizes %v", sizes)
+	}
+
+	if _, err = fs.StatFile(ctx, "b1", "docs/big.bin"); err == nil {
+		t.Fatal("file exists before upload is completed")
+	}
+
+	if err = fs.CompleteUpload(ctx, "b1", "docs/big.bin", uploadID); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := readText(t, fs, "b1", "docs/big.bin"); err != nil || content != "aabbccc" {
+		t.Fatalf("content %q, error %v", content, err)
+	}
+	if _, err = fs.ListUploadParts(ctx, "b1", "docs/big.bin", uploadID); err == nil {
+		t.Fatal("completed upload still exists")
+	}
+}
+
+func TestMultipartUploadAbort(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+
+	if _, err := fs.CreateUpload(ctx, "b3", "a.bin"); err == nil {
+		t.Fatal("upload has been created in missing bucket")
+	}
+
+	uploadID, err := fs.CreateUpload(ctx, "b1", "a.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = fs.CompleteUpload(ctx, "b1", "a.bin", uploadID); err == nil {
+		t.Fatal("upload without parts has been completed")
+	}
+
+	_, err = fs.UploadPart(ctx, "b1", "a.bin", uploadID, 1, strings.NewReader("a"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = fs.AbortUpload(ctx, "b1", "a.bin", uploadID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = fs.CompleteUpload(ctx, "b1", "a.bin", uploadID); err == nil {
+		t.Fatal("aborted upload has been completed")
+	}
+	if _, err = fs.StatFile(ctx, "b1", "a.bin"); err == nil {
+		t.Fatal("aborted upload has created file")
+	}
+}
+
+func listVersions(t *testing.T, fs *LocalFS, bucket, filePath string) []*cloud.FileVersion {
+	t.Helper()
+
+	versions, err := fs.ListFileVersions(context.Background(), bucket, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return versions
+}
+
+func TestVersions(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+
+	if status, err := fs.GetBucketVersioning(ctx, "b1"); err != nil || status != "" {
+		t.Fatalf("versioning of new bucket %q, error %v", status, err)
+	}
+	if err := fs.SetBucketVersioning(ctx, "b1", "On"); err == nil {
+		t.Fatal("unknown versioning status has been set")
+	}
+	if err := fs.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+		t.Fatal(err)
+	}
+
+	uploadText(t, fs, "b1", "contract.txt", "v1")
+	uploadText(t, fs, "b1", "contract.txt", "v2")
+
+	versions := listVersions(t, fs, "b1", "contract.txt")
+	if len(versions) != 2 || !versions[0].IsLatest || versions[1].IsLatest || versions[0].VersionID == versions[1].VersionID {
+		t.Fatalf("unexpected versions %+v, %+v", versions[0], versions[1])
+	}
+	firstID := versions[1].VersionID
+
+	fileData, err := fs.DownloadVersion(ctx, "b1", "contract.txt", firstID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(fileData)
+	_ = fileData.Close()
+	if string(data) != "v1" {
+		t.Fatalf("first version content %q", data)
+	}
+
+	// Removed file is hidden behind delete marker and kept into history.
+	if err = fs.RemoveFile(ctx, "b1", "contract.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.StatFile(ctx, "b1", "contract.txt"); err == nil {
+		t.Fatal("removed file is found")
+	}
+	versions = listVersions(t, fs, "b1", "contract.txt")
+	if len(versions) != 3 || !versions[0].IsDeleteMarker || !versions[0].IsLatest {
+		t.Fatalf("removed file has versions %+v", versions)
+	}
+	if _, err = fs.DownloadVersion(ctx, "b1", "contract.txt", versions[0].VersionID); err == nil {
+		t.Fatal("delete marker has been downloaded")
+	}
+
+	if err = fs.RestoreVersion(ctx, "b1", "contract.txt", firstID); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := readText(t, fs, "b1", "contract.txt"); err != nil || content != "v1" {
+		t.Fatalf("restored content %q, error %v", content, err)
+	}
+	if versions = listVersions(t, fs, "b1", "contract.txt"); len(versions) != 4 {
+		t.Fatalf("restore has not added version: %+v", versions)
+	}
+
+	// Removing current version brings back previous one.
+	if err = fs.RemoveVersion(ctx, "b1", "contract.txt", versions[0].VersionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.StatFile(ctx, "b1", "contract.txt"); err == nil {
+		t.Fatal("file is found while delete marker is latest version")
+	}
+	if err = fs.RemoveVersion(ctx, "b1", "contract.txt", "missing"); err == nil {
+		t.Fatal("missing version has been removed")
+	}
+}
+
+func TestSuspendedVersioning(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+
+	if err := fs.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+		t.Fatal(err)
+	}
+	uploadText(t, fs, "b1", "a.txt", "v1")
+	if err := fs.SetBucketVersioning(ctx, "b1", cloud.VersioningSuspended); err != nil {
+		t.Fatal(err)
+	}
+	uploadText(t, fs, "b1", "a.txt", "v2")
+	uploadText(t, fs, "b1", "a.txt", "v3")
+
+	// Null version is replaced, versions made before suspension are kept.
+	versions := listVersions(t, fs, "b1", "a.txt")
+	if len(versions) != 2 || versions[0].VersionID != cloud.NullVersionID || versions[1].VersionID == cloud.NullVersionID {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+	if content, err := readText(t, fs, "b1", "a.txt"); err != nil || content != "v3" {
+		t.Fatalf("content %q, error %v", content, err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFS(t)
+
+	invalid := []*cloud.LifecycleRule{{ID: "no-action", Prefix: "tmp/"}}
+	if err := fs.SetBucketLifecycle(ctx, "b1", invalid); err == nil {
+		t.Fatal("rule without action has been set")
+	}
+
+	rules := []*cloud.LifecycleRule{
+		{ID: "tmp", Prefix: "tmp/", ExpirationDays: 1, AbortIncompleteUploadDays: 2},
+		{ID: "docs", Prefix: "docs/", NoncurrentExpirationDays: 7, TransitionDays: 30, TransitionStorageClass: "GLACIER"},
+		{ID: "off", Status: cloud.LifecycleDisabled, Prefix: "docs/", ExpirationDays: 1},
+	}
+	if err := fs.SetBucketLifecycle(ctx, "b1", rules); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := fs.GetBucketLifecycle(ctx, "b1"); err != nil || len(stored) != 3 {
+		t.Fatalf("rules %+v, error %v", stored, err)
+	}
+
+	if err := fs.SetBucketVersioning(ctx, "b1", cloud.VersioningEnabled); err != nil {
+		t.Fatal(err)
+	}
+	uploadText(t, fs, "b1", "tmp/a.txt", "a")
+	uploadText(t, fs, "b1", "docs/b.txt", "b1")
+	uploadText(t, fs, "b1", "docs/b.txt", "b2")
+	uploadText(t, fs, "b1", "c.txt", "c")
+	uploadID, err := fs.CreateUpload(ctx, "b1", "tmp/big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err = fs.ApplyLifecycle(ctx, "b1", now.AddDate(0, 0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.StatFile(ctx, "b1", "tmp/a.txt"); err == nil {
+		t.Fatal("expired file still exists")
+	}
+	if _, err = fs.ListUploadParts(ctx, "b1", "tmp/big.bin", uploadID); err == nil {
+		t.Fatal("incomplete upload has not been aborted")
+	}
+	if versions, _ := fs.ListFileVersions(ctx, "b1", "docs/b.txt"); len(versions) != 2 {
+		t.Fatalf("noncurrent version is expired early: %+v", versions)
+	}
+
+	if err = fs.ApplyLifecycle(ctx, "b1", now.AddDate(0, 0, 31)); err != nil {
+		t.Fatal(err)
+	}
+	item, err := fs.StatFile(ctx, "b1", "docs/b.txt")
+	if err != nil || item.StorageClass != "GLACIER" {
+		t.Fatalf("file has not been transitioned: %+v, error %v", item, err)
+	}
+	if versions, _ := fs.ListFileVersions(ctx, "b1", "docs/b.txt"); len(versions) != 1 {
+		t.Fatalf("noncurrent version has not been expired: %+v", versions)
+	}
+	if item, err = fs.StatFile(ctx, "b1", "c.txt"); err != nil || item.StorageClass == "GLACIER" {
+		t.Fatalf("file out of rules has been changed: %+v, error %v", item, err)
+	}
+
+	if err = fs.SetBucketLifecycle(ctx, "b1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := fs.GetBucketLifecycle(ctx, "b1"); err != nil || len(stored) != 0 {
+		t.Fatalf("rules are not removed: %+v, error %v", stored, err)
+	}
+}
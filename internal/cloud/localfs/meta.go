@@ -0,0 +1,102 @@
+package localfs
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileMeta is sidecar record stored next to object under
+// the service metadata directory of storage root.
+type fileMeta struct {
+	Expires      *time.Time        `json:"expires,omitempty"`
+	StorageClass string            `json:"storage_class,omitempty"`
+	UserMetadata map[string]string `json:"user_metadata,omitempty"`
+}
+
+func (fm *fileMeta) isEmpty() bool {
+	return fm.Expires == nil && fm.StorageClass == "" && len(fm.UserMetadata) == 0
+}
+
+func (fs *LocalFS) metaPath(bucket, filePath string) (string, error) {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	relPath, err := filepath.Rel(fs.root, objPath)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(fs.root, metaDirName, relPath+".json"), nil
+}
+
+func (fs *LocalFS) readMeta(bucket, filePath string) (*fileMeta, error) {
+	metaPath, err := fs.metaPath(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &fileMeta{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &fileMeta{}
+	if err = json.Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+func (fs *LocalFS) writeMeta(bucket, filePath string, meta *fileMeta) error {
+	metaPath, err := fs.metaPath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(metaPath), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(metaPath, data, 0o644)
+}
+
+func (fs *LocalFS) copyMeta(srcBucket, srcPath, dstBucket, dstPath string) error {
+	meta, err := fs.readMeta(srcBucket, srcPath)
+	if err != nil {
+		return err
+	}
+
+	if meta.isEmpty() {
+		return fs.removeMeta(dstBucket, dstPath)
+	}
+
+	return fs.writeMeta(dstBucket, dstPath, meta)
+}
+
+func (fs *LocalFS) removeMeta(bucket, filePath string) error {
+	metaPath, err := fs.metaPath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
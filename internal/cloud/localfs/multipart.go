@@ -0,0 +1,283 @@
+package localfs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+const (
+	uploadsDirName  = ".uploads"
+	sessionFileName = "session.json"
+	partFilePrefix  = "part-"
+)
+
+// uploadSession is stored into upload directory to check that
+// parts are uploaded into the same object upload was created for.
+type uploadSession struct {
+	Bucket    string    `json:"bucket"`
+	FilePath  string    `json:"file_path"`
+	Initiated time.Time `json:"initiated"`
+}
+
+func (fs *LocalFS) CreateUpload(_ context.Context, bucket, filePath string) (string, error) {
+	if _, err := fs.filePath(bucket, filePath); err != nil {
+		return "", err
+	}
+
+	exist, err := fs.IsBucketExist(context.Background(), bucket)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", err
+	}
+
+	uploadID := hex.EncodeToString(idBytes)
+	uploadDir := fs.uploadDir(uploadID)
+	if err = os.MkdirAll(uploadDir, 0o755); err != nil {
+		return "", err
+	}
+
+	session := &uploadSession{
+		Bucket:    bucket,
+		FilePath:  filePath,
+		Initiated: time.Now().UTC(),
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	sessionPath := filepath.Join(uploadDir, sessionFileName)
+	if err = os.WriteFile(sessionPath, data, 0o644); err != nil {
+		return "", err
+	}
+
+	return uploadID, nil
+}
+
+func (fs *LocalFS) UploadPart(
+	_ context.Context,
+	bucket, filePath, uploadID string,
+	partNumber int,
+	data io.Reader,
+	_ int64,
+) (*cloud.UploadPart, error) {
+	uploadDir, err := fs.openUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partNumber < 1 {
+		return nil, fmt.Errorf("invalid part number: %d", partNumber)
+	}
+
+	tmpFile, err := os.CreateTemp(uploadDir, uploadPattern)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	if _, err = io.Copy(tmpFile, data); err != nil {
+		_ = tmpFile.Close()
+		return nil, err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return nil, err
+	}
+
+	partPath := filepath.Join(uploadDir, partFilePrefix+strconv.Itoa(partNumber))
+	if err = os.Rename(tmpFile.Name(), partPath); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return uploadPart(partNumber, info), nil
+}
+
+func (fs *LocalFS) ListUploadParts(_ context.Context, bucket, filePath, uploadID string) ([]*cloud.UploadPart, error) {
+	uploadDir, err := fs.openUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.listParts(uploadDir)
+}
+
+func (fs *LocalFS) CompleteUpload(_ context.Context, bucket, filePath, uploadID string) error {
+	uploadDir, err := fs.openUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return err
+	}
+
+	parts, err := fs.listParts(uploadDir)
+	if err != nil {
+		return err
+	}
+
+	if len(parts) == 0 {
+		return fmt.Errorf("upload %s has no parts", uploadID)
+	}
+
+	readers := make([]io.Reader, 0, len(parts))
+	for _, part := range parts {
+		partPath := filepath.Join(uploadDir, partFilePrefix+strconv.Itoa(part.PartNumber))
+		partFile, err := os.Open(partPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := partFile.Close(); err != nil {
+				log.Println("failed to close upload part: ", partPath, err)
+			}
+		}()
+		readers = append(readers, partFile)
+	}
+
+	if err = fs.writeFile(bucket, filePath, io.MultiReader(readers...), false); err != nil {
+		return err
+	}
+
+	if err = fs.removeMeta(bucket, filePath); err != nil {
+		log.Println("failed to remove file metadata: ", filePath, err)
+	}
+
+	return os.RemoveAll(uploadDir)
+}
+
+func (fs *LocalFS) AbortUpload(_ context.Context, bucket, filePath, uploadID string) error {
+	uploadDir, err := fs.openUpload(bucket, filePath, uploadID)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(uploadDir)
+}
+
+func (fs *LocalFS) uploadDir(uploadID string) string {
+	return filepath.Join(fs.root, uploadsDirName, uploadID)
+}
+
+func (fs *LocalFS) openUpload(bucket, filePath, uploadID string) (string, error) {
+	if _, err := hex.DecodeString(uploadID); err != nil || uploadID == "" {
+		return "", fmt.Errorf("invalid upload id: %s", uploadID)
+	}
+
+	uploadDir := fs.uploadDir(uploadID)
+	session, err := readSession(uploadDir)
+	if err != nil {
+		return "", fmt.Errorf("upload %s does not exist", uploadID)
+	}
+
+	if session.Bucket != bucket || session.FilePath != filePath {
+		return "", fmt.Errorf("upload %s does not exist", uploadID)
+	}
+
+	return uploadDir, nil
+}
+
+// abortUploads removes all incomplete uploads into bucket.
+func (fs *LocalFS) abortUploads(bucket string) error {
+	entries, err := os.ReadDir(filepath.Join(fs.root, uploadsDirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		uploadDir := fs.uploadDir(entry.Name())
+		session, err := readSession(uploadDir)
+		if err != nil || session.Bucket != bucket {
+			continue
+		}
+
+		if err = os.RemoveAll(uploadDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readSession(uploadDir string) (*uploadSession, error) {
+	data, err := os.ReadFile(filepath.Join(uploadDir, sessionFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	session := &uploadSession{}
+	if err = json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (fs *LocalFS) listParts(uploadDir string) ([]*cloud.UploadPart, error) {
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*cloud.UploadPart, 0, len(entries))
+	for _, entry := range entries {
+		numValue, found := strings.CutPrefix(entry.Name(), partFilePrefix)
+		if !found {
+			continue
+		}
+
+		partNumber, err := strconv.Atoi(numValue)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, uploadPart(partNumber, info))
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	return parts, nil
+}
+
+func uploadPart(partNumber int, info os.FileInfo) *cloud.UploadPart {
+	return &cloud.UploadPart{
+		PartNumber:   partNumber,
+		Size:         info.Size(),
+		ETag:         fileETag(info),
+		LastModified: info.ModTime().UTC(),
+	}
+}
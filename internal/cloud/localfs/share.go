@@ -0,0 +1,26 @@
+package localfs
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// GetShareURL returns link to docs-hub share route signed by share
+// secret, because local files have no cloud presigning service.
+func (fs *LocalFS) GetShareURL(_ context.Context, bucket, filePath string, expired time.Duration) (string, error) {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = os.Stat(objPath); err != nil {
+		return "", err
+	}
+
+	return fs.signer.SignURL(bucket, filePath, expired), nil
+}
+
+func (fs *LocalFS) VerifyShareURL(_ context.Context, bucket, filePath string, expires int64, signature string) error {
+	return fs.signer.VerifyURL(bucket, filePath, expires, signature)
+}
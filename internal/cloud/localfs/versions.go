@@ -0,0 +1,416 @@
+package localfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+const (
+	bucketsDirName  = ".buckets"
+	versionsDirName = ".versions"
+	versionsIndex   = "index.json"
+	versionsKey     = "key"
+)
+
+// bucketConfig keeps bucket settings which S3 stores with bucket.
+type bucketConfig struct {
+	Versioning string                 `json:"versioning,omitempty"`
+	Lifecycle  []*cloud.LifecycleRule `json:"lifecycle,omitempty"`
+}
+
+// versionEntry describes one version of object. Entries are stored
+// newest first, the first one is current object unless it is delete
+// marker, content of noncurrent versions is kept next to index.
+type versionEntry struct {
+	VersionID    string    `json:"version_id"`
+	DeleteMarker bool      `json:"delete_marker,omitempty"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	ETag         string    `json:"etag,omitempty"`
+}
+
+func newVersionEntry(versionID string, info os.FileInfo) *versionEntry {
+	return &versionEntry{
+		VersionID:    versionID,
+		Size:         info.Size(),
+		LastModified: info.ModTime().UTC(),
+		ETag:         fileETag(info),
+	}
+}
+
+func (fs *LocalFS) SetBucketVersioning(ctx context.Context, bucket, status string) error {
+	if err := cloud.CheckVersioningStatus(status); err != nil {
+		return err
+	}
+
+	exist, err := fs.IsBucketExist(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	config, err := fs.readBucketConfig(bucket)
+	if err != nil {
+		return err
+	}
+
+	config.Versioning = status
+	return fs.writeBucketConfig(bucket, config)
+}
+
+func (fs *LocalFS) GetBucketVersioning(ctx context.Context, bucket string) (string, error) {
+	exist, err := fs.IsBucketExist(ctx, bucket)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", fmt.Errorf("bucket %s does not exist", bucket)
+	}
+
+	config, err := fs.readBucketConfig(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	return config.Versioning, nil
+}
+
+func (fs *LocalFS) ListFileVersions(_ context.Context, bucket, filePath string) ([]*cloud.FileVersion, error) {
+	entries, err := fs.currentVersions(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("object %s does not exist", filePath)
+	}
+
+	versions := make([]*cloud.FileVersion, len(entries))
+	for index, entry := range entries {
+		versions[index] = &cloud.FileVersion{
+			VersionID:      entry.VersionID,
+			IsLatest:       index == 0,
+			IsDeleteMarker: entry.DeleteMarker,
+			Size:           entry.Size,
+			LastModified:   entry.LastModified,
+			ETag:           entry.ETag,
+		}
+	}
+
+	return versions, nil
+}
+
+func (fs *LocalFS) DownloadVersion(_ context.Context, bucket, filePath, versionID string) (io.ReadCloser, error) {
+	dataPath, err := fs.versionDataPath(bucket, filePath, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(dataPath)
+}
+
+func (fs *LocalFS) RestoreVersion(_ context.Context, bucket, filePath, versionID string) error {
+	dataPath, err := fs.versionDataPath(bucket, filePath, versionID)
+	if err != nil {
+		return err
+	}
+
+	dataFile, err := os.Open(dataPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dataFile.Close(); err != nil {
+			log.Println("failed to close file handler: ", dataPath, err)
+		}
+	}()
+
+	if err = fs.writeFile(bucket, filePath, dataFile, false); err != nil {
+		return err
+	}
+
+	return fs.removeMeta(bucket, filePath)
+}
+
+func (fs *LocalFS) RemoveVersion(_ context.Context, bucket, filePath, versionID string) error {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := fs.currentVersions(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	index := findVersion(entries, versionID)
+	if index < 0 {
+		return fmt.Errorf("version %s of object %s does not exist", versionID, filePath)
+	}
+
+	versionsDir := fs.versionsDir(bucket, filePath)
+	entry := entries[index]
+	entries = append(entries[:index:index], entries[index+1:]...)
+
+	switch {
+	case index > 0:
+		err = os.Remove(filepath.Join(versionsDir, entry.VersionID))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return fs.writeVersions(bucket, filePath, entries)
+	case !entry.DeleteMarker:
+		if err = os.Remove(objPath); err != nil {
+			return err
+		}
+		if err = fs.removeMeta(bucket, filePath); err != nil {
+			log.Println("failed to remove file metadata: ", filePath, err)
+		}
+	}
+
+	// Newest remaining version becomes current unless it is delete marker.
+	if len(entries) > 0 && !entries[0].DeleteMarker {
+		if err = os.MkdirAll(filepath.Dir(objPath), 0o755); err != nil {
+			return err
+		}
+		if err = os.Rename(filepath.Join(versionsDir, entries[0].VersionID), objPath); err != nil {
+			return err
+		}
+	} else {
+		fs.pruneDirs(bucket, filepath.Dir(objPath))
+	}
+
+	return fs.writeVersions(bucket, filePath, entries)
+}
+
+// versionedWrite applies change of current object. When bucket versioning
+// is on, content of current object is kept as noncurrent version before
+// it is replaced or removed, and new version is recorded after change.
+func (fs *LocalFS) versionedWrite(bucket, filePath string, isDelete bool, apply func() error) error {
+	config, err := fs.readBucketConfig(bucket)
+	if err != nil {
+		return err
+	}
+
+	if config.Versioning == "" {
+		return apply()
+	}
+
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := fs.currentVersions(bucket, filePath)
+	if err != nil {
+		return err
+	}
+
+	versionID := cloud.NullVersionID
+	if config.Versioning == cloud.VersioningEnabled {
+		if versionID, err = cloud.NewVersionID(); err != nil {
+			return err
+		}
+	}
+
+	// Null version is replaced instead of being kept, like S3
+	// does it while versioning is suspended.
+	versionsDir := fs.versionsDir(bucket, filePath)
+	var keptPath string
+	if len(entries) > 0 && !entries[0].DeleteMarker && entries[0].VersionID != versionID {
+		keptPath = filepath.Join(versionsDir, entries[0].VersionID)
+		if err = os.MkdirAll(versionsDir, 0o755); err != nil {
+			return err
+		}
+		if err = os.Link(objPath, keptPath); err != nil {
+			return err
+		}
+	}
+
+	if err = apply(); err != nil {
+		if keptPath != "" {
+			_ = os.Remove(keptPath)
+		}
+		return err
+	}
+
+	if index := findVersion(entries, versionID); index >= 0 {
+		if index > 0 {
+			_ = os.Remove(filepath.Join(versionsDir, versionID))
+		}
+		entries = append(entries[:index:index], entries[index+1:]...)
+	}
+
+	entry := &versionEntry{
+		VersionID:    versionID,
+		DeleteMarker: true,
+		LastModified: time.Now().UTC(),
+	}
+	if !isDelete {
+		info, err := os.Stat(objPath)
+		if err != nil {
+			return err
+		}
+		entry = newVersionEntry(versionID, info)
+	}
+
+	return fs.writeVersions(bucket, filePath, append([]*versionEntry{entry}, entries...))
+}
+
+// currentVersions returns versions index of object, object which has
+// been written before versioning was enabled is reported as null version.
+func (fs *LocalFS) currentVersions(bucket, filePath string) ([]*versionEntry, error) {
+	objPath, err := fs.filePath(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.readVersions(bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) > 0 && !entries[0].DeleteMarker {
+		return entries, nil
+	}
+
+	info, err := os.Stat(objPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]*versionEntry{newVersionEntry(cloud.NullVersionID, info)}, entries...), nil
+}
+
+// versionDataPath returns path of file with content of object version.
+func (fs *LocalFS) versionDataPath(bucket, filePath, versionID string) (string, error) {
+	entries, err := fs.currentVersions(bucket, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	index := findVersion(entries, versionID)
+	if index < 0 {
+		return "", fmt.Errorf("version %s of object %s does not exist", versionID, filePath)
+	}
+
+	if entries[index].DeleteMarker {
+		return "", fmt.Errorf("version %s of object %s is delete marker", versionID, filePath)
+	}
+
+	if index == 0 {
+		return fs.filePath(bucket, filePath)
+	}
+
+	return filepath.Join(fs.versionsDir(bucket, filePath), versionID), nil
+}
+
+func findVersion(entries []*versionEntry, versionID string) int {
+	for index, entry := range entries {
+		if entry.VersionID == versionID {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// versionsDir returns directory of object versions. Object keys are
+// hashed, so nested keys never collide with versions of their parents.
+func (fs *LocalFS) versionsDir(bucket, filePath string) string {
+	keyHash := sha256.Sum256([]byte(filePath))
+	return filepath.Join(fs.root, versionsDirName, bucket, hex.EncodeToString(keyHash[:]))
+}
+
+func (fs *LocalFS) readVersions(bucket, filePath string) ([]*versionEntry, error) {
+	data, err := os.ReadFile(filepath.Join(fs.versionsDir(bucket, filePath), versionsIndex))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*versionEntry, 0)
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (fs *LocalFS) writeVersions(bucket, filePath string, entries []*versionEntry) error {
+	versionsDir := fs.versionsDir(bucket, filePath)
+	if len(entries) == 0 {
+		return os.RemoveAll(versionsDir)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(versionsDir, 0o755); err != nil {
+		return err
+	}
+
+	// Object key is kept next to index, because directory name is its
+	// hash and lifecycle has to find objects by versions directory.
+	err = os.WriteFile(filepath.Join(versionsDir, versionsKey), []byte(filePath), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(versionsDir, versionsIndex), data, 0o644)
+}
+
+func (fs *LocalFS) bucketConfigPath(bucket string) string {
+	return filepath.Join(fs.root, bucketsDirName, bucket+".json")
+}
+
+func (fs *LocalFS) readBucketConfig(bucket string) (*bucketConfig, error) {
+	data, err := os.ReadFile(fs.bucketConfigPath(bucket))
+	if errors.Is(err, os.ErrNotExist) {
+		return &bucketConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config := &bucketConfig{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (fs *LocalFS) writeBucketConfig(bucket string, config *bucketConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	configPath := fs.bucketConfigPath(bucket)
+	if err = os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, data, 0o644)
+}
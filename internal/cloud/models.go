@@ -1,7 +1,50 @@
 package cloud
 
+import (
+	"mime"
+	"path"
+	"time"
+)
+
+const (
+	defaultContentType  = "application/octet-stream"
+	DefaultStorageClass = "STANDARD"
+)
+
 type StorageItem struct {
-	FileName      string `json:"file_name"`
-	DirectoryName string `json:"directory_name"`
-	IsDirectory   bool   `json:"is_directory"`
+	FileName      string    `json:"file_name"`
+	DirectoryName string    `json:"directory_name"`
+	IsDirectory   bool      `json:"is_directory"`
+	Size          int64     `json:"size"`
+	LastModified  time.Time `json:"last_modified"`
+	ETag          string    `json:"etag"`
+	ContentType   string    `json:"content_type"`
+
+	StorageClass string            `json:"storage_class"`
+	UserMetadata map[string]string `json:"user_metadata,omitempty"`
+	ExpiresAt    *time.Time        `json:"expires_at,omitempty"`
+}
+
+type UploadPart struct {
+	PartNumber   int       `json:"part_number"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// ContentTypeOf guesses MIME type of file by its extension.
+func ContentTypeOf(filePath string) string {
+	contentType := mime.TypeByExtension(path.Ext(filePath))
+	if contentType == "" {
+		return defaultContentType
+	}
+
+	return contentType
+}
+
+// DirectoryOf returns parent directory prefix of object key
+// in the same form as it is used by files listing.
+func DirectoryOf(filePath string) string {
+	dirPath, _ := path.Split(filePath)
+	return dirPath
 }
@@ -0,0 +1,66 @@
+package cloud
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// UploadPolicy constrains upload which is signed for client to
+// send file directly to cloud. PUT uploads single file path and
+// can constrain its content type only, POST form uploads any file
+// into folder and can constrain its size too.
+type UploadPolicy struct {
+	Method      string
+	FilePath    string
+	Folder      string
+	ContentType string
+	MaxSize     int64
+	Expired     time.Duration
+}
+
+// PresignedUpload is request client sends file by, POST form must
+// contain form data fields before the file field.
+type PresignedUpload struct {
+	Method    string            `json:"method" example:"POST" enums:"PUT,POST"`
+	URL       string            `json:"url" example:"http://localhost:9000/contracts"`
+	FormData  map[string]string `json:"form_data,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at" example:"2025-01-01T13:01:01Z"`
+}
+
+func (p *UploadPolicy) Check() error {
+	switch p.Method {
+	case http.MethodPut:
+		if p.FilePath == "" {
+			return errors.New("file path is required for PUT upload")
+		}
+		if p.MaxSize > 0 {
+			return errors.New("size of PUT upload can not be constrained, use POST upload")
+		}
+	case http.MethodPost:
+		if p.FilePath == "" && p.Folder == "" {
+			return errors.New("file path or folder is required for POST upload")
+		}
+		if p.FilePath != "" && p.Folder != "" {
+			return errors.New("either file path or folder is allowed for POST upload")
+		}
+	default:
+		return errors.New("upload method must be PUT or POST")
+	}
+
+	if p.Expired <= 0 {
+		return errors.New("upload expiry must be positive")
+	}
+
+	if p.MaxSize < 0 {
+		return errors.New("max size must not be negative")
+	}
+
+	if strings.HasSuffix(p.FilePath, "/") {
+		return errors.New("file path must not be folder")
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cloud
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckUploadPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  UploadPolicy
+		isValid bool
+	}{
+		{name: "PUT of file", policy: UploadPolicy{Method: http.MethodPut, FilePath: "in/a.pdf", ContentType: "application/pdf"}, isValid: true},
+		{name: "POST of file", policy: UploadPolicy{Method: http.MethodPost, FilePath: "in/a.pdf", MaxSize: 10}, isValid: true},
+		{name: "POST into folder", policy: UploadPolicy{Method: http.MethodPost, Folder: "in/"}, isValid: true},
+		{name: "PUT without file", policy: UploadPolicy{Method: http.MethodPut, Folder: "in/"}},
+		{name: "PUT with max size", policy: UploadPolicy{Method: http.MethodPut, FilePath: "in/a.pdf", MaxSize: 10}},
+		{name: "POST without path", policy: UploadPolicy{Method: http.MethodPost}},
+		{name: "POST with file and folder", policy: UploadPolicy{Method: http.MethodPost, FilePath: "in/a.pdf", Folder: "in/"}},
+		{name: "unknown method", policy: UploadPolicy{Method: http.MethodGet, FilePath: "in/a.pdf"}},
+		{name: "negative max size", policy: UploadPolicy{Method: http.MethodPost, Folder: "in/", MaxSize: -1}},
+		{name: "folder as file", policy: UploadPolicy{Method: http.MethodPut, FilePath: "in/"}},
+		{name: "no expiry", policy: UploadPolicy{Method: http.MethodPut, FilePath: "in/a.pdf", Expired: -1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.policy.Expired == 0 {
+				test.policy.Expired = time.Hour
+			}
+			if err := test.policy.Check(); (err == nil) != test.isValid {
+				t.Fatalf("error %v, expected valid %v", err, test.isValid)
+			}
+		})
+	}
+}
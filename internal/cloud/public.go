@@ -1,8 +1,9 @@
 package cloud
 
 import (
-	"bytes"
 	"context"
+	"io"
+	"net/http"
 	"time"
 )
 
@@ -13,30 +14,101 @@ type DocumentHub struct {
 type ICloud interface {
 	IBucket
 	IDocument
+	IFolder
+	IVersion
+	IMultipart
 	IShare
 	IExpired
+	ILifecycle
 }
 
 type IBucket interface {
 	GetBuckets(ctx context.Context) ([]string, error)
 	CreateBucket(ctx context.Context, bucket string) error
 	RemoveBucket(ctx context.Context, bucket string) error
+	PurgeBucket(ctx context.Context, bucket string) error
 	IsBucketExist(ctx context.Context, bucket string) (bool, error)
 }
 
 type IDocument interface {
-	GetFiles(ctx context.Context, bucket, filePath string) ([]*StorageItem, error)
-	CopyFile(ctx context.Context, bucket, srcPath, dstPath string) error
-	MoveFile(ctx context.Context, bucket, srcPath, dstPath string) error
+	GetFiles(ctx context.Context, bucket string, params *ListFilesParams) (*FilesPage, error)
+
+	// CopyFile and MoveFile copy file server side between buckets of the
+	// same cloud, files are never streamed between different backends.
+	CopyFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error
+	MoveFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error
 	RemoveFile(ctx context.Context, bucket, filePath string) error
-	UploadFile(ctx context.Context, bucket, filePath string, data bytes.Buffer) error
-	DownloadFile(ctx context.Context, bucket, filePath string) (bytes.Buffer, error)
+	UploadFile(ctx context.Context, bucket, filePath string, data io.Reader, size int64) error
+	DownloadFile(ctx context.Context, bucket, filePath string) (io.ReadCloser, error)
+	DownloadRange(ctx context.Context, bucket, filePath string, offset, length int64) (io.ReadCloser, error)
+	StatFile(ctx context.Context, bucket, filePath string) (*StorageItem, error)
+
+	// UploadNewFile, CopyNewFile and MoveNewFile store file only if its path
+	// is free when it is written, so that file stored concurrently after
+	// conflict check is never replaced. ErrFileExists is returned otherwise.
+	UploadNewFile(ctx context.Context, bucket, filePath string, data io.Reader, size int64) error
+	CopyNewFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error
+	MoveNewFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error
+}
+
+type IFolder interface {
+	CreateFolder(ctx context.Context, bucket, folderPath string) error
+	RemoveFiles(ctx context.Context, bucket string, filePaths []string) map[string]error
+}
+
+type IVersion interface {
+	SetBucketVersioning(ctx context.Context, bucket, status string) error
+	GetBucketVersioning(ctx context.Context, bucket string) (string, error)
+	ListFileVersions(ctx context.Context, bucket, filePath string) ([]*FileVersion, error)
+	DownloadVersion(ctx context.Context, bucket, filePath, versionID string) (io.ReadCloser, error)
+	RestoreVersion(ctx context.Context, bucket, filePath, versionID string) error
+	RemoveVersion(ctx context.Context, bucket, filePath, versionID string) error
+}
+
+type IMultipart interface {
+	CreateUpload(ctx context.Context, bucket, filePath string) (string, error)
+	UploadPart(ctx context.Context, bucket, filePath, uploadID string, partNumber int, data io.Reader, size int64) (*UploadPart, error)
+	ListUploadParts(ctx context.Context, bucket, filePath, uploadID string) ([]*UploadPart, error)
+	CompleteUpload(ctx context.Context, bucket, filePath, uploadID string) error
+	AbortUpload(ctx context.Context, bucket, filePath, uploadID string) error
 }
 
 type IShare interface {
 	GetShareURL(ctx context.Context, bucket, filePath string, expired time.Duration) (string, error)
 }
 
+type IShareVerifier interface {
+	VerifyShareURL(ctx context.Context, bucket, filePath string, expires int64, signature string) error
+}
+
+// PresignProxyPath is route prefix docs-hub serves presigned URLs by
+// when cloud itself is unreachable by users.
+const PresignProxyPath = "/presigned"
+
+// IPresignProxy is implemented by clouds which forward requests of
+// presigned URLs, handler is nil if proxy is disabled by config.
+type IPresignProxy interface {
+	PresignProxy() http.Handler
+}
+
+// IUploadPresigner is implemented by clouds which are able to sign
+// uploads, so that clients send files without docs-hub in between.
+type IUploadPresigner interface {
+	PresignUpload(ctx context.Context, bucket string, policy *UploadPolicy) (*PresignedUpload, error)
+}
+
 type IExpired interface {
-	UploadExpired(ctx context.Context, bucket, filePath string, expired time.Time, data bytes.Buffer) error
+	UploadExpired(ctx context.Context, bucket, filePath string, expired time.Time, data io.Reader, size int64) error
+	// SetFileExpiry extends expiry of existing file, nil expiry clears it.
+	SetFileExpiry(ctx context.Context, bucket, filePath string, expiresAt *time.Time) error
+	// RemoveExpired removes files of bucket expired by now and returns their count.
+	RemoveExpired(ctx context.Context, bucket string, now time.Time) (int, error)
+}
+
+type ILifecycle interface {
+	SetBucketLifecycle(ctx context.Context, bucket string, rules []*LifecycleRule) error
+	GetBucketLifecycle(ctx context.Context, bucket string) ([]*LifecycleRule, error)
+	// ApplyLifecycle applies lifecycle rules of bucket for backends which
+	// emulate them, backends with native lifecycle do nothing here.
+	ApplyLifecycle(ctx context.Context, bucket string, now time.Time) error
 }
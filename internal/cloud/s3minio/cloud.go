@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log"
+	"strings"
 	"time"
 
 	"docs-hub/internal/cloud"
@@ -12,14 +15,30 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
+// uploadPartSize limits memory buffered by client for each upload
+// which size is unknown, e.g. streamed directly from multipart form.
+const uploadPartSize = 16 << 20
+
+// maxCopySize is the largest object which S3 copies by single request.
+const maxCopySize = 5 << 30
+
+// expiresAtMeta is user metadata key which keeps expiry time of object
+// removed by sweeper, because S3 has no per-object expiration.
+const expiresAtMeta = "Expires-At"
+
 type S3Minio struct {
 	config *cloud.CloudConfig
 	mc     *minio.Client
+	core   *minio.Core
+	// signer presigns URLs, it is the same client as mc
+	// unless public endpoint of cloud is configured.
+	signer *minio.Client
 }
 
 func New(config *cloud.CloudConfig) *cloud.DocumentHub {
+	creds := credentials.NewStaticV4(config.Username, config.Password, "")
 	minioOpts := &minio.Options{
-		Creds:  credentials.NewStaticV4(config.Username, config.Password, ""),
+		Creds:  creds,
 		Secure: config.EnableSSL,
 	}
 
@@ -28,9 +47,21 @@ func New(config *cloud.CloudConfig) *cloud.DocumentHub {
 		log.Fatalln("failed to connect to minio cloud: ", err.Error())
 	}
 
+	signer := client
+	if config.PublicEndpoint != "" {
+		signer, err = newSigner(config, creds)
+		if err != nil {
+			log.Fatalln("failed to init signer of public endpoint: ", err.Error())
+		}
+	} else if config.ProxyPresigned {
+		log.Fatalln("public endpoint is required to proxy presigned urls")
+	}
+
 	s3Minio := &S3Minio{
 		config: config,
 		mc:     client,
+		core:   &minio.Core{Client: client},
+		signer: signer,
 	}
 
 	return &cloud.DocumentHub{Cloud: s3Minio}
@@ -56,39 +87,111 @@ func (mw *S3Minio) CreateBucket(ctx context.Context, bucket string) error {
 }
 
 func (mw *S3Minio) RemoveBucket(ctx context.Context, bucket string) error {
-	return mw.mc.RemoveBucket(ctx, bucket)
+	err := mw.mc.RemoveBucket(ctx, bucket)
+	if minio.ToErrorResponse(err).Code == "BucketNotEmpty" {
+		return fmt.Errorf("%w: %s", cloud.ErrBucketNotEmpty, bucket)
+	}
+
+	return err
+}
+
+// PurgeBucket removes all objects of bucket including all their versions
+// and delete markers by batches, and aborts incomplete multipart uploads.
+func (mw *S3Minio) PurgeBucket(ctx context.Context, bucket string) error {
+	for upload := range mw.mc.ListIncompleteUploads(ctx, bucket, "", true) {
+		if upload.Err != nil {
+			return upload.Err
+		}
+
+		if err := mw.core.AbortMultipartUpload(ctx, bucket, upload.Key, upload.UploadID); err != nil {
+			return err
+		}
+	}
+
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var listErr error
+	objects := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objects)
+		opts := minio.ListObjectsOptions{Recursive: true, WithVersions: true}
+		for obj := range mw.mc.ListObjects(listCtx, bucket, opts) {
+			if obj.Err != nil {
+				listErr = obj.Err
+				return
+			}
+
+			select {
+			case objects <- obj:
+			case <-listCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var removeErr error
+	failedCount := 0
+	for objErr := range mw.mc.RemoveObjects(ctx, bucket, objects, minio.RemoveObjectsOptions{}) {
+		if removeErr == nil {
+			removeErr = objErr.Err
+		}
+		failedCount++
+	}
+
+	if removeErr != nil {
+		return fmt.Errorf("failed to remove %d objects: %w", failedCount, removeErr)
+	}
+
+	return listErr
 }
 
 func (mw *S3Minio) IsBucketExist(ctx context.Context, bucket string) (bool, error) {
 	return mw.mc.BucketExists(ctx, bucket)
 }
 
-func (mw *S3Minio) GetFiles(ctx context.Context, bucket, filePath string) ([]*cloud.StorageItem, error) {
+func (mw *S3Minio) GetFiles(ctx context.Context, bucket string, params *cloud.ListFilesParams) (*cloud.FilesPage, error) {
+	pager, err := cloud.NewFilesPager(params)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := minio.ListObjectsOptions{
-		UseV1:     true,
-		Prefix:    filePath,
-		Recursive: false,
+		Prefix:       params.Prefix,
+		Recursive:    params.Recursive,
+		StartAfter:   pager.StartAfter(),
+		WithMetadata: true,
 	}
 
 	if mw.mc.IsOffline() {
 		return nil, errors.New("cloud is offline")
 	}
 
-	dirObjects := make([]*cloud.StorageItem, 0)
-	for obj := range mw.mc.ListObjects(ctx, bucket, opts) {
+	// Cancel listing to stop background request when page is filled.
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range mw.mc.ListObjects(listCtx, bucket, opts) {
 		if obj.Err != nil {
-			log.Println("failed to get object: ", obj.Err)
+			return nil, obj.Err
+		}
+
+		// Placeholder of listed folder is not its own child.
+		if !params.Recursive && obj.Key == params.Prefix {
 			continue
 		}
 
-		dirObjects = append(dirObjects, &cloud.StorageItem{
-			FileName:      obj.Key,
-			DirectoryName: filePath,
-			IsDirectory:   len(obj.ETag) == 0,
-		})
+		dirName := params.Prefix
+		if params.Recursive {
+			dirName = cloud.DirectoryOf(obj.Key)
+		}
+
+		if !pager.Add(storageItem(&obj, dirName)) {
+			break
+		}
 	}
 
-	return dirObjects, nil
+	return pager.Page()
 }
 
 func (mw *S3Minio) RemoveFile(ctx context.Context, bucket, filePath string) error {
@@ -96,66 +199,354 @@ func (mw *S3Minio) RemoveFile(ctx context.Context, bucket, filePath string) erro
 	return mw.mc.RemoveObject(ctx, bucket, filePath, opts)
 }
 
-func (mw *S3Minio) UploadFile(ctx context.Context, bucket, filePath string, data bytes.Buffer) error {
-	opts := minio.PutObjectOptions{}
-	dataLen := int64(data.Len())
-	_, err := mw.mc.PutObject(ctx, bucket, filePath, &data, dataLen, opts)
+func (mw *S3Minio) UploadFile(ctx context.Context, bucket, filePath string, data io.Reader, size int64) error {
+	opts := minio.PutObjectOptions{
+		ContentType: cloud.ContentTypeOf(filePath),
+	}
+	if size < 0 {
+		opts.PartSize = uploadPartSize
+	}
+
+	_, err := mw.mc.PutObject(ctx, bucket, filePath, data, size, opts)
 	return err
 }
 
-func (mw *S3Minio) CopyFile(ctx context.Context, bucket, srcPath, dstPath string) error {
-	srcOpts := minio.CopySrcOptions{Bucket: bucket, Object: srcPath}
-	dstOpts := minio.CopyDestOptions{Bucket: bucket, Object: dstPath}
-	_, err := mw.mc.CopyObject(ctx, dstOpts, srcOpts)
+// UploadNewFile puts object with If-None-Match condition, so that S3 refuses
+// to replace object which has been stored by concurrent writer meanwhile.
+// Condition is checked on completion of multipart upload as well.
+func (mw *S3Minio) UploadNewFile(ctx context.Context, bucket, filePath string, data io.Reader, size int64) error {
+	opts := minio.PutObjectOptions{
+		ContentType: cloud.ContentTypeOf(filePath),
+	}
+	if size < 0 {
+		opts.PartSize = uploadPartSize
+	}
+	opts.SetMatchETagExcept("")
+
+	_, err := mw.mc.PutObject(ctx, bucket, filePath, data, size, opts)
+	return newObjectError(err, filePath)
+}
+
+func (mw *S3Minio) CopyFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	srcOpts := minio.CopySrcOptions{Bucket: srcBucket, Object: srcPath}
+	dstOpts := minio.CopyDestOptions{Bucket: dstBucket, Object: dstPath}
+	return mw.copyObject(ctx, dstOpts, srcOpts)
+}
+
+// copyObject copies object server side. S3 copies at most 5 GiB by single
+// request, so larger objects are copied by parts which do not keep content
+// type of source, metadata of source is passed explicitly then.
+func (mw *S3Minio) copyObject(ctx context.Context, dstOpts minio.CopyDestOptions, srcOpts minio.CopySrcOptions) error {
+	if !dstOpts.ReplaceMetadata {
+		statOpts := minio.StatObjectOptions{VersionID: srcOpts.VersionID}
+		objInfo, err := mw.mc.StatObject(ctx, srcOpts.Bucket, srcOpts.Object, statOpts)
+		if err != nil {
+			return err
+		}
+
+		if objInfo.Size > maxCopySize {
+			dstOpts.UserMetadata = objectMetadata(&objInfo)
+			dstOpts.ReplaceMetadata = true
+		}
+	}
+
+	_, err := mw.mc.ComposeObject(ctx, dstOpts, srcOpts)
+	return err
+}
+
+// objectMetadata returns content type and user metadata of object
+// to be set on its copy.
+func objectMetadata(objInfo *minio.ObjectInfo) map[string]string {
+	metadata := map[string]string{"Content-Type": objInfo.ContentType}
+	for metaKey, metaValue := range objInfo.UserMetadata {
+		metadata[metaKey] = metaValue
+	}
+
+	return metadata
+}
+
+// CopyNewFile copies object server side by multipart copy, because
+// S3 checks If-None-Match condition on completion of multipart upload
+// but not on single request copy.
+func (mw *S3Minio) CopyNewFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	objInfo, err := mw.mc.StatObject(ctx, srcBucket, srcPath, minio.StatObjectOptions{})
+	if err != nil {
+		return err
+	}
+
+	opts := minio.PutObjectOptions{
+		ContentType:  objInfo.ContentType,
+		UserMetadata: objInfo.UserMetadata,
+	}
+	opts.SetMatchETagExcept("")
+
+	// Empty object has no range to be copied by part.
+	if objInfo.Size == 0 {
+		_, err = mw.mc.PutObject(ctx, dstBucket, dstPath, bytes.NewReader(nil), 0, opts)
+		return newObjectError(err, dstPath)
+	}
+
+	uploadID, err := mw.core.NewMultipartUpload(ctx, dstBucket, dstPath, opts)
 	if err != nil {
 		return err
 	}
 
+	parts := make([]minio.CompletePart, 0, objInfo.Size/maxCopySize+1)
+	for offset := int64(0); offset < objInfo.Size; offset += maxCopySize {
+		length := min(maxCopySize, objInfo.Size-offset)
+		part, err := mw.core.CopyObjectPart(
+			ctx, srcBucket, srcPath, dstBucket, dstPath, uploadID, len(parts)+1, offset, length, nil,
+		)
+		if err != nil {
+			mw.abortUpload(ctx, dstBucket, dstPath, uploadID)
+			return err
+		}
+		parts = append(parts, part)
+	}
+
+	_, err = mw.core.CompleteMultipartUpload(ctx, dstBucket, dstPath, uploadID, parts, opts)
+	if err != nil {
+		mw.abortUpload(ctx, dstBucket, dstPath, uploadID)
+		return newObjectError(err, dstPath)
+	}
+
 	return nil
 }
 
-func (mw *S3Minio) MoveFile(ctx context.Context, bucket, srcPath, dstPath string) error {
-	err := mw.CopyFile(ctx, bucket, srcPath, dstPath)
+func (mw *S3Minio) MoveFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	err := mw.CopyFile(ctx, srcBucket, srcPath, dstBucket, dstPath)
+	if err != nil {
+		return err
+	}
+
+	return mw.RemoveFile(ctx, srcBucket, srcPath)
+}
+
+func (mw *S3Minio) MoveNewFile(ctx context.Context, srcBucket, srcPath, dstBucket, dstPath string) error {
+	err := mw.CopyNewFile(ctx, srcBucket, srcPath, dstBucket, dstPath)
 	if err != nil {
 		return err
 	}
 
-	return mw.RemoveFile(ctx, bucket, srcPath)
+	return mw.RemoveFile(ctx, srcBucket, srcPath)
+}
+
+func (mw *S3Minio) abortUpload(ctx context.Context, bucket, filePath, uploadID string) {
+	if err := mw.core.AbortMultipartUpload(ctx, bucket, filePath, uploadID); err != nil {
+		log.Println("failed to abort multipart upload: ", filePath, err)
+	}
+}
+
+// newObjectError reports failed If-None-Match condition as ErrFileExists.
+func newObjectError(err error, filePath string) error {
+	if err != nil && minio.ToErrorResponse(err).Code == "PreconditionFailed" {
+		return fmt.Errorf("%w: %s", cloud.ErrFileExists, filePath)
+	}
+
+	return err
 }
 
-func (mw *S3Minio) DownloadFile(ctx context.Context, bucket, filePath string) (bytes.Buffer, error) {
-	var objBody bytes.Buffer
+func (mw *S3Minio) CreateFolder(ctx context.Context, bucket, folderPath string) error {
+	folderKey := cloud.FolderKey(folderPath)
+	if folderKey == "" {
+		return fmt.Errorf("invalid folder path: %s", folderPath)
+	}
 
+	_, err := mw.mc.PutObject(ctx, bucket, folderKey, bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	return err
+}
+
+func (mw *S3Minio) RemoveFiles(ctx context.Context, bucket string, filePaths []string) map[string]error {
+	objects := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objects)
+		for _, filePath := range filePaths {
+			select {
+			case objects <- minio.ObjectInfo{Key: filePath}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	removeErrs := make(map[string]error)
+	for removeErr := range mw.mc.RemoveObjects(ctx, bucket, objects, minio.RemoveObjectsOptions{}) {
+		removeErrs[removeErr.ObjectName] = removeErr.Err
+	}
+
+	return removeErrs
+}
+
+func (mw *S3Minio) DownloadFile(ctx context.Context, bucket, filePath string) (io.ReadCloser, error) {
 	opts := minio.GetObjectOptions{}
-	obj, err := mw.mc.GetObject(ctx, bucket, filePath, opts)
+	return mw.getObject(ctx, bucket, filePath, opts)
+}
+
+func (mw *S3Minio) DownloadRange(ctx context.Context, bucket, filePath string, offset, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+
+	return mw.getObject(ctx, bucket, filePath, opts)
+}
+
+func (mw *S3Minio) StatFile(ctx context.Context, bucket, filePath string) (*cloud.StorageItem, error) {
+	opts := minio.StatObjectOptions{}
+	objInfo, err := mw.mc.StatObject(ctx, bucket, filePath, opts)
 	if err != nil {
-		return objBody, err
+		return nil, err
 	}
 
-	_, err = objBody.ReadFrom(obj)
+	return storageItem(&objInfo, cloud.DirectoryOf(objInfo.Key)), nil
+}
+
+func (mw *S3Minio) getObject(ctx context.Context, bucket, filePath string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	obj, err := mw.mc.GetObject(ctx, bucket, filePath, opts)
 	if err != nil {
-		return objBody, err
+		return nil, err
+	}
+
+	// GetObject is lazy, so stat object to return errors before streaming.
+	if _, err = obj.Stat(); err != nil {
+		_ = obj.Close()
+		return nil, err
 	}
 
-	return objBody, nil
+	return obj, nil
 }
 
 func (mw *S3Minio) GetShareURL(ctx context.Context, bucket, filePath string, expired time.Duration) (string, error) {
-	url, err := mw.mc.PresignedGetObject(ctx, bucket, filePath, expired, map[string][]string{})
+	url, err := mw.signer.PresignedGetObject(ctx, bucket, filePath, expired, map[string][]string{})
 	if err != nil {
 		return "", err
 	}
 
-	return url.String(), nil
+	return mw.publicURL(url), nil
 }
 
-func (mw *S3Minio) UploadExpired(ctx context.Context, bucket, filePath string, expired time.Time, data bytes.Buffer) error {
+func (mw *S3Minio) UploadExpired(ctx context.Context, bucket, filePath string, expired time.Time, data io.Reader, size int64) error {
 	opts := minio.PutObjectOptions{
-		Expires: expired,
+		ContentType:  cloud.ContentTypeOf(filePath),
+		UserMetadata: map[string]string{expiresAtMeta: expired.UTC().Format(time.RFC3339)},
+	}
+	if size < 0 {
+		opts.PartSize = uploadPartSize
 	}
 
-	dataLen := int64(data.Len())
-	_, err := mw.mc.PutObject(ctx, bucket, filePath, &data, dataLen, opts)
+	_, err := mw.mc.PutObject(ctx, bucket, filePath, data, size, opts)
 	return err
+}
+
+func (mw *S3Minio) SetFileExpiry(ctx context.Context, bucket, filePath string, expiresAt *time.Time) error {
+	objInfo, err := mw.mc.StatObject(ctx, bucket, filePath, minio.StatObjectOptions{})
+	if err != nil {
+		return err
+	}
+
+	userMetadata := objectMetadata(&objInfo)
+	for metaKey := range userMetadata {
+		if isExpiresAtMeta(metaKey) {
+			delete(userMetadata, metaKey)
+		}
+	}
+	if expiresAt != nil {
+		userMetadata[expiresAtMeta] = expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	// Metadata of stored object can't be changed, so object is copied onto itself.
+	srcOpts := minio.CopySrcOptions{Bucket: bucket, Object: filePath}
+	dstOpts := minio.CopyDestOptions{
+		Bucket:          bucket,
+		Object:          filePath,
+		UserMetadata:    userMetadata,
+		ReplaceMetadata: true,
+	}
+	return mw.copyObject(ctx, dstOpts, srcOpts)
+}
+
+func (mw *S3Minio) RemoveExpired(ctx context.Context, bucket string, now time.Time) (int, error) {
+	filePaths := make([]string, 0)
+	opts := minio.ListObjectsOptions{Recursive: true, WithMetadata: true}
+	for obj := range mw.mc.ListObjects(ctx, bucket, opts) {
+		if obj.Err != nil {
+			return 0, obj.Err
+		}
+
+		expiresAt := expiresAtOf(obj.UserMetadata)
+		if expiresAt != nil && !now.Before(*expiresAt) {
+			filePaths = append(filePaths, obj.Key)
+		}
+	}
+
+	if len(filePaths) == 0 {
+		return 0, nil
+	}
+
+	removed := len(filePaths)
+	var removeErr error
+	for filePath, err := range mw.RemoveFiles(ctx, bucket, filePaths) {
+		if err == nil {
+			continue
+		}
+		if removeErr == nil {
+			removeErr = fmt.Errorf("failed to remove %s: %w", filePath, err)
+		}
+		removed--
+	}
+
+	return removed, removeErr
+}
+
+// isExpiresAtMeta reports whether metadata key is expiry of object. Stat
+// strips amz prefix of user metadata, while listing keeps it as is.
+func isExpiresAtMeta(metaKey string) bool {
+	metaKey = strings.TrimPrefix(strings.ToLower(metaKey), "x-amz-meta-")
+	return metaKey == strings.ToLower(expiresAtMeta)
+}
+
+func expiresAtOf(userMetadata map[string]string) *time.Time {
+	for metaKey, metaValue := range userMetadata {
+		if !isExpiresAtMeta(metaKey) {
+			continue
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, metaValue)
+		if err != nil {
+			return nil
+		}
+		return &expiresAt
+	}
+
+	return nil
+}
+
+func storageItem(obj *minio.ObjectInfo, dirName string) *cloud.StorageItem {
+	item := &cloud.StorageItem{
+		FileName:      obj.Key,
+		DirectoryName: dirName,
+		IsDirectory:   len(obj.ETag) == 0 || cloud.IsFolderKey(obj.Key),
+		Size:          obj.Size,
+		LastModified:  obj.LastModified,
+		ETag:          obj.ETag,
+		ContentType:   obj.ContentType,
+		StorageClass:  obj.StorageClass,
+		UserMetadata:  obj.UserMetadata,
+	}
+
+	if item.IsDirectory {
+		item.StorageClass = ""
+		return item
+	}
+
+	if item.StorageClass == "" {
+		item.StorageClass = cloud.DefaultStorageClass
+	}
+
+	item.ExpiresAt = expiresAtOf(obj.UserMetadata)
+	if item.ExpiresAt == nil && !obj.Expiration.IsZero() {
+		item.ExpiresAt = &obj.Expiration
+	}
 
+	return item
 }
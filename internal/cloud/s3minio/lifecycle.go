@@ -0,0 +1,85 @@
+package s3minio
+
+import (
+	"context"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+func (mw *S3Minio) SetBucketLifecycle(ctx context.Context, bucket string, rules []*cloud.LifecycleRule) error {
+	if err := cloud.CheckLifecycleRules(rules); err != nil {
+		return err
+	}
+
+	config := lifecycle.NewConfiguration()
+	for _, rule := range rules {
+		config.Rules = append(config.Rules, lifecycleRule(rule))
+	}
+
+	return mw.mc.SetBucketLifecycle(ctx, bucket, config)
+}
+
+func (mw *S3Minio) GetBucketLifecycle(ctx context.Context, bucket string) ([]*cloud.LifecycleRule, error) {
+	rules := make([]*cloud.LifecycleRule, 0)
+	config, err := mw.mc.GetBucketLifecycle(ctx, bucket)
+	if minio.ToErrorResponse(err).Code == "NoSuchLifecycleConfiguration" {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range config.Rules {
+		prefix := rule.RuleFilter.Prefix
+		if prefix == "" {
+			prefix = rule.Prefix
+		}
+
+		rules = append(rules, &cloud.LifecycleRule{
+			ID:                        rule.ID,
+			Status:                    rule.Status,
+			Prefix:                    prefix,
+			ExpirationDays:            int(rule.Expiration.Days),
+			NoncurrentExpirationDays:  int(rule.NoncurrentVersionExpiration.NoncurrentDays),
+			AbortIncompleteUploadDays: int(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation),
+			TransitionDays:            int(rule.Transition.Days),
+			TransitionStorageClass:    rule.Transition.StorageClass,
+		})
+	}
+
+	return rules, nil
+}
+
+// ApplyLifecycle does nothing, because S3 applies lifecycle rules itself.
+func (mw *S3Minio) ApplyLifecycle(_ context.Context, _ string, _ time.Time) error {
+	return nil
+}
+
+func lifecycleRule(rule *cloud.LifecycleRule) lifecycle.Rule {
+	status := rule.Status
+	if status == "" {
+		status = cloud.LifecycleEnabled
+	}
+
+	return lifecycle.Rule{
+		ID:         rule.ID,
+		Status:     status,
+		RuleFilter: lifecycle.Filter{Prefix: rule.Prefix},
+		Expiration: lifecycle.Expiration{
+			Days: lifecycle.ExpirationDays(rule.ExpirationDays),
+		},
+		NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{
+			NoncurrentDays: lifecycle.ExpirationDays(rule.NoncurrentExpirationDays),
+		},
+		AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: lifecycle.ExpirationDays(rule.AbortIncompleteUploadDays),
+		},
+		Transition: lifecycle.Transition{
+			Days:         lifecycle.ExpirationDays(rule.TransitionDays),
+			StorageClass: rule.TransitionStorageClass,
+		},
+	}
+}
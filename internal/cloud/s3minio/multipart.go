@@ -0,0 +1,95 @@
+package s3minio
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"docs-hub/internal/cloud"
+	"github.com/minio/minio-go/v7"
+)
+
+const maxListParts = 1000
+
+func (mw *S3Minio) CreateUpload(ctx context.Context, bucket, filePath string) (string, error) {
+	opts := minio.PutObjectOptions{
+		ContentType: cloud.ContentTypeOf(filePath),
+	}
+
+	return mw.core.NewMultipartUpload(ctx, bucket, filePath, opts)
+}
+
+func (mw *S3Minio) UploadPart(
+	ctx context.Context,
+	bucket, filePath, uploadID string,
+	partNumber int,
+	data io.Reader,
+	size int64,
+) (*cloud.UploadPart, error) {
+	opts := minio.PutObjectPartOptions{}
+	objPart, err := mw.core.PutObjectPart(ctx, bucket, filePath, uploadID, partNumber, data, size, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return uploadPart(&objPart), nil
+}
+
+func (mw *S3Minio) ListUploadParts(ctx context.Context, bucket, filePath, uploadID string) ([]*cloud.UploadPart, error) {
+	parts := make([]*cloud.UploadPart, 0)
+
+	partMarker := 0
+	for {
+		result, err := mw.core.ListObjectParts(ctx, bucket, filePath, uploadID, partMarker, maxListParts)
+		if err != nil {
+			return nil, err
+		}
+
+		for index := range result.ObjectParts {
+			parts = append(parts, uploadPart(&result.ObjectParts[index]))
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		partMarker = result.NextPartNumberMarker
+	}
+
+	return parts, nil
+}
+
+func (mw *S3Minio) CompleteUpload(ctx context.Context, bucket, filePath, uploadID string) error {
+	parts, err := mw.ListUploadParts(ctx, bucket, filePath, uploadID)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	completeParts := make([]minio.CompletePart, len(parts))
+	for index, part := range parts {
+		completeParts[index] = minio.CompletePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		}
+	}
+
+	opts := minio.PutObjectOptions{}
+	_, err = mw.core.CompleteMultipartUpload(ctx, bucket, filePath, uploadID, completeParts, opts)
+	return err
+}
+
+func (mw *S3Minio) AbortUpload(ctx context.Context, bucket, filePath, uploadID string) error {
+	return mw.core.AbortMultipartUpload(ctx, bucket, filePath, uploadID)
+}
+
+func uploadPart(objPart *minio.ObjectPart) *cloud.UploadPart {
+	return &cloud.UploadPart{
+		PartNumber:   objPart.PartNumber,
+		Size:         objPart.Size,
+		ETag:         objPart.ETag,
+		LastModified: objPart.LastModified,
+	}
+}
@@ -0,0 +1,103 @@
+package s3minio
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"github.com/minio/minio-go/v7"
+)
+
+// filenameVar is replaced by S3 with name of uploaded file,
+// so that POST form uploads any file into folder.
+const filenameVar = "${filename}"
+
+func (mw *S3Minio) PresignUpload(ctx context.Context, bucket string, policy *cloud.UploadPolicy) (*cloud.PresignedUpload, error) {
+	if err := policy.Check(); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().UTC().Add(policy.Expired)
+	if policy.Method == http.MethodPut {
+		return mw.presignPut(ctx, bucket, policy, expiresAt)
+	}
+
+	postPolicy := minio.NewPostPolicy()
+	if err := postPolicy.SetBucket(bucket); err != nil {
+		return nil, err
+	}
+
+	if err := postPolicy.SetExpires(expiresAt); err != nil {
+		return nil, err
+	}
+
+	folderKey := ""
+	if policy.Folder != "" {
+		folderKey = cloud.FolderKey(policy.Folder)
+		if err := postPolicy.SetKeyStartsWith(folderKey); err != nil {
+			return nil, err
+		}
+	} else if err := postPolicy.SetKey(policy.FilePath); err != nil {
+		return nil, err
+	}
+
+	if policy.ContentType != "" {
+		if err := postPolicy.SetContentType(policy.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	if policy.MaxSize > 0 {
+		if err := postPolicy.SetContentLengthRange(0, policy.MaxSize); err != nil {
+			return nil, err
+		}
+	}
+
+	postURL, formData, err := mw.signer.PresignedPostPolicy(ctx, postPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if folderKey != "" {
+		formData["key"] = folderKey + filenameVar
+	}
+
+	return &cloud.PresignedUpload{
+		Method:    http.MethodPost,
+		URL:       mw.publicURL(postURL),
+		FormData:  formData,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// presignPut signs PUT of single file, content type is signed
+// as header which client must send with the same value.
+func (mw *S3Minio) presignPut(
+	ctx context.Context,
+	bucket string,
+	policy *cloud.UploadPolicy,
+	expiresAt time.Time,
+) (*cloud.PresignedUpload, error) {
+	headers := http.Header{}
+	if policy.ContentType != "" {
+		headers.Set("Content-Type", policy.ContentType)
+	}
+
+	putURL, err := mw.signer.PresignHeader(ctx, http.MethodPut, bucket, policy.FilePath, policy.Expired, url.Values{}, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &cloud.PresignedUpload{
+		Method:    http.MethodPut,
+		URL:       mw.publicURL(putURL),
+		ExpiresAt: expiresAt,
+	}
+	if policy.ContentType != "" {
+		upload.Headers = map[string]string{"Content-Type": policy.ContentType}
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,96 @@
+package s3minio
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+// newTestCloud returns cloud which signs URLs for public endpoint
+// with fixed region, so that presigning never connects to cloud.
+func newTestCloud(t *testing.T, proxyPresigned bool) *S3Minio {
+	t.Helper()
+
+	hub := New(&cloud.CloudConfig{
+		Address:        "minio:9000",
+		Username:       "user",
+		Password:       "password",
+		PublicEndpoint: "https://files.example.com",
+		Region:         "eu-west-1",
+		ProxyPresigned: proxyPresigned,
+	})
+
+	return hub.Cloud.(*S3Minio)
+}
+
+func TestPresignPutUpload(t *testing.T) {
+	mw := newTestCloud(t, false)
+
+	policy := &cloud.UploadPolicy{
+		Method:      http.MethodPut,
+		FilePath:    "in/a.pdf",
+		ContentType: "application/pdf",
+		Expired:     time.Hour,
+	}
+	upload, err := mw.PresignUpload(context.Background(), "contracts", policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	putURL, err := url.Parse(upload.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if putURL.Host != "files.example.com" || putURL.Path != "/contracts/in/a.pdf" {
+		t.Fatalf("URL %s is not signed for public endpoint", upload.URL)
+	}
+	query := putURL.Query()
+	if query.Get("X-Amz-Signature") == "" || query.Get("X-Amz-Expires") != "3600" {
+		t.Fatalf("URL %s is not presigned for an hour", upload.URL)
+	}
+	if signed := query.Get("X-Amz-SignedHeaders"); signed != "content-type;host" {
+		t.Fatalf("content type is not signed: %s", signed)
+	}
+	if upload.Method != http.MethodPut || upload.Headers["Content-Type"] != "application/pdf" {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	policy.MaxSize = 10
+	if _, err = mw.PresignUpload(context.Background(), "contracts", policy); err == nil {
+		t.Fatal("size of PUT upload is constrained")
+	}
+}
+
+func TestPresignPostUpload(t *testing.T) {
+	mw := newTestCloud(t, false)
+
+	policy := &cloud.UploadPolicy{
+		Method:  http.MethodPost,
+		Folder:  "in",
+		MaxSize: 10,
+		Expired: time.Hour,
+	}
+	upload, err := mw.PresignUpload(context.Background(), "contracts", policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if upload.Method != http.MethodPost || upload.URL != "https://files.example.com/contracts/" {
+		t.Fatalf("unexpected upload %s %s", upload.Method, upload.URL)
+	}
+	if key := upload.FormData["key"]; key != "in/"+filenameVar {
+		t.Fatalf("file of folder upload is not named by client: %q", key)
+	}
+	for _, field := range []string{"policy", "x-amz-signature", "x-amz-credential"} {
+		if upload.FormData[field] == "" {
+			t.Fatalf("form data has no %s: %+v", field, upload.FormData)
+		}
+	}
+	if until := time.Until(upload.ExpiresAt); until <= 0 || until > time.Hour {
+		t.Fatalf("upload expires at %v", upload.ExpiresAt)
+	}
+}
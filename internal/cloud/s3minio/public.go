@@ -0,0 +1,104 @@
+package s3minio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"docs-hub/internal/cloud"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// defaultRegion is region of MinIO which has not been configured otherwise.
+const defaultRegion = "us-east-1"
+
+// newSigner creates client which signs URLs for public endpoint of cloud.
+// Region is fixed, so that client never connects to public host itself.
+func newSigner(config *cloud.CloudConfig, creds *credentials.Credentials) (*minio.Client, error) {
+	endpoint, err := parseEndpoint(config.PublicEndpoint, config.EnableSSL)
+	if err != nil {
+		return nil, err
+	}
+
+	region := config.Region
+	if region == "" {
+		region = defaultRegion
+	}
+
+	signerOpts := &minio.Options{
+		Creds:  creds,
+		Secure: endpoint.Scheme == "https",
+		Region: region,
+	}
+
+	return minio.New(endpoint.Host, signerOpts)
+}
+
+// parseEndpoint parses endpoint which is either host with optional
+// port or URL with scheme and without path.
+func parseEndpoint(endpoint string, secure bool) (*url.URL, error) {
+	if !strings.Contains(endpoint, "://") {
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpointURL.Host == "" || strings.Trim(endpointURL.Path, "/") != "" {
+		return nil, fmt.Errorf("public endpoint must be host or URL without path: %s", endpoint)
+	}
+
+	// default port is not signed, proxy must send host the same way
+	port := endpointURL.Port()
+	if endpointURL.Scheme == "http" && port == "80" || endpointURL.Scheme == "https" && port == "443" {
+		endpointURL.Host = strings.TrimSuffix(endpointURL.Host, ":"+port)
+	}
+
+	return endpointURL, nil
+}
+
+// publicURL returns presigned URL users open, it is moved under
+// proxy path of docs-hub if presigned URLs are proxied.
+func (mw *S3Minio) publicURL(presigned *url.URL) string {
+	if !mw.config.ProxyPresigned {
+		return presigned.String()
+	}
+
+	proxied := *presigned
+	proxied.Path = cloud.PresignProxyPath + presigned.Path
+	if presigned.RawPath != "" {
+		proxied.RawPath = cloud.PresignProxyPath + presigned.RawPath
+	}
+
+	return proxied.String()
+}
+
+// PresignProxy forwards requests of presigned URLs to internal address
+// of cloud. Host header is replaced by public host URLs are signed for,
+// so that cloud verifies signature as if it was requested directly.
+func (mw *S3Minio) PresignProxy() http.Handler {
+	if !mw.config.ProxyPresigned {
+		return nil
+	}
+
+	target := mw.mc.EndpointURL()
+	publicHost := mw.signer.EndpointURL().Host
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.SetXForwarded()
+			r.Out.Host = publicHost
+			r.Out.Header.Del("Authorization")
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package s3minio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		secure    bool
+		expected  string
+		isInvalid bool
+	}{
+		{name: "host", endpoint: "files.example.com", expected: "http://files.example.com"},
+		{name: "secure host", endpoint: "files.example.com", secure: true, expected: "https://files.example.com"},
+		{name: "host with port", endpoint: "files.example.com:9000", expected: "http://files.example.com:9000"},
+		{name: "URL", endpoint: "https://files.example.com", expected: "https://files.example.com"},
+		{name: "root path", endpoint: "https://files.example.com/", expected: "https://files.example.com/"},
+		{name: "default https port", endpoint: "https://files.example.com:443", expected: "https://files.example.com"},
+		{name: "default http port", endpoint: "http://files.example.com:80", expected: "http://files.example.com"},
+		{name: "URL with path", endpoint: "https://docs.example.com/minio", isInvalid: true},
+		{name: "no host", endpoint: "https://", isInvalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint, err := parseEndpoint(test.endpoint, test.secure)
+			if test.isInvalid {
+				if err == nil {
+					t.Fatalf("expected error, got endpoint %s", endpoint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if endpoint.String() != test.expected {
+				t.Fatalf("endpoint %s, expected %s", endpoint, test.expected)
+			}
+		})
+	}
+}
+
+func TestProxiedShareURL(t *testing.T) {
+	mw := newTestCloud(t, true)
+
+	shareURL, err := mw.GetShareURL(context.Background(), "contracts", "in/a b.pdf", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(shareURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Host != "files.example.com" || parsed.Path != cloud.PresignProxyPath+"/contracts/in/a b.pdf" {
+		t.Fatalf("share URL %s is not moved under proxy path", shareURL)
+	}
+	if parsed.Query().Get("X-Amz-Signature") == "" {
+		t.Fatalf("share URL %s is not presigned", shareURL)
+	}
+
+	if handler := newTestCloud(t, false).PresignProxy(); handler != nil {
+		t.Fatal("proxy is served while it is disabled")
+	}
+}
+
+func TestPresignProxy(t *testing.T) {
+	var received *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer backend.Close()
+
+	hub := New(&cloud.CloudConfig{
+		Address:        strings.TrimPrefix(backend.URL, "http://"),
+		Username:       "user",
+		Password:       "password",
+		PublicEndpoint: "https://docs.example.com",
+		Region:         "eu-west-1",
+		ProxyPresigned: true,
+	})
+	mw := hub.Cloud.(*S3Minio)
+
+	// Proxy path is stripped by docs-hub before request reaches handler.
+	req := httptest.NewRequest(http.MethodGet, "/contracts/in/a.pdf?X-Amz-Signature=abc", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	mw.PresignProxy().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "content" {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if received.Host != "docs.example.com" {
+		t.Fatalf("cloud gets host %s instead of signed public host", received.Host)
+	}
+	if received.URL.Path != "/contracts/in/a.pdf" || received.URL.Query().Get("X-Amz-Signature") != "abc" {
+		t.Fatalf("cloud gets URL %s", received.URL)
+	}
+	if received.Header.Get("Authorization") != "" {
+		t.Fatal("authorization of docs-hub is forwarded to cloud")
+	}
+}
@@ -0,0 +1,88 @@
+package s3minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"docs-hub/internal/cloud"
+	"github.com/minio/minio-go/v7"
+)
+
+func (mw *S3Minio) SetBucketVersioning(ctx context.Context, bucket, status string) error {
+	switch status {
+	case cloud.VersioningEnabled:
+		return mw.mc.EnableVersioning(ctx, bucket)
+	case cloud.VersioningSuspended:
+		return mw.mc.SuspendVersioning(ctx, bucket)
+	default:
+		return fmt.Errorf("unknown versioning status: %s", status)
+	}
+}
+
+func (mw *S3Minio) GetBucketVersioning(ctx context.Context, bucket string) (string, error) {
+	versioning, err := mw.mc.GetBucketVersioning(ctx, bucket)
+	if err != nil {
+		return "", err
+	}
+
+	return versioning.Status, nil
+}
+
+func (mw *S3Minio) ListFileVersions(ctx context.Context, bucket, filePath string) ([]*cloud.FileVersion, error) {
+	opts := minio.ListObjectsOptions{
+		Prefix:       filePath,
+		Recursive:    true,
+		WithVersions: true,
+	}
+
+	// Prefix matches other objects starting with the same key too,
+	// versions of the key are listed first and in a row.
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	versions := make([]*cloud.FileVersion, 0)
+	for obj := range mw.mc.ListObjects(listCtx, bucket, opts) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		if obj.Key != filePath {
+			if len(versions) > 0 {
+				break
+			}
+			continue
+		}
+
+		versions = append(versions, &cloud.FileVersion{
+			VersionID:      obj.VersionID,
+			IsLatest:       obj.IsLatest,
+			IsDeleteMarker: obj.IsDeleteMarker,
+			Size:           obj.Size,
+			LastModified:   obj.LastModified,
+			ETag:           obj.ETag,
+		})
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("object %s does not exist", filePath)
+	}
+
+	return versions, nil
+}
+
+func (mw *S3Minio) DownloadVersion(ctx context.Context, bucket, filePath, versionID string) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{VersionID: versionID}
+	return mw.getObject(ctx, bucket, filePath, opts)
+}
+
+func (mw *S3Minio) RestoreVersion(ctx context.Context, bucket, filePath, versionID string) error {
+	srcOpts := minio.CopySrcOptions{Bucket: bucket, Object: filePath, VersionID: versionID}
+	dstOpts := minio.CopyDestOptions{Bucket: bucket, Object: filePath}
+	return mw.copyObject(ctx, dstOpts, srcOpts)
+}
+
+func (mw *S3Minio) RemoveVersion(ctx context.Context, bucket, filePath, versionID string) error {
+	opts := minio.RemoveObjectOptions{VersionID: versionID}
+	return mw.mc.RemoveObject(ctx, bucket, filePath, opts)
+}
@@ -0,0 +1,75 @@
+package cloud
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// shareSecretLength is length of random secret generated
+// when share secret is not configured.
+const shareSecretLength = 32
+
+// URLSigner builds and verifies share URLs served by docs-hub itself
+// for clouds which have no presigning service.
+type URLSigner struct {
+	secret []byte
+}
+
+// NewURLSigner signs URLs by configured share secret. Random secret is
+// generated if it is not configured, URLs are invalidated by restart then.
+func NewURLSigner(config *CloudConfig) *URLSigner {
+	if config.ShareSecret != "" {
+		return &URLSigner{secret: []byte(config.ShareSecret)}
+	}
+
+	secret := make([]byte, shareSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalln("failed to generate share url secret: ", err)
+	}
+	log.Println("WARNING: cloud share secret is not set, share urls are invalidated by restart")
+
+	return &URLSigner{secret: secret}
+}
+
+// SignURL returns share URL relative to docs-hub address, because
+// cloud does not know address which docs-hub is reached by.
+func (us *URLSigner) SignURL(bucket, filePath string, expired time.Duration) string {
+	expires := time.Now().Add(expired).Unix()
+	shareURL := &url.URL{
+		Path: fmt.Sprintf("/cloud/%s/share/%s", bucket, filePath),
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", us.sign(bucket, filePath, expires))
+	shareURL.RawQuery = query.Encode()
+
+	return shareURL.String()
+}
+
+func (us *URLSigner) VerifyURL(bucket, filePath string, expires int64, signature string) error {
+	expected := us.sign(bucket, filePath, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("invalid share url signature")
+	}
+
+	if time.Now().Unix() > expires {
+		return errors.New("share url has been expired")
+	}
+
+	return nil
+}
+
+func (us *URLSigner) sign(bucket, filePath string, expires int64) string {
+	mac := hmac.New(sha256.New, us.secret)
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%d", bucket, filePath, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,22 @@
+package cloud
+
+import "strings"
+
+// StagingDirName is prefix of per-bucket area where files of atomic
+// uploads are kept until every file of request has been uploaded.
+const StagingDirName = ".staging/"
+
+// IsStagingPath reports whether object key belongs to staging area.
+func IsStagingPath(filePath string) bool {
+	return strings.HasPrefix(filePath, StagingDirName)
+}
+
+// NewStagingDir returns unique staging folder for files of one request.
+func NewStagingDir() (string, error) {
+	id, err := NewVersionID()
+	if err != nil {
+		return "", err
+	}
+
+	return StagingDirName + id + "/", nil
+}
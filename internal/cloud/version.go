@@ -0,0 +1,46 @@
+package cloud
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	VersioningEnabled   = "Enabled"
+	VersioningSuspended = "Suspended"
+
+	// NullVersionID is version of objects written while versioning
+	// is suspended or before it has been enabled, like S3 does.
+	NullVersionID = "null"
+)
+
+type FileVersion struct {
+	VersionID      string    `json:"version_id"`
+	IsLatest       bool      `json:"is_latest"`
+	IsDeleteMarker bool      `json:"is_delete_marker"`
+	Size           int64     `json:"size"`
+	LastModified   time.Time `json:"last_modified"`
+	ETag           string    `json:"etag,omitempty"`
+}
+
+func CheckVersioningStatus(status string) error {
+	switch status {
+	case VersioningEnabled, VersioningSuspended:
+		return nil
+	default:
+		return fmt.Errorf("unknown versioning status: %s", status)
+	}
+}
+
+// NewVersionID returns random version id for backends which
+// emulate versioning.
+func NewVersionID() (string, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(idBytes), nil
+}
@@ -5,16 +5,34 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/audit"
+	"docs-hub/internal/auth"
 	"docs-hub/internal/cloud"
+	"docs-hub/internal/expiry"
+	"docs-hub/internal/lifecycle"
+	"docs-hub/internal/rbac"
 	"docs-hub/internal/server"
+	"docs-hub/internal/share"
+	"docs-hub/internal/trash"
 	"github.com/lpernett/godotenv"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Cloud  cloud.CloudConfig
-	Server server.Config
+	Cloud     cloud.CloudConfig
+	Server    server.Config
+	Trash     trash.Config
+	Expiry    expiry.Config
+	Lifecycle lifecycle.Config
+	Auth      auth.Config
+	RBAC      rbac.Config
+	APIKeys   apikey.Config
+	Audit     audit.Config
+	Shares    share.Config
 }
 
 func FromFile(filePath string) (*Config, error) {
@@ -27,10 +45,49 @@ func FromFile(filePath string) (*Config, error) {
 	viperInstance.SetDefault("server.Address", "0.0.0.0:2863")
 	viperInstance.SetDefault("server.LoggerLevel", "INFO")
 
+	viperInstance.SetDefault("cloud.Provider", "s3")
 	viperInstance.SetDefault("cloud.Address", "localhost:9000")
 	viperInstance.SetDefault("cloud.Username", "minio-root")
 	viperInstance.SetDefault("cloud.Password", "minio-root")
 	viperInstance.SetDefault("cloud.EnableSSL", false)
+	viperInstance.SetDefault("cloud.RootPath", "./storage")
+	viperInstance.SetDefault("cloud.ShareSecret", "")
+	viperInstance.SetDefault("cloud.PublicEndpoint", "")
+	viperInstance.SetDefault("cloud.Region", "us-east-1")
+	viperInstance.SetDefault("cloud.ProxyPresigned", false)
+
+	viperInstance.SetDefault("trash.Enabled", true)
+	viperInstance.SetDefault("trash.Retention", "720h")
+	viperInstance.SetDefault("trash.PurgeInterval", "1h")
+
+	viperInstance.SetDefault("expiry.Enabled", true)
+	viperInstance.SetDefault("expiry.SweepInterval", "5m")
+
+	viperInstance.SetDefault("lifecycle.Enabled", true)
+	viperInstance.SetDefault("lifecycle.ApplyInterval", "1h")
+
+	viperInstance.SetDefault("auth.Enabled", false)
+	viperInstance.SetDefault("auth.Algorithm", auth.AlgorithmHS256)
+
+	viperInstance.SetDefault("rbac.Enabled", false)
+	viperInstance.SetDefault("rbac.PolicyFile", "./configs/policy.json")
+
+	viperInstance.SetDefault("apikeys.Enabled", false)
+	viperInstance.SetDefault("apikeys.StoreFile", "./configs/api-keys.json")
+	viperInstance.SetDefault("apikeys.BootstrapToken", "")
+
+	viperInstance.SetDefault("audit.Enabled", false)
+	viperInstance.SetDefault("audit.LogFile", "./audit/audit.log")
+
+	viperInstance.SetDefault("shares.Enabled", false)
+	viperInstance.SetDefault("shares.StoreFile", "./configs/share-links.json")
+	viperInstance.SetDefault("shares.UploadStoreFile", "./configs/upload-links.json")
+	viperInstance.SetDefault("shares.DefaultExpiry", "24h")
+	viperInstance.SetDefault("shares.MaxExpiry", "720h")
+	viperInstance.SetDefault("shares.MaxPasswordAttempts", 5)
+	viperInstance.SetDefault("shares.PasswordLockout", "15m")
+	viperInstance.SetDefault("shares.MaxZipFiles", 1000)
+	viperInstance.SetDefault("shares.MaxZipSize", 1<<30)
 
 	if err := viperInstance.ReadInConfig(); err != nil {
 		confErr := fmt.Errorf("failed while reading config file %s: %w", filePath, err)
@@ -52,22 +109,112 @@ func LoadEnv(enableDotenv bool) (*Config, error) {
 
 	servAddr := loadString("DOCS_HUB_SERVER_ADDRESS")
 	servLogger := loadString("DOCS_HUB_SERVER_LOGGER_LEVEL")
-	serverConfig := server.Config{Address: servAddr, LoggerLevel: servLogger}
+	servOrigins := loadList("DOCS_HUB_SERVER_ALLOW_ORIGINS")
+	servProxies := loadList("DOCS_HUB_SERVER_TRUSTED_PROXIES")
+	serverConfig := server.Config{
+		Address:        servAddr,
+		LoggerLevel:    servLogger,
+		AllowOrigins:   servOrigins,
+		TrustedProxies: servProxies,
+	}
 
+	cloudProvider := loadString("DOCS_HUB_CLOUD_PROVIDER")
 	cloudAddr := loadString("DOCS_HUB_CLOUD_ADDRESS")
 	cloudUser := loadString("DOCS_HUB_CLOUD_USERNAME")
 	cloudPasswd := loadString("DOCS_HUB_CLOUD_PASSWORD")
 	cloudEnableSSL := loadBool("DOCS_HUB_CLOUD_ENABLE_SSL")
+	cloudRootPath := loadString("DOCS_HUB_CLOUD_ROOT_PATH")
+	cloudShareSecret := loadString("DOCS_HUB_CLOUD_SHARE_SECRET")
+	cloudPublicEndpoint := loadString("DOCS_HUB_CLOUD_PUBLIC_ENDPOINT")
+	cloudRegion := loadString("DOCS_HUB_CLOUD_REGION")
+	cloudProxyPresigned := loadBool("DOCS_HUB_CLOUD_PROXY_PRESIGNED")
 	cloudConfig := cloud.CloudConfig{
-		Address:   cloudAddr,
-		Username:  cloudUser,
-		Password:  cloudPasswd,
-		EnableSSL: cloudEnableSSL,
+		Provider:       cloudProvider,
+		Address:        cloudAddr,
+		Username:       cloudUser,
+		Password:       cloudPasswd,
+		EnableSSL:      cloudEnableSSL,
+		RootPath:       cloudRootPath,
+		ShareSecret:    cloudShareSecret,
+		PublicEndpoint: cloudPublicEndpoint,
+		Region:         cloudRegion,
+		ProxyPresigned: cloudProxyPresigned,
+	}
+
+	trashEnabled := loadBool("DOCS_HUB_TRASH_ENABLED")
+	trashRetention := loadDuration("DOCS_HUB_TRASH_RETENTION")
+	trashInterval := loadDuration("DOCS_HUB_TRASH_PURGE_INTERVAL")
+	trashConfig := trash.Config{
+		Enabled:       trashEnabled,
+		Retention:     trashRetention,
+		PurgeInterval: trashInterval,
+	}
+
+	expiryEnabled := loadBool("DOCS_HUB_EXPIRY_ENABLED")
+	expiryInterval := loadDuration("DOCS_HUB_EXPIRY_SWEEP_INTERVAL")
+	expiryConfig := expiry.Config{
+		Enabled:       expiryEnabled,
+		SweepInterval: expiryInterval,
+	}
+
+	lifecycleEnabled := loadBool("DOCS_HUB_LIFECYCLE_ENABLED")
+	lifecycleInterval := loadDuration("DOCS_HUB_LIFECYCLE_APPLY_INTERVAL")
+	lifecycleConfig := lifecycle.Config{
+		Enabled:       lifecycleEnabled,
+		ApplyInterval: lifecycleInterval,
+	}
+
+	authConfig := auth.Config{
+		Enabled:       loadBool("DOCS_HUB_AUTH_ENABLED"),
+		Algorithm:     loadString("DOCS_HUB_AUTH_ALGORITHM"),
+		Secret:        loadString("DOCS_HUB_AUTH_SECRET"),
+		PublicKeyFile: loadString("DOCS_HUB_AUTH_PUBLIC_KEY_FILE"),
+		JWKSFile:      loadString("DOCS_HUB_AUTH_JWKS_FILE"),
+		Issuer:        loadString("DOCS_HUB_AUTH_ISSUER"),
+		Audience:      loadString("DOCS_HUB_AUTH_AUDIENCE"),
+		PublicRoutes:  loadList("DOCS_HUB_AUTH_PUBLIC_ROUTES"),
+	}
+
+	rbacConfig := rbac.Config{
+		Enabled:    loadBool("DOCS_HUB_RBAC_ENABLED"),
+		PolicyFile: loadString("DOCS_HUB_RBAC_POLICY_FILE"),
+	}
+
+	apiKeysConfig := apikey.Config{
+		Enabled:        loadBool("DOCS_HUB_API_KEYS_ENABLED"),
+		StoreFile:      loadString("DOCS_HUB_API_KEYS_STORE_FILE"),
+		BootstrapToken: loadString("DOCS_HUB_API_KEYS_BOOTSTRAP_TOKEN"),
+	}
+
+	auditConfig := audit.Config{
+		Enabled: loadBool("DOCS_HUB_AUDIT_ENABLED"),
+		LogFile: loadString("DOCS_HUB_AUDIT_LOG_FILE"),
+	}
+
+	sharesConfig := share.Config{
+		Enabled:             loadBool("DOCS_HUB_SHARES_ENABLED"),
+		StoreFile:           loadString("DOCS_HUB_SHARES_STORE_FILE"),
+		UploadStoreFile:     loadString("DOCS_HUB_SHARES_UPLOAD_STORE_FILE"),
+		BaseURL:             loadString("DOCS_HUB_SHARES_BASE_URL"),
+		DefaultExpiry:       loadDuration("DOCS_HUB_SHARES_DEFAULT_EXPIRY"),
+		MaxExpiry:           loadDuration("DOCS_HUB_SHARES_MAX_EXPIRY"),
+		MaxPasswordAttempts: loadNumber("DOCS_HUB_SHARES_MAX_PASSWORD_ATTEMPTS", 32),
+		PasswordLockout:     loadDuration("DOCS_HUB_SHARES_PASSWORD_LOCKOUT"),
+		MaxZipFiles:         loadNumber("DOCS_HUB_SHARES_MAX_ZIP_FILES", 32),
+		MaxZipSize:          int64(loadNumber("DOCS_HUB_SHARES_MAX_ZIP_SIZE", 64)),
 	}
 
 	return &Config{
-		Cloud:  cloudConfig,
-		Server: serverConfig,
+		Cloud:     cloudConfig,
+		Server:    serverConfig,
+		Trash:     trashConfig,
+		Expiry:    expiryConfig,
+		Lifecycle: lifecycleConfig,
+		Auth:      authConfig,
+		RBAC:      rbacConfig,
+		APIKeys:   apiKeysConfig,
+		Audit:     auditConfig,
+		Shares:    sharesConfig,
 	}, nil
 }
 
@@ -116,3 +263,38 @@ func loadBool(envName string) bool {
 
 	return boolean
 }
+
+func loadDuration(envName string) time.Duration {
+	value, exists := os.LookupEnv(envName)
+	if !exists {
+		msg := fmt.Sprintf("failed to extract %s env var: %s", envName, value)
+		log.Println(msg)
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		msg := fmt.Sprintf("failed to convert %s env var: %s", envName, value)
+		log.Println(msg)
+		return 0
+	}
+
+	return duration
+}
+
+// loadList reads comma separated list, empty items are skipped.
+func loadList(envName string) []string {
+	value := loadString(envName)
+	if value == "" {
+		return nil
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,8 @@
+package expiry
+
+import "time"
+
+type Config struct {
+	Enabled       bool
+	SweepInterval time.Duration
+}
@@ -0,0 +1,66 @@
+package expiry
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+const defaultSweepInterval = 5 * time.Minute
+
+// Sweeper removes documents which have been uploaded with expiry
+// time once it has passed.
+type Sweeper struct {
+	config *Config
+	cloud  *cloud.DocumentHub
+}
+
+func New(config *Config, hub *cloud.DocumentHub) *Sweeper {
+	return &Sweeper{config: config, cloud: hub}
+}
+
+// Sweep removes expired documents of all buckets.
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	buckets, err := s.cloud.Cloud.GetBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, bucket := range buckets {
+		removed, err := s.cloud.Cloud.RemoveExpired(ctx, bucket, now)
+		if err != nil {
+			log.Println("failed to remove expired documents of bucket: ", bucket, err)
+		}
+		if removed > 0 {
+			log.Printf("removed %d expired documents of bucket %s", removed, bucket)
+		}
+	}
+
+	return nil
+}
+
+// Run sweeps expired documents periodically until context is done.
+func (s *Sweeper) Run(ctx context.Context) {
+	interval := s.config.SweepInterval
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sweep(ctx); err != nil {
+			log.Println("failed to sweep expired documents: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
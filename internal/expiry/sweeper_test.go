@@ -0,0 +1,44 @@
+package expiry
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/cloud/inmemory"
+)
+
+func TestSweep(t *testing.T) {
+	ctx := context.Background()
+	hub := inmemory.New(&cloud.CloudConfig{ShareSecret: "test"})
+	doc := hub.Cloud
+
+	for _, bucket := range []string{"b1", "b2"} {
+		if err := doc.CreateBucket(ctx, bucket); err != nil {
+			t.Fatal(err)
+		}
+		err := doc.UploadExpired(ctx, bucket, "old.txt", time.Now().Add(-time.Minute), strings.NewReader("old"), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = doc.UploadFile(ctx, bucket, "kept.txt", strings.NewReader("kept"), 4); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := New(&Config{Enabled: true}, hub).Sweep(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bucket := range []string{"b1", "b2"} {
+		removed, err := doc.RemoveExpired(ctx, bucket, time.Now())
+		if err != nil || removed != 0 {
+			t.Fatalf("bucket %s has %d expired files left, error %v", bucket, removed, err)
+		}
+		if _, err = doc.StatFile(ctx, bucket, "kept.txt"); err != nil {
+			t.Fatalf("file without expiry of bucket %s is removed: %v", bucket, err)
+		}
+	}
+}
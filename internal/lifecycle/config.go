@@ -0,0 +1,8 @@
+package lifecycle
+
+import "time"
+
+type Config struct {
+	Enabled       bool
+	ApplyInterval time.Duration
+}
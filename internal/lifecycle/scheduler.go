@@ -0,0 +1,62 @@
+package lifecycle
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+const defaultApplyInterval = time.Hour
+
+// Scheduler applies bucket lifecycle rules periodically for backends
+// which have no native lifecycle.
+type Scheduler struct {
+	config *Config
+	cloud  *cloud.DocumentHub
+}
+
+func New(config *Config, hub *cloud.DocumentHub) *Scheduler {
+	return &Scheduler{config: config, cloud: hub}
+}
+
+// Apply applies lifecycle rules of all buckets.
+func (s *Scheduler) Apply(ctx context.Context) error {
+	buckets, err := s.cloud.Cloud.GetBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, bucket := range buckets {
+		if err = s.cloud.Cloud.ApplyLifecycle(ctx, bucket, now); err != nil {
+			log.Println("failed to apply lifecycle of bucket: ", bucket, err)
+		}
+	}
+
+	return nil
+}
+
+// Run applies lifecycle rules periodically until context is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	interval := s.config.ApplyInterval
+	if interval <= 0 {
+		interval = defaultApplyInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Apply(ctx); err != nil {
+			log.Println("failed to apply lifecycle rules: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
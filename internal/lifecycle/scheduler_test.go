@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/cloud/inmemory"
+)
+
+// recordingCloud records buckets lifecycle is applied to
+// and fails for bucket named "broken".
+type recordingCloud struct {
+	cloud.ICloud
+
+	mu      sync.Mutex
+	applied []string
+}
+
+func (rc *recordingCloud) ApplyLifecycle(_ context.Context, bucket string, _ time.Time) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.applied = append(rc.applied, bucket)
+	if bucket == "broken" {
+		return errors.New("broken bucket")
+	}
+
+	return nil
+}
+
+func (rc *recordingCloud) appliedBuckets() string {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	buckets := append([]string(nil), rc.applied...)
+	sort.Strings(buckets)
+	return strings.Join(buckets, ",")
+}
+
+func newRecordingCloud(t *testing.T) *recordingCloud {
+	t.Helper()
+
+	rc := &recordingCloud{ICloud: inmemory.New(&cloud.CloudConfig{ShareSecret: "test"}).Cloud}
+	for _, bucket := range []string{"b1", "broken", "b2"} {
+		if err := rc.CreateBucket(context.Background(), bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rc
+}
+
+func TestApply(t *testing.T) {
+	rc := newRecordingCloud(t)
+
+	if err := New(&Config{Enabled: true}, &cloud.DocumentHub{Cloud: rc}).Apply(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failure of one bucket does not stop others.
+	if buckets := rc.appliedBuckets(); buckets != "b1,b2,broken" {
+		t.Fatalf("lifecycle is applied to %s", buckets)
+	}
+}
+
+func TestRunAppliesUntilCanceled(t *testing.T) {
+	rc := newRecordingCloud(t)
+	scheduler := New(&Config{Enabled: true, ApplyInterval: time.Hour}, &cloud.DocumentHub{Cloud: rc})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for rc.appliedBuckets() != "b1,b2,broken" {
+		if time.Now().After(deadline) {
+			t.Fatal("lifecycle is not applied on start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler has not stopped")
+	}
+}
@@ -0,0 +1,8 @@
+package rbac
+
+type Config struct {
+	Enabled bool
+	// PolicyFile is JSON file with role bindings, it is rewritten
+	// when bindings are changed by admin endpoints.
+	PolicyFile string
+}
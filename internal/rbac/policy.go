@@ -0,0 +1,294 @@
+package rbac
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"docs-hub/internal/auth"
+)
+
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleWriter Role = "writer"
+	RoleAdmin  Role = "admin"
+)
+
+// AnyBucket grants binding role on all buckets, global admins
+// are bound to it and manage bindings of policy.
+const AnyBucket = "*"
+
+var roleLevels = map[Role]int{
+	RoleReader: 1,
+	RoleWriter: 2,
+	RoleAdmin:  3,
+}
+
+var ErrBindingNotFound = errors.New("binding does not exist")
+
+// Binding grants role to user or group on objects of bucket under prefix
+// folder, empty prefix grants role on whole bucket including bucket settings.
+type Binding struct {
+	ID      string `json:"id" example:"5f2b9c1e4a7d3e80"`
+	Subject string `json:"subject,omitempty" example:"alice"`
+	Group   string `json:"group,omitempty" example:"legal"`
+	Bucket  string `json:"bucket" example:"contracts"`
+	Prefix  string `json:"prefix" example:"2024/"`
+	Role    Role   `json:"role" example:"writer" enums:"reader,writer,admin"`
+}
+
+func (b *Binding) check() error {
+	if (b.Subject == "") == (b.Group == "") {
+		return errors.New("binding must be granted to either subject or group")
+	}
+
+	if b.Bucket == "" {
+		return errors.New("binding bucket must not be empty")
+	}
+
+	if _, ok := roleLevels[b.Role]; !ok {
+		return fmt.Errorf("unknown role: %s", b.Role)
+	}
+
+	return nil
+}
+
+func (b *Binding) grantedTo(identity *auth.Identity) bool {
+	if b.Subject != "" {
+		return b.Subject == identity.Subject
+	}
+
+	for _, group := range identity.Groups {
+		if group == b.Group {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Binding) coversBucket(bucket string) bool {
+	return b.Bucket == AnyBucket || b.Bucket == bucket
+}
+
+// normalizePrefix makes prefix folder key, so that prefix "2024"
+// covers "2024/a.txt" but not "2024-secret/a.txt".
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+
+	return prefix + "/"
+}
+
+type policyFile struct {
+	Bindings []*Binding `json:"bindings"`
+}
+
+type Policy struct {
+	config *Config
+
+	mu       sync.RWMutex
+	bindings []*Binding
+}
+
+// New loads policy file, missing file means there are no bindings yet.
+func New(config *Config) (*Policy, error) {
+	policy := &Policy{config: config, bindings: make([]*Binding, 0)}
+	if !config.Enabled {
+		return policy, nil
+	}
+
+	if config.PolicyFile == "" {
+		return nil, errors.New("policy file is required for access control")
+	}
+
+	if err := policy.Reload(); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (p *Policy) IsEnabled() bool {
+	return p.config.Enabled
+}
+
+// Reload replaces bindings by content of policy file.
+func (p *Policy) Reload() error {
+	data, err := os.ReadFile(p.config.PolicyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	file := &policyFile{}
+	if err = json.Unmarshal(data, file); err != nil {
+		return fmt.Errorf("failed to parse policy file %s: %w", p.config.PolicyFile, err)
+	}
+
+	// Bindings added by hand may lack id, generated ids are written
+	// back so that they can be removed by admin endpoints.
+	isIDGenerated := false
+	for _, binding := range file.Bindings {
+		if err = binding.check(); err != nil {
+			return fmt.Errorf("invalid binding %s of policy file: %w", binding.ID, err)
+		}
+		binding.Prefix = normalizePrefix(binding.Prefix)
+		if binding.ID == "" {
+			if binding.ID, err = newBindingID(); err != nil {
+				return err
+			}
+			isIDGenerated = true
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if isIDGenerated {
+		return p.save(file.Bindings)
+	}
+
+	p.bindings = file.Bindings
+	return nil
+}
+
+func (p *Policy) Bindings() []*Binding {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	bindings := make([]*Binding, len(p.bindings))
+	copy(bindings, p.bindings)
+	return bindings
+}
+
+func (p *Policy) AddBinding(binding *Binding) error {
+	if err := binding.check(); err != nil {
+		return err
+	}
+	binding.Prefix = normalizePrefix(binding.Prefix)
+
+	bindingID, err := newBindingID()
+	if err != nil {
+		return err
+	}
+	binding.ID = bindingID
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.save(append(p.bindings, binding))
+}
+
+func (p *Policy) RemoveBinding(bindingID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for index, binding := range p.bindings {
+		if binding.ID == bindingID {
+			bindings := append(p.bindings[:index:index], p.bindings[index+1:]...)
+			return p.save(bindings)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrBindingNotFound, bindingID)
+}
+
+// Allows reports whether identity has at least role on object path of bucket.
+func (p *Policy) Allows(identity *auth.Identity, bucket, objPath string, role Role) bool {
+	if !p.config.Enabled {
+		return true
+	}
+	if identity == nil {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, binding := range p.bindings {
+		if binding.grantedTo(identity) && binding.coversBucket(bucket) &&
+			strings.HasPrefix(objPath, binding.Prefix) && roleLevels[binding.Role] >= roleLevels[role] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanBrowse reports whether identity may see object or folder in listing:
+// it is readable itself or it is folder leading to readable prefix.
+func (p *Policy) CanBrowse(identity *auth.Identity, bucket, objPath string) bool {
+	if p.Allows(identity, bucket, objPath, RoleReader) {
+		return true
+	}
+	if identity == nil {
+		return false
+	}
+
+	folderKey := normalizePrefix(objPath)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, binding := range p.bindings {
+		if binding.grantedTo(identity) && binding.coversBucket(bucket) &&
+			strings.HasPrefix(binding.Prefix, folderKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanSeeBucket reports whether identity has any role on bucket.
+func (p *Policy) CanSeeBucket(identity *auth.Identity, bucket string) bool {
+	return p.CanBrowse(identity, bucket, "")
+}
+
+// save writes bindings into policy file and applies them,
+// must be called with write lock held.
+func (p *Policy) save(bindings []*Binding) error {
+	data, err := json.MarshalIndent(&policyFile{Bindings: bindings}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	policyPath := p.config.PolicyFile
+	if err = os.MkdirAll(filepath.Dir(policyPath), 0o755); err != nil {
+		return err
+	}
+
+	tmpPath := policyPath + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpPath, policyPath); err != nil {
+		return err
+	}
+
+	p.bindings = bindings
+	return nil
+}
+
+func newBindingID() (string, error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(idBytes), nil
+}
@@ -0,0 +1,159 @@
+package rbac
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"docs-hub/internal/auth"
+)
+
+func newTestPolicy(t *testing.T, bindings []*Binding) *Policy {
+	t.Helper()
+
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	data, err := json.Marshal(&policyFile{Bindings: bindings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(policyPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := New(&Config{Enabled: true, PolicyFile: policyPath})
+	if err != nil {
+		t.Fatalf("failed to load policy: %v", err)
+	}
+
+	return policy
+}
+
+func TestPolicyAllows(t *testing.T) {
+	policy := newTestPolicy(t, []*Binding{
+		{Subject: "root", Bucket: AnyBucket, Role: RoleAdmin},
+		{Subject: "alice", Bucket: "contracts", Prefix: "2024/", Role: RoleWriter},
+		{Subject: "alice", Bucket: "contracts", Prefix: "", Role: RoleReader},
+		{Subject: "bob", Bucket: "contracts", Prefix: "2024/legal/", Role: RoleReader},
+		{Group: "legal", Bucket: "archive", Prefix: "cases/", Role: RoleWriter},
+		{Subject: "dave", Bucket: "reports", Prefix: "2024", Role: RoleReader},
+	})
+
+	alice := &auth.Identity{Subject: "alice"}
+	bob := &auth.Identity{Subject: "bob"}
+	lawyer := &auth.Identity{Subject: "carol", Groups: []string{"sales", "legal"}}
+	dave := &auth.Identity{Subject: "dave"}
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		bucket   string
+		objPath  string
+		role     Role
+		expected bool
+	}{
+		{name: "admin on any bucket", identity: &auth.Identity{Subject: "root"}, bucket: "b1", objPath: "x", role: RoleAdmin, expected: true},
+		{name: "anonymous", identity: nil, bucket: "contracts", objPath: "a.txt", role: RoleReader, expected: false},
+		{name: "unknown subject", identity: &auth.Identity{Subject: "mallory"}, bucket: "contracts", objPath: "a.txt", role: RoleReader, expected: false},
+		{name: "reader of whole bucket", identity: alice, bucket: "contracts", objPath: "2023/a.txt", role: RoleReader, expected: true},
+		{name: "writer under prefix", identity: alice, bucket: "contracts", objPath: "2024/a.txt", role: RoleWriter, expected: true},
+		{name: "writer outside prefix", identity: alice, bucket: "contracts", objPath: "2023/a.txt", role: RoleWriter, expected: false},
+		{name: "prefix folder key itself", identity: alice, bucket: "contracts", objPath: "2024/", role: RoleWriter, expected: true},
+		{name: "prefix is not path boundary", identity: alice, bucket: "contracts", objPath: "2024-old/a.txt", role: RoleWriter, expected: false},
+		{name: "folder without slash", identity: alice, bucket: "contracts", objPath: "2024", role: RoleWriter, expected: false},
+		{name: "role above binding", identity: alice, bucket: "contracts", objPath: "2024/a.txt", role: RoleAdmin, expected: false},
+		{name: "other bucket", identity: alice, bucket: "archive", objPath: "2024/a.txt", role: RoleReader, expected: false},
+		{name: "nested prefix", identity: bob, bucket: "contracts", objPath: "2024/legal/nda.pdf", role: RoleReader, expected: true},
+		{name: "parent of nested prefix", identity: bob, bucket: "contracts", objPath: "2024/a.txt", role: RoleReader, expected: false},
+		{name: "bucket root of nested prefix", identity: bob, bucket: "contracts", objPath: "", role: RoleReader, expected: false},
+		{name: "group binding", identity: lawyer, bucket: "archive", objPath: "cases/1.pdf", role: RoleWriter, expected: true},
+		{name: "group outside prefix", identity: lawyer, bucket: "archive", objPath: "misc/1.pdf", role: RoleReader, expected: false},
+		{name: "prefix without slash", identity: dave, bucket: "reports", objPath: "2024/x", role: RoleReader, expected: true},
+		{name: "prefix without slash is path boundary", identity: dave, bucket: "reports", objPath: "2024-secret/x", role: RoleReader, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			allowed := policy.Allows(test.identity, test.bucket, test.objPath, test.role)
+			if allowed != test.expected {
+				t.Fatalf("Allows(%s, %s) = %v, expected %v", test.bucket, test.objPath, allowed, test.expected)
+			}
+		})
+	}
+}
+
+func TestPolicyCanBrowse(t *testing.T) {
+	policy := newTestPolicy(t, []*Binding{
+		{Subject: "bob", Bucket: "contracts", Prefix: "2024/legal", Role: RoleReader},
+	})
+
+	bob := &auth.Identity{Subject: "bob"}
+	tests := []struct {
+		name     string
+		bucket   string
+		objPath  string
+		expected bool
+	}{
+		{name: "bucket root leads to prefix", bucket: "contracts", objPath: "", expected: true},
+		{name: "parent folder leads to prefix", bucket: "contracts", objPath: "2024/", expected: true},
+		{name: "parent folder without slash", bucket: "contracts", objPath: "2024", expected: true},
+		{name: "partial folder name", bucket: "contracts", objPath: "20", expected: false},
+		{name: "partial prefix segment", bucket: "contracts", objPath: "2024/leg", expected: false},
+		{name: "file of similar folder", bucket: "contracts", objPath: "2024/legal-old/a.txt", expected: false},
+		{name: "readable file", bucket: "contracts", objPath: "2024/legal/nda.pdf", expected: true},
+		{name: "sibling file", bucket: "contracts", objPath: "2024/a.txt", expected: false},
+		{name: "sibling folder", bucket: "contracts", objPath: "2024/sales/", expected: false},
+		{name: "other bucket", bucket: "archive", objPath: "", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if allowed := policy.CanBrowse(bob, test.bucket, test.objPath); allowed != test.expected {
+				t.Fatalf("CanBrowse(%s, %s) = %v, expected %v", test.bucket, test.objPath, allowed, test.expected)
+			}
+		})
+	}
+}
+
+func TestPolicyDisabledAllowsAll(t *testing.T) {
+	policy, err := New(&Config{Enabled: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !policy.Allows(nil, "contracts", "a.txt", RoleAdmin) {
+		t.Fatal("disabled policy must allow anonymous caller")
+	}
+}
+
+func TestPolicyReloadGeneratesIDs(t *testing.T) {
+	policy := newTestPolicy(t, []*Binding{
+		{Subject: "alice", Bucket: "contracts", Role: RoleReader},
+	})
+
+	bindings := policy.Bindings()
+	if len(bindings) != 1 || bindings[0].ID == "" {
+		t.Fatalf("expected binding with generated id, got %+v", bindings)
+	}
+
+	if err := policy.RemoveBinding(bindings[0].ID); err != nil {
+		t.Fatalf("failed to remove binding: %v", err)
+	}
+	if policy.Allows(&auth.Identity{Subject: "alice"}, "contracts", "a.txt", RoleReader) {
+		t.Fatal("removed binding still grants access")
+	}
+}
+
+func TestPolicyNormalizesPrefix(t *testing.T) {
+	policy := newTestPolicy(t, []*Binding{
+		{Subject: "alice", Bucket: "contracts", Prefix: "2024", Role: RoleReader},
+	})
+	if err := policy.AddBinding(&Binding{Subject: "bob", Bucket: "contracts", Prefix: "/2025/", Role: RoleReader}); err != nil {
+		t.Fatal(err)
+	}
+
+	bindings := policy.Bindings()
+	if len(bindings) != 2 || bindings[0].Prefix != "2024/" || bindings[1].Prefix != "2025/" {
+		t.Fatalf("expected folder prefixes, got %+v", bindings)
+	}
+}
@@ -3,4 +3,10 @@ package server
 type Config struct {
 	Address     string
 	LoggerLevel string
+	// AllowOrigins are origins allowed to call API from browser by CORS,
+	// cross-origin requests are not allowed if it is empty.
+	AllowOrigins []string
+	// TrustedProxies are CIDR ranges of proxies which X-Forwarded-For
+	// header is trusted from, address of peer is client IP if it is empty.
+	TrustedProxies []string
 }
@@ -0,0 +1,76 @@
+package httpserv
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+var errAccessDenied = errors.New("access denied")
+
+// roleOperations maps role required by handler to operation
+// which API key must be scoped to.
+var roleOperations = map[rbac.Role]apikey.Operation{
+	rbac.RoleReader: apikey.OperationRead,
+	rbac.RoleWriter: apikey.OperationWrite,
+	rbac.RoleAdmin:  apikey.OperationAdmin,
+}
+
+// allows decides access of API key callers by key scope,
+// access of other callers is decided by policy.
+func (s *ServerHttp) allows(c echo.Context, bucket, objPath string, role rbac.Role) bool {
+	if key := requestAPIKey(c); key != nil {
+		return key.Allows(bucket, objPath, roleOperations[role])
+	}
+
+	return s.policy.Allows(requestIdentity(c), bucket, objPath, role)
+}
+
+// canBrowse reports whether object or folder is shown to caller in listing.
+func (s *ServerHttp) canBrowse(c echo.Context, bucket, objPath string) bool {
+	if key := requestAPIKey(c); key != nil {
+		return key.CanBrowse(bucket, objPath)
+	}
+
+	return s.policy.CanBrowse(requestIdentity(c), bucket, objPath)
+}
+
+// canSeeBucket reports whether bucket is shown to caller in bucket list.
+func (s *ServerHttp) canSeeBucket(c echo.Context, bucket string) bool {
+	if key := requestAPIKey(c); key != nil {
+		return key.CoversBucket(bucket)
+	}
+
+	return s.policy.CanSeeBucket(requestIdentity(c), bucket)
+}
+
+// checkAccess checks that caller has role on object path of bucket,
+// empty path means whole bucket including its settings.
+func (s *ServerHttp) checkAccess(c echo.Context, bucket, objPath string, role rbac.Role) error {
+	if err := cloud.CheckFilePath(objPath); err != nil {
+		return err
+	}
+
+	if s.allows(c, bucket, objPath, role) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s role is required on %s/%s", errAccessDenied, role, bucket, objPath)
+}
+
+// authorize is checkAccess for handlers which are denied as a whole.
+func (s *ServerHttp) authorize(c echo.Context, bucket, objPath string, role rbac.Role) error {
+	if err := s.checkAccess(c, bucket, objPath, role); err != nil {
+		if errors.Is(err, cloud.ErrInvalidFilePath) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	return nil
+}
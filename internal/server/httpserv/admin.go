@@ -0,0 +1,287 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	errAccessControlDisabled = errors.New("access control is disabled")
+	errAPIKeysDisabled       = errors.New("api keys are disabled")
+	errAuditDisabled         = errors.New("audit is disabled")
+	errKeysBootstrapRequired = errors.New("bootstrap token is required to manage api keys while access control is disabled")
+	errAPIKeyNotAllowed      = errors.New("api keys can not manage access control or api keys")
+)
+
+// headerBootstrapToken passes bootstrap token of API keys.
+const headerBootstrapToken = "X-Bootstrap-Token"
+
+func (s *ServerHttp) CreateAdminGroup() error {
+	group := s.server.Group("/admin")
+
+	group.GET("/policy/bindings", s.GetPolicyBindings)
+	group.POST("/policy/bindings", s.AddPolicyBinding)
+	group.DELETE("/policy/bindings/:id", s.RemovePolicyBinding)
+	group.POST("/policy/reload", s.ReloadPolicy)
+
+	group.GET("/keys", s.GetAPIKeys)
+	group.POST("/keys", s.CreateAPIKey)
+	group.DELETE("/keys/:id", s.RevokeAPIKey)
+
+	group.GET("/audit", s.GetAuditEvents)
+	group.GET("/audit/verify", s.VerifyAuditLog)
+
+	return nil
+}
+
+// checkPolicyAdmin checks that access control is on and caller is global
+// admin user. API keys are rejected even with admin scope, otherwise key
+// could grant its holder more than key itself is scoped to.
+func (s *ServerHttp) checkPolicyAdmin(c echo.Context) error {
+	if !s.policy.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errAccessControlDisabled.Error())
+	}
+
+	if requestAPIKey(c) != nil {
+		return echo.NewHTTPError(http.StatusForbidden, errAPIKeyNotAllowed.Error())
+	}
+
+	return s.authorize(c, rbac.AnyBucket, "", rbac.RoleAdmin)
+}
+
+// checkKeysAdmin checks that API keys are on and caller is authenticated
+// global admin user. Every caller would be admin while access control is
+// off, so keys are managed only by bootstrap token then.
+func (s *ServerHttp) checkKeysAdmin(c echo.Context) error {
+	if !s.apiKeys.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errAPIKeysDisabled.Error())
+	}
+
+	if requestAPIKey(c) != nil {
+		return echo.NewHTTPError(http.StatusForbidden, errAPIKeyNotAllowed.Error())
+	}
+
+	if !s.policy.IsEnabled() {
+		if s.apiKeys.IsBootstrapToken(c.Request().Header.Get(headerBootstrapToken)) {
+			return nil
+		}
+		return echo.NewHTTPError(http.StatusForbidden, errKeysBootstrapRequired.Error())
+	}
+
+	if requestIdentity(c) == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authentication is required")
+	}
+
+	return s.authorize(c, rbac.AnyBucket, "", rbac.RoleAdmin)
+}
+
+// GetPolicyBindings
+// @Summary Get role bindings
+// @Description Get role bindings of access control policy, admin role on all buckets is required
+// @ID get-policy-bindings
+// @Tags admin
+// @Produce json
+// @Success 200 {object} PolicyBindingsForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/policy/bindings [get]
+func (s *ServerHttp) GetPolicyBindings(c echo.Context) error {
+	if err := s.checkPolicyAdmin(c); err != nil {
+		return err
+	}
+
+	return c.JSON(200, &PolicyBindingsForm{Bindings: s.policy.Bindings()})
+}
+
+// AddPolicyBinding
+// @Summary Add role binding
+// @Description Grant role to user or group on bucket objects under prefix.
+// @Description Bucket "*" grants role on all buckets, empty prefix grants role on whole bucket.
+// @ID add-policy-binding
+// @Tags admin
+// @Accept  json
+// @Produce json
+// @Param jsonQuery body rbac.Binding true "Role binding"
+// @Success 200 {object} rbac.Binding "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/policy/bindings [post]
+func (s *ServerHttp) AddPolicyBinding(c echo.Context) error {
+	if err := s.checkPolicyAdmin(c); err != nil {
+		return err
+	}
+
+	binding := &rbac.Binding{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(binding); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.policy.AddBinding(binding); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, binding)
+}
+
+// RemovePolicyBinding
+// @Summary Remove role binding
+// @Description Revoke role binding by its id
+// @ID remove-policy-binding
+// @Tags admin
+// @Produce json
+// @Param id path string true "Binding id"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	404 {object} BadRequestForm "Binding does not exist"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/policy/bindings/{id} [delete]
+func (s *ServerHttp) RemovePolicyBinding(c echo.Context) error {
+	if err := s.checkPolicyAdmin(c); err != nil {
+		return err
+	}
+
+	err := s.policy.RemoveBinding(c.Param("id"))
+	if errors.Is(err, rbac.ErrBindingNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// ReloadPolicy
+// @Summary Reload policy file
+// @Description Replace role bindings by content of policy file after it was edited manually
+// @ID reload-policy
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/policy/reload [post]
+func (s *ServerHttp) ReloadPolicy(c echo.Context) error {
+	if err := s.checkPolicyAdmin(c); err != nil {
+		return err
+	}
+
+	if err := s.policy.Reload(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// GetAPIKeys
+// @Summary Get API keys
+// @Description Get API keys including revoked ones, key values are never returned
+// @ID get-api-keys
+// @Tags admin
+// @Produce json
+// @Param X-Bootstrap-Token header string false "Bootstrap token while access control is disabled"
+// @Success 200 {object} APIKeysForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	401 {object} BadRequestForm "Authentication is required"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/keys [get]
+func (s *ServerHttp) GetAPIKeys(c echo.Context) error {
+	if err := s.checkKeysAdmin(c); err != nil {
+		return err
+	}
+
+	return c.JSON(200, &APIKeysForm{Keys: s.apiKeys.Keys()})
+}
+
+// CreateAPIKey
+// @Summary Create API key
+// @Description Create API key scoped to buckets and operations for service which calls API
+// @Description without user. Bucket "*" scopes key to all buckets, prefixes scope key to objects
+// @Description under these folders only. Key value is returned only once and must be passed
+// @Description by X-API-Key header. Keys are managed by global admin users, or by
+// @Description X-Bootstrap-Token header while access control is disabled.
+// @ID create-api-key
+// @Tags admin
+// @Accept  json
+// @Produce json
+// @Param jsonQuery body CreateAPIKeyForm true "Key name and scope"
+// @Param X-Bootstrap-Token header string false "Bootstrap token while access control is disabled"
+// @Success 200 {object} APIKeyCreatedForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	401 {object} BadRequestForm "Authentication is required"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/keys [post]
+func (s *ServerHttp) CreateAPIKey(c echo.Context) error {
+	if err := s.checkKeysAdmin(c); err != nil {
+		return err
+	}
+
+	jsonForm := &CreateAPIKeyForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if jsonForm.ExpiresAt != nil && !jsonForm.ExpiresAt.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "expiry time must be in the future")
+	}
+
+	key := &apikey.Key{
+		Name:       jsonForm.Name,
+		Buckets:    jsonForm.Buckets,
+		Prefixes:   jsonForm.Prefixes,
+		Operations: jsonForm.Operations,
+		ExpiresAt:  jsonForm.ExpiresAt,
+	}
+
+	created, keyValue, err := s.apiKeys.Create(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, &APIKeyCreatedForm{Key: created, Value: keyValue})
+}
+
+// RevokeAPIKey
+// @Summary Revoke API key
+// @Description Revoke API key by its id, revoked key is kept in list
+// @ID revoke-api-key
+// @Tags admin
+// @Produce json
+// @Param id path string true "Key id"
+// @Param X-Bootstrap-Token header string false "Bootstrap token while access control is disabled"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	401 {object} BadRequestForm "Authentication is required"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	404 {object} BadRequestForm "Key does not exist"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/keys/{id} [delete]
+func (s *ServerHttp) RevokeAPIKey(c echo.Context) error {
+	if err := s.checkKeysAdmin(c); err != nil {
+		return err
+	}
+
+	err := s.apiKeys.Revoke(c.Param("id"))
+	if errors.Is(err, apikey.ErrKeyNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
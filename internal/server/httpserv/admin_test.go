@@ -0,0 +1,150 @@
+package httpserv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+)
+
+// withAPIKeys enables API keys of server and returns headers of requests
+// made by each key. Keys are stored as recently used, so that
+// authentication does not save their usage in background.
+func withAPIKeys(t *testing.T, s *ServerHttp, keys ...*apikey.Key) []map[string]string {
+	t.Helper()
+
+	now := time.Now().UTC()
+	headers := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		value := "dhk_test" + key.ID
+		hash := sha256.Sum256([]byte(value))
+		key.Hash = hex.EncodeToString(hash[:])
+		key.CreatedAt = now
+		key.LastUsedAt = &now
+		headers = append(headers, map[string]string{headerAPIKey: value})
+	}
+
+	data, err := json.Marshal(map[string][]*apikey.Key{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeFile := filepath.Join(t.TempDir(), "keys.json")
+	if err = os.WriteFile(storeFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.apiKeys, err = apikey.New(&apikey.Config{Enabled: true, StoreFile: storeFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	return headers
+}
+
+func TestAdminRejectsAPIKeys(t *testing.T) {
+	s := newAccessTestServer(t, &rbac.Binding{Subject: "root", Bucket: rbac.AnyBucket, Role: rbac.RoleAdmin})
+	keyHeaders := withAPIKeys(t, s, &apikey.Key{
+		ID:         "admin",
+		Name:       "admin",
+		Buckets:    []string{apikey.AnyBucket},
+		Operations: []apikey.Operation{apikey.OperationRead, apikey.OperationWrite, apikey.OperationAdmin},
+	})
+	adminKey, root := keyHeaders[0], bearer(t, "root")
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "keys by user", method: http.MethodGet, target: "/admin/keys", headers: root, status: http.StatusOK},
+		{name: "keys by key", method: http.MethodGet, target: "/admin/keys", headers: adminKey, status: http.StatusForbidden},
+		{
+			name:    "key creation by key",
+			method:  http.MethodPost,
+			target:  "/admin/keys",
+			body:    `{"name":"wider","buckets":["*"],"operations":["admin"]}`,
+			headers: adminKey,
+			status:  http.StatusForbidden,
+		},
+		{name: "key revoke by key", method: http.MethodDelete, target: "/admin/keys/admin", headers: adminKey, status: http.StatusForbidden},
+		{name: "bindings by user", method: http.MethodGet, target: "/admin/policy/bindings", headers: root, status: http.StatusOK},
+		{name: "bindings by key", method: http.MethodGet, target: "/admin/policy/bindings", headers: adminKey, status: http.StatusForbidden},
+		{
+			name:    "binding creation by key",
+			method:  http.MethodPost,
+			target:  "/admin/policy/bindings",
+			body:    `{"subject":"mallory","bucket":"*","role":"admin"}`,
+			headers: adminKey,
+			status:  http.StatusForbidden,
+		},
+		{name: "policy reload by key", method: http.MethodPost, target: "/admin/policy/reload", headers: adminKey, status: http.StatusForbidden},
+		{name: "bucket admin by key", method: http.MethodGet, target: "/cloud/b1/lifecycle", headers: adminKey, status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, test.method, test.target, strings.NewReader(test.body), test.headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	if len(s.policy.Bindings()) != 1 {
+		t.Fatalf("binding is added by key: %+v", s.policy.Bindings())
+	}
+}
+
+func TestAPIKeyPrefixes(t *testing.T) {
+	s := newAccessTestServer(t)
+	keyHeaders := withAPIKeys(t, s, &apikey.Key{
+		ID:         "reports",
+		Name:       "reports",
+		Buckets:    []string{"b1"},
+		Prefixes:   []string{"reports/"},
+		Operations: []apikey.Operation{apikey.OperationRead},
+	})
+	reportsKey := keyHeaders[0]
+	s.uploadText(t, "reports/a.txt", "a")
+	s.uploadText(t, "reports-secret/b.txt", "b")
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{name: "file under prefix", target: "/cloud/b1/file/download", body: `{"file_name":"reports/a.txt"}`, status: http.StatusOK},
+		{name: "file of similar folder", target: "/cloud/b1/file/download", body: `{"file_name":"reports-secret/b.txt"}`, status: http.StatusForbidden},
+		{name: "listing of other folder", target: "/cloud/b1/files", body: `{"directory":"reports-secret/"}`, status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPost, test.target, strings.NewReader(test.body), reportsKey)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/files", strings.NewReader(`{"recursive":true}`), reportsKey)
+	filesPage := &cloud.FilesPage{}
+	if err := json.Unmarshal(rec.Body.Bytes(), filesPage); err != nil {
+		t.Fatalf("unexpected response %s: %v", rec.Body.String(), err)
+	}
+	if len(filesPage.Items) != 1 || filesPage.Items[0].FileName != "reports/a.txt" {
+		t.Fatalf("key sees items %+v", filesPage.Items)
+	}
+}
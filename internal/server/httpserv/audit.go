@@ -0,0 +1,190 @@
+package httpserv
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+const auditKey = "audit_event"
+
+// Audit records operation marked by handler with result of request,
+// operations on several objects are recorded by handlers themselves.
+// It runs ahead of authentication, so that rejected requests which never
+// reach handler are recorded as authentication failures.
+func (s *ServerHttp) Audit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := next(c)
+
+		event, ok := c.Get(auditKey).(*audit.Event)
+		if !ok {
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnauthorized {
+				return err
+			}
+			event = &audit.Event{
+				Operation: audit.OperationAuthFailure,
+				Bucket:    c.Param("bucket"),
+				Path:      c.Request().URL.Path,
+			}
+		}
+
+		if event.Operation == audit.OperationDownload && err == nil {
+			event.Size = c.Response().Size
+		}
+
+		status := c.Response().Status
+		var httpErr *echo.HTTPError
+		switch {
+		case errors.As(err, &httpErr):
+			status = httpErr.Code
+			event.Error = fmt.Sprint(httpErr.Message)
+		case err != nil:
+			status = http.StatusInternalServerError
+			event.Error = err.Error()
+		}
+
+		event.Status = status
+		event.Result = audit.ResultSuccess
+		if status >= http.StatusBadRequest || event.Error != "" {
+			event.Result = audit.ResultFailure
+		}
+
+		s.recordEvent(c, event)
+		return err
+	}
+}
+
+// auditOperation marks request as operation on object which is recorded
+// into audit log once handler is done, returned event is completed by
+// handler with details known to it only like size or destination. Handler
+// sets error of event if operation fails after response has been sent.
+func auditOperation(c echo.Context, operation, bucket, objPath string) *audit.Event {
+	event := &audit.Event{Operation: operation, Bucket: bucket, Path: objPath}
+	c.Set(auditKey, event)
+	return event
+}
+
+// recordAudit records operation on one of objects of request,
+// err is result of operation.
+func (s *ServerHttp) recordAudit(c echo.Context, event *audit.Event, err error) {
+	event.Result = audit.ResultSuccess
+	event.Status = http.StatusOK
+	if err != nil {
+		event.Result = audit.ResultFailure
+		event.Status = http.StatusBadRequest
+		event.Error = err.Error()
+	}
+
+	s.recordEvent(c, event)
+}
+
+func (s *ServerHttp) recordEvent(c echo.Context, event *audit.Event) {
+	event.Time = time.Now().UTC()
+	event.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	event.Actor = requestActor(c)
+	event.ClientIP = c.RealIP()
+
+	if err := s.audit.Record(event); err != nil {
+		log.Println("failed to record audit event: ", event.Operation, event.Bucket, event.Path, err)
+	}
+}
+
+// GetAuditEvents
+// @Summary Get audit events
+// @Description Get audit events newest first, filtered by time range, actor, bucket
+// @Description and operation. Next page is requested by next cursor of previous page.
+// @Description Admin role on all buckets is required.
+// @ID get-audit-events
+// @Tags admin
+// @Produce json
+// @Param from query string false "Events recorded at or after time, RFC3339"
+// @Param to query string false "Events recorded before time, RFC3339"
+// @Param actor query string false "Subject or client IP of anonymous caller"
+// @Param bucket query string false "Bucket which is source or destination of operation"
+// @Param operation query string false "Operation, like file.download"
+// @Param limit query int false "Max number of events, 100 by default and 1000 at most"
+// @Param cursor query string false "Next cursor of previous page"
+// @Success 200 {object} audit.EventsPage "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/audit [get]
+func (s *ServerHttp) GetAuditEvents(c echo.Context) error {
+	if err := s.checkAuditAdmin(c); err != nil {
+		return err
+	}
+
+	filter := &audit.Filter{
+		Actor:     c.QueryParam("actor"),
+		Bucket:    c.QueryParam("bucket"),
+		Operation: c.QueryParam("operation"),
+		Cursor:    c.QueryParam("cursor"),
+	}
+
+	var err error
+	if fromParam := c.QueryParam("from"); fromParam != "" {
+		if filter.From, err = time.Parse(time.RFC3339, fromParam); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	if toParam := c.QueryParam("to"); toParam != "" {
+		if filter.To, err = time.Parse(time.RFC3339, toParam); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.QueryParam("limit"))
+
+	page, err := s.audit.Query(filter)
+	if errors.Is(err, audit.ErrInvalidCursor) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(200, page)
+}
+
+// VerifyAuditLog
+// @Summary Verify audit log
+// @Description Check hash chain of audit log, chain is broken when any event has been
+// @Description changed or removed. Admin role on all buckets is required.
+// @ID verify-audit-log
+// @Tags admin
+// @Produce json
+// @Success 200 {object} AuditVerifyForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /admin/audit/verify [get]
+func (s *ServerHttp) VerifyAuditLog(c echo.Context) error {
+	if err := s.checkAuditAdmin(c); err != nil {
+		return err
+	}
+
+	checked, err := s.audit.Verify()
+	response := &AuditVerifyForm{IsValid: err == nil, Checked: checked}
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	return c.JSON(200, response)
+}
+
+// checkAuditAdmin checks that audit is on and caller is global admin.
+func (s *ServerHttp) checkAuditAdmin(c echo.Context) error {
+	if !s.audit.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errAuditDisabled.Error())
+	}
+
+	return s.authorize(c, rbac.AnyBucket, "", rbac.RoleAdmin)
+}
@@ -0,0 +1,95 @@
+package httpserv
+
+import (
+	"net/http"
+	"strings"
+
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/auth"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	identityKey = "identity"
+	apiKeyKey   = "api_key"
+
+	headerAPIKey = "X-API-Key"
+)
+
+// Authenticate checks API key or bearer token of request to every route
+// which is not public and keeps identity of caller for handlers. API key
+// is accepted even when bearer tokens are not required.
+func (s *ServerHttp) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if s.auth.IsPublicRoute(c.Path()) {
+			return next(c)
+		}
+
+		if keyValue := c.Request().Header.Get(headerAPIKey); keyValue != "" && s.apiKeys.IsEnabled() {
+			key, err := s.apiKeys.Authenticate(keyValue)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+
+			c.Set(identityKey, key.Identity())
+			c.Set(apiKeyKey, key)
+			return next(c)
+		}
+
+		if !s.auth.IsEnabled() {
+			return next(c)
+		}
+
+		authHeader := c.Request().Header.Get(echo.HeaderAuthorization)
+		token, found := strings.CutPrefix(authHeader, "Bearer ")
+		if !found || token == "" {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+			return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token")
+		}
+
+		identity, err := s.auth.Authenticate(token)
+		if err != nil {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+
+		c.Set(identityKey, identity)
+		return next(c)
+	}
+}
+
+// requestIdentity returns authenticated caller of request,
+// nil if authentication is disabled or route is public.
+func requestIdentity(c echo.Context) *auth.Identity {
+	identity, _ := c.Get(identityKey).(*auth.Identity)
+	return identity
+}
+
+// requestAPIKey returns API key which request is made with, nil
+// if caller is authenticated otherwise.
+func requestAPIKey(c echo.Context) *apikey.Key {
+	key, _ := c.Get(apiKeyKey).(*apikey.Key)
+	return key
+}
+
+// requestActor returns name of client made request to record it
+// into operations history, like who has deleted a file.
+func requestActor(c echo.Context) string {
+	if identity := requestIdentity(c); identity != nil {
+		return identity.Subject
+	}
+
+	return c.RealIP()
+}
+
+// Health
+// @Summary Check service health
+// @Description Check that service is up, the route does not require authentication
+// @ID health
+// @Tags health
+// @Produce json
+// @Success 200 {object} ResponseForm "Ok"
+// @Router /health [get]
+func (s *ServerHttp) Health(c echo.Context) error {
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
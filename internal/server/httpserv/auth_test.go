@@ -0,0 +1,72 @@
+package httpserv
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestAuthenticate(t *testing.T) {
+	s := newAccessTestServer(t)
+
+	tests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+		status  int
+	}{
+		{name: "missing token", target: "/cloud/buckets", status: http.StatusUnauthorized},
+		{
+			name:    "malformed token",
+			target:  "/cloud/buckets",
+			headers: map[string]string{echo.HeaderAuthorization: "Bearer token"},
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name:    "other scheme",
+			target:  "/cloud/buckets",
+			headers: map[string]string{echo.HeaderAuthorization: "Basic YTpi"},
+			status:  http.StatusUnauthorized,
+		},
+		{name: "valid token", target: "/cloud/buckets", headers: bearer(t, "alice"), status: http.StatusOK},
+		{name: "public route", target: "/health", status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodGet, test.target, nil, test.headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get(echo.HeaderWWWAuthenticate) == "" {
+				t.Fatal("challenge header is not set")
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	s := newTestServer(t)
+	origin := map[string]string{echo.HeaderOrigin: "https://app.example.com"}
+
+	rec := s.serve(t, http.MethodGet, "/health", nil, origin)
+	if allowed := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); allowed != "" {
+		t.Fatalf("origin %q is allowed without configured origins", allowed)
+	}
+
+	s.config.AllowOrigins = []string{"https://app.example.com"}
+	if err := s.setupServer(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = s.serve(t, http.MethodGet, "/health", nil, origin)
+	if allowed := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); allowed != "https://app.example.com" {
+		t.Fatalf("configured origin is not allowed: %q", allowed)
+	}
+
+	rec = s.serve(t, http.MethodGet, "/health", nil, map[string]string{echo.HeaderOrigin: "https://other.example.com"})
+	if allowed := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); allowed != "" {
+		t.Fatalf("origin %q is allowed", allowed)
+	}
+}
@@ -0,0 +1,169 @@
+package httpserv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+const bulkWorkersCount = 8
+
+// runBounded calls handler for every index from 0 to count
+// by pool of workers limited by bulkWorkersCount.
+func runBounded(count int, handler func(index int)) {
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+
+	workersCount := min(count, bulkWorkersCount)
+	for range workersCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				handler(index)
+			}
+		}()
+	}
+
+	for index := range count {
+		indexes <- index
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+// relativePath returns document path relative to source directory,
+// documents may be passed with or without source directory prefix.
+func relativePath(srcDir, docPath string) string {
+	docPath = cloud.JoinFilePath("", docPath)
+	srcDir = cloud.JoinFilePath("", srcDir)
+	if srcDir == "" {
+		return docPath
+	}
+
+	return strings.TrimPrefix(docPath, srcDir+"/")
+}
+
+// MoveFiles
+// @Summary Move many documents to another folder
+// @Description Move documents from source folder into target folder keeping their relative paths,
+// @Description optionally into another bucket, and report result of every document
+// @ID move-files
+// @Tags files
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of source documents"
+// @Param jsonQuery body MoveFilesForm true "Params to move documents"
+// @Success 200 {object} MoveFilesResponseForm "Ok"
+// @Success 207 {object} MoveFilesResponseForm "Some documents have not been moved"
+// @Failure	400 {object} MoveFilesResponseForm "No document has been moved"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/files/move [post]
+func (s *ServerHttp) MoveFiles(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &MoveFilesForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if len(jsonForm.DocumentPaths) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "there are no documents to move")
+	}
+
+	if err := cloud.CheckConflictPolicy(jsonForm.OnConflict); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	dstBucket := jsonForm.TargetBucket
+	if dstBucket == "" {
+		dstBucket = bucket
+	}
+
+	// Existence of bucket is reported only to callers which may write into it.
+	if err := s.authorize(c, dstBucket, cloud.FolderKey(jsonForm.TargetDirectory), rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	if exist, err := s.cloud.Cloud.IsBucketExist(ctx, dstBucket); err != nil || !exist {
+		retErr := fmt.Errorf("specified bucket %s does not exist", dstBucket)
+		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
+	}
+
+	// Document passed twice would be moved by two workers at once,
+	// so it is moved and reported once.
+	results := make([]*MoveFileResult, 0, len(jsonForm.DocumentPaths))
+	srcPaths := make(map[string]bool, len(jsonForm.DocumentPaths))
+	for _, docPath := range jsonForm.DocumentPaths {
+		relPath := relativePath(jsonForm.SourceDirectory, docPath)
+		srcPath := cloud.JoinFilePath(jsonForm.SourceDirectory, relPath)
+		if srcPaths[srcPath] {
+			continue
+		}
+		srcPaths[srcPath] = true
+
+		results = append(results, &MoveFileResult{
+			DocumentPath: docPath,
+			SrcPath:      srcPath,
+			DstPath:      cloud.JoinFilePath(jsonForm.TargetDirectory, relPath),
+		})
+	}
+
+	runBounded(len(results), func(index int) {
+		result := results[index]
+		err := s.checkAccess(c, bucket, result.SrcPath, rbac.RoleWriter)
+		if err == nil {
+			err = s.checkAccess(c, dstBucket, result.DstPath, rbac.RoleWriter)
+		}
+		if err == nil {
+			err = s.moveDocument(ctx, bucket, dstBucket, result, jsonForm.OnConflict)
+		}
+		switch {
+		case errors.Is(err, cloud.ErrFileSkipped):
+			result.Status = MoveStatusSkipped
+			result.Error = err.Error()
+		case err != nil:
+			result.Status = MoveStatusFailed
+			result.Error = err.Error()
+		default:
+			result.Status = MoveStatusMoved
+		}
+
+		if result.Status != MoveStatusSkipped {
+			event := &audit.Event{
+				Operation: audit.OperationMove,
+				Bucket:    bucket,
+				Path:      result.SrcPath,
+				DstBucket: dstBucket,
+				DstPath:   result.DstPath,
+			}
+			s.recordAudit(c, event, err)
+		}
+	})
+
+	response := createMoveFilesResponse(results)
+	return c.JSON(response.Status, response)
+}
+
+func (s *ServerHttp) moveDocument(ctx context.Context, srcBucket, dstBucket string, result *MoveFileResult, onConflict string) error {
+	dstPath, err := cloud.MoveByPolicy(ctx, s.cloud.Cloud, srcBucket, result.SrcPath, dstBucket, result.DstPath, onConflict)
+	if err != nil {
+		return err
+	}
+
+	result.DstPath = dstPath
+	return nil
+}
@@ -0,0 +1,114 @@
+package httpserv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-hub/internal/rbac"
+)
+
+func moveFiles(t *testing.T, s *ServerHttp, body string, headers map[string]string) (int, *MoveFilesResponseForm) {
+	t.Helper()
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/files/move", strings.NewReader(body), headers)
+	response := &MoveFilesResponseForm{}
+	if rec.Code == http.StatusOK || rec.Code == http.StatusMultiStatus {
+		if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rec.Code, response
+}
+
+func TestMoveFiles(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "inbox/a.txt", "a")
+	s.uploadText(t, "inbox/2024/b.txt", "b")
+	s.uploadText(t, "inbox/c.txt", "new c")
+	s.uploadText(t, "archive/c.txt", "old c")
+
+	body := `{
+		"src_folder_id": "inbox",
+		"location": "archive",
+		"document_ids": ["a.txt", "inbox/2024/b.txt", "./a.txt", "c.txt", "missing.txt"]
+	}`
+	status, response := moveFiles(t, s, body, nil)
+	if status != http.StatusMultiStatus {
+		t.Fatalf("status %d", status)
+	}
+
+	results := make([]string, 0, len(response.Files))
+	for _, result := range response.Files {
+		results = append(results, result.DstPath+":"+result.Status)
+	}
+	expected := "archive/a.txt:moved,archive/2024/b.txt:moved,archive/c.txt:failed,archive/missing.txt:failed"
+	if strings.Join(results, ",") != expected {
+		t.Fatalf("results %v", results)
+	}
+	if response.Moved != 2 || response.Failed != 2 {
+		t.Fatalf("unexpected counts %+v", response)
+	}
+
+	if content := s.readText(t, "archive/2024/b.txt"); content != "b" {
+		t.Fatalf("moved file has content %q", content)
+	}
+	if content := s.readText(t, "archive/c.txt"); content != "old c" {
+		t.Fatalf("existing file has been replaced by default: %q", content)
+	}
+	if _, err := s.cloud.Cloud.StatFile(context.Background(), "b1", "inbox/a.txt"); err == nil {
+		t.Fatal("moved file still exists")
+	}
+
+	status, response = moveFiles(t, s, `{"src_folder_id":"inbox","location":"archive","document_ids":["c.txt"],"on_conflict":"rename"}`, nil)
+	if status != http.StatusOK || response.Files[0].DstPath != "archive/c (1).txt" {
+		t.Fatalf("status %d, results %+v", status, response.Files)
+	}
+}
+
+func TestMoveFilesAccess(t *testing.T) {
+	s := newAccessTestServer(t,
+		&rbac.Binding{Subject: "alice", Bucket: "b1", Prefix: "inbox/", Role: rbac.RoleWriter},
+		&rbac.Binding{Subject: "alice", Bucket: "b1", Prefix: "archive/", Role: rbac.RoleWriter},
+	)
+	s.uploadText(t, "inbox/a.txt", "a")
+	alice := bearer(t, "alice")
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{
+			name:   "missing target bucket",
+			body:   `{"src_folder_id":"inbox","location":"archive","document_ids":["a.txt"],"target_bucket":"b9"}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "denied target folder",
+			body:   `{"src_folder_id":"inbox","location":"private","document_ids":["a.txt"]}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "denied source file",
+			body:   `{"src_folder_id":"private","location":"archive","document_ids":["a.txt"]}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "allowed folders",
+			body:   `{"src_folder_id":"inbox","location":"archive","document_ids":["a.txt"]}`,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if status, _ := moveFiles(t, s, test.body, alice); status != test.status {
+				t.Fatalf("status %d, expected %d", status, test.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/trash"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	folderRemoveBatchSize = 1000
+	folderProgressStep    = 100
+)
+
+// folderReport collects results of every object of folder operation.
+// When client asks for progress, response is streamed as JSON lines
+// with progress records followed by final report.
+type folderReport struct {
+	mu       sync.Mutex
+	response *FolderResponseForm
+	stream   *echo.Response
+}
+
+func newFolderReport(c echo.Context, total int) (*folderReport, error) {
+	report := &folderReport{
+		response: &FolderResponseForm{
+			Total:  total,
+			Errors: make([]*FolderObjectError, 0),
+		},
+	}
+
+	if progress := c.QueryParam("progress"); progress != "" {
+		isProgress, err := strconv.ParseBool(progress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid progress flag: %w", err)
+		}
+
+		if isProgress {
+			report.stream = c.Response()
+			report.stream.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			report.stream.WriteHeader(http.StatusOK)
+		}
+	}
+
+	return report, nil
+}
+
+func (fr *folderReport) add(filePath string, err error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	response := fr.response
+	response.Processed++
+	switch {
+	case errors.Is(err, cloud.ErrFileSkipped):
+		response.Skipped++
+	case err != nil:
+		response.Failed++
+		response.Errors = append(response.Errors, &FolderObjectError{
+			FilePath: filePath,
+			Error:    err.Error(),
+		})
+	default:
+		response.Succeeded++
+	}
+
+	if fr.stream != nil && response.Processed%folderProgressStep == 0 {
+		fr.write(&FolderProgressForm{
+			Total:     response.Total,
+			Processed: response.Processed,
+			Failed:    response.Failed,
+		})
+	}
+}
+
+func (fr *folderReport) write(record any) {
+	if err := json.NewEncoder(fr.stream).Encode(record); err != nil {
+		return
+	}
+
+	fr.stream.Flush()
+}
+
+func (fr *folderReport) finish(c echo.Context) error {
+	response := fr.response
+	switch {
+	case response.Failed == 0:
+		response.Status = http.StatusOK
+	case response.Succeeded == 0:
+		response.Status = http.StatusBadRequest
+	default:
+		response.Status = http.StatusMultiStatus
+	}
+
+	if fr.stream != nil {
+		fr.write(response)
+		return nil
+	}
+
+	return c.JSON(response.Status, response)
+}
+
+// CreateFolder
+// @Summary Create empty folder
+// @Description Create empty folder which is kept by placeholder object until it is removed
+// @ID create-folder
+// @Tags folders
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name to create folder"
+// @Param jsonQuery body CreateFolderForm true "Params to create folder"
+// @Success 200 {object} FileResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/folder [put]
+func (s *ServerHttp) CreateFolder(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &CreateFolderForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationCreateFolder, bucket, cloud.FolderKey(jsonForm.FolderPath))
+	if err := s.authorize(c, bucket, cloud.FolderKey(jsonForm.FolderPath), rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := s.cloud.Cloud.CreateFolder(ctx, bucket, jsonForm.FolderPath); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createFileResponse(200, "Ok", cloud.FolderKey(jsonForm.FolderPath)))
+}
+
+// CopyFolder
+// @Summary Copy folder with all its content
+// @Description Copy folder tree to another location into bucket or another bucket
+// @Description and report every object which has not been copied
+// @ID copy-folder
+// @Tags folders
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of source folder"
+// @Param progress query bool false "Stream progress records as JSON lines before final report"
+// @Param jsonQuery body CopyFolderForm true "Params to copy folder"
+// @Success 200 {object} FolderResponseForm "Ok"
+// @Success 207 {object} FolderResponseForm "Some objects have not been copied"
+// @Failure	400 {object} FolderResponseForm "No object has been copied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/folder/copy [post]
+func (s *ServerHttp) CopyFolder(c echo.Context) error {
+	return s.transferFolder(c, false)
+}
+
+// MoveFolder
+// @Summary Move or rename folder with all its content
+// @Description Move folder tree to another location into bucket or another bucket
+// @Description and report every object which has not been moved
+// @ID move-folder
+// @Tags folders
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of source folder"
+// @Param progress query bool false "Stream progress records as JSON lines before final report"
+// @Param jsonQuery body CopyFolderForm true "Params to move folder"
+// @Success 200 {object} FolderResponseForm "Ok"
+// @Success 207 {object} FolderResponseForm "Some objects have not been moved"
+// @Failure	400 {object} FolderResponseForm "No object has been moved"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/folder/move [post]
+func (s *ServerHttp) MoveFolder(c echo.Context) error {
+	return s.transferFolder(c, true)
+}
+
+func (s *ServerHttp) transferFolder(c echo.Context, isMove bool) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &CopyFolderForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := cloud.CheckConflictPolicy(jsonForm.OnConflict); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	srcKey := cloud.FolderKey(jsonForm.SrcFolder)
+	if srcKey == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "source folder is not specified")
+	}
+
+	ctx := c.Request().Context()
+	dstBucket := jsonForm.DstBucket
+	if dstBucket == "" {
+		dstBucket = bucket
+	}
+
+	operation, srcRole := audit.OperationCopyFolder, rbac.RoleReader
+	if isMove {
+		operation, srcRole = audit.OperationMoveFolder, rbac.RoleWriter
+	}
+
+	dstKey := cloud.FolderKey(jsonForm.DstFolder)
+	event := auditOperation(c, operation, bucket, srcKey)
+	event.DstBucket = dstBucket
+	event.DstPath = dstKey
+
+	if err := s.authorize(c, bucket, srcKey, srcRole); err != nil {
+		return err
+	}
+
+	if err := s.authorize(c, dstBucket, dstKey, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	if exist, err := s.cloud.Cloud.IsBucketExist(ctx, dstBucket); err != nil || !exist {
+		retErr := fmt.Errorf("specified bucket %s does not exist", dstBucket)
+		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
+	}
+
+	if dstBucket == bucket && strings.HasPrefix(dstKey, srcKey) {
+		return echo.NewHTTPError(http.StatusBadRequest, "folder can not be placed into itself")
+	}
+
+	doc := s.cloud.Cloud
+	items, err := cloud.ListFolder(ctx, doc, bucket, srcKey)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if len(items) == 0 {
+		retErr := fmt.Errorf("folder %s does not exist", srcKey)
+		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
+	}
+
+	report, err := newFolderReport(c, len(items))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	transfer := cloud.CopyByPolicy
+	if isMove {
+		transfer = cloud.MoveByPolicy
+	}
+
+	filePaths := make([]string, 0, len(items))
+	folderKeys := make([]string, 0)
+	for _, item := range items {
+		if cloud.IsFolderKey(item.FileName) {
+			folderKeys = append(folderKeys, item.FileName)
+			continue
+		}
+		filePaths = append(filePaths, item.FileName)
+	}
+
+	var keptMu sync.Mutex
+	keptPaths := make([]string, 0)
+	runBounded(len(filePaths), func(index int) {
+		srcPath := filePaths[index]
+		dstPath := cloud.JoinFilePath(dstKey, strings.TrimPrefix(srcPath, srcKey))
+		_, err := transfer(ctx, doc, bucket, srcPath, dstBucket, dstPath, jsonForm.OnConflict)
+		if err != nil {
+			keptMu.Lock()
+			keptPaths = append(keptPaths, srcPath)
+			keptMu.Unlock()
+		}
+		report.add(srcPath, err)
+	})
+
+	// Placeholders are handled after files, so source folders
+	// are removed on move only when they have been emptied.
+	folderErrs := make(map[string]error)
+	for _, folderKey := range folderKeys {
+		dstFolder := cloud.JoinFilePath(dstKey, strings.TrimPrefix(folderKey, srcKey))
+		if dstFolder != "" {
+			folderErrs[folderKey] = doc.CreateFolder(ctx, dstBucket, dstFolder)
+		}
+	}
+
+	if isMove {
+		removeKeys := make([]string, 0, len(folderKeys))
+		for _, folderKey := range folderKeys {
+			if folderErrs[folderKey] == nil && !hasPathUnder(keptPaths, folderKey) {
+				removeKeys = append(removeKeys, folderKey)
+			}
+		}
+
+		for folderKey, err := range doc.RemoveFiles(ctx, bucket, removeKeys) {
+			folderErrs[folderKey] = err
+		}
+	}
+
+	for _, folderKey := range folderKeys {
+		report.add(folderKey, folderErrs[folderKey])
+	}
+
+	return report.finish(c)
+}
+
+// hasPathUnder reports whether any of file paths is under folder key,
+// files which have been skipped or failed on move keep their folder.
+func hasPathUnder(filePaths []string, folderKey string) bool {
+	for _, filePath := range filePaths {
+		if strings.HasPrefix(filePath, folderKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveFolder
+// @Summary Remove folder with all its content
+// @Description Remove folder tree by batches of objects and report every object which has not been removed.
+// @Description Files are moved into trash of bucket when trash is enabled, unless removed permanently.
+// @ID remove-folder
+// @Tags folders
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name to remove folder"
+// @Param progress query bool false "Stream progress records as JSON lines before final report"
+// @Param permanent query bool false "Remove files permanently bypassing trash, admin role is required"
+// @Param jsonQuery body RemoveFolderForm true "Params to remove folder"
+// @Success 200 {object} FolderResponseForm "Ok"
+// @Success 207 {object} FolderResponseForm "Some objects have not been removed"
+// @Failure	400 {object} FolderResponseForm "No object has been removed"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/folder/remove [delete]
+func (s *ServerHttp) RemoveFolder(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &RemoveFolderForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	folderKey := cloud.FolderKey(jsonForm.FolderPath)
+	if folderKey == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "folder is not specified")
+	}
+
+	isPermanent, _ := strconv.ParseBool(c.QueryParam("permanent"))
+	auditOperation(c, audit.OperationRemoveFolder, bucket, folderKey)
+	if err := s.authorize(c, bucket, folderKey, removeRole(folderKey, isPermanent)); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	doc := s.cloud.Cloud
+	items, err := cloud.ListFolder(ctx, doc, bucket, folderKey)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if len(items) == 0 {
+		retErr := fmt.Errorf("folder %s does not exist", folderKey)
+		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
+	}
+
+	report, err := newFolderReport(c, len(items))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Files go through trash like single removed files do,
+	// folder placeholders have no content and are removed at once.
+	toTrash := s.trash.IsEnabled() && !isPermanent && !trash.IsTrashPath(folderKey)
+	actor := requestActor(c)
+
+	for start := 0; start < len(items); start += folderRemoveBatchSize {
+		batch := items[start:min(start+folderRemoveBatchSize, len(items))]
+		filePaths := make([]string, 0, len(batch))
+		for _, item := range batch {
+			if toTrash && !cloud.IsFolderKey(item.FileName) {
+				_, err := s.trash.MoveToTrash(ctx, bucket, item.FileName, actor)
+				report.add(item.FileName, err)
+				continue
+			}
+			filePaths = append(filePaths, item.FileName)
+		}
+
+		removeErrs := doc.RemoveFiles(ctx, bucket, filePaths)
+		for _, filePath := range filePaths {
+			report.add(filePath, removeErrs[filePath])
+		}
+	}
+
+	return report.finish(c)
+}
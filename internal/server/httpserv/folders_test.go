@@ -0,0 +1,159 @@
+package httpserv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-hub/internal/cloud"
+)
+
+func folderRequest(t *testing.T, s *ServerHttp, method, target, body string) (int, *FolderResponseForm) {
+	t.Helper()
+
+	rec := s.serve(t, method, target, strings.NewReader(body), nil)
+	response := &FolderResponseForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+		t.Fatalf("unexpected response %s: %v", rec.Body.String(), err)
+	}
+
+	return rec.Code, response
+}
+
+func listFolder(t *testing.T, s *ServerHttp, folderPath string) string {
+	t.Helper()
+
+	items, err := cloud.ListFolder(context.Background(), s.cloud.Cloud, "b1", folderPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.FileName)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestCreateFolder(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.serve(t, http.MethodPut, "/cloud/b1/folder", strings.NewReader(`{"folder_path":"/reports/2024"}`), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to create folder: %s", rec.Body.String())
+	}
+	if names := listFolder(t, s, "reports"); names != "reports/2024/" {
+		t.Fatalf("folder contains %q", names)
+	}
+
+	rec = s.serve(t, http.MethodPut, "/cloud/b1/folder", strings.NewReader(`{"folder_path":"/"}`), nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("bucket root: status %d", rec.Code)
+	}
+}
+
+func TestCopyFolder(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "reports/a.txt", "a")
+	s.uploadText(t, "reports/2024/b.txt", "b")
+	if err := s.cloud.Cloud.CreateFolder(context.Background(), "b1", "reports/empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, response := folderRequest(t, s, http.MethodPost, "/cloud/b1/folder/copy", `{"src_folder":"reports","dst_folder":"archive/reports"}`)
+	if status != http.StatusOK || response.Total != 3 || response.Succeeded != 3 {
+		t.Fatalf("status %d, response %+v", status, response)
+	}
+	if names := listFolder(t, s, "archive"); names != "archive/reports/2024/b.txt,archive/reports/a.txt,archive/reports/empty/" {
+		t.Fatalf("copied folder contains %q", names)
+	}
+	if names := listFolder(t, s, "reports"); names != "reports/2024/b.txt,reports/a.txt,reports/empty/" {
+		t.Fatalf("source folder contains %q", names)
+	}
+
+	for _, body := range []string{
+		`{"src_folder":"reports","dst_folder":"reports/copy"}`,
+		`{"src_folder":"missing","dst_folder":"copy"}`,
+		`{"src_folder":"","dst_folder":"copy"}`,
+	} {
+		if rec := s.serve(t, http.MethodPost, "/cloud/b1/folder/copy", strings.NewReader(body), nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("copy %s: status %d", body, rec.Code)
+		}
+	}
+}
+
+func TestMoveFolderKeepsSkippedFiles(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "inbox/a.txt", "new a")
+	s.uploadText(t, "inbox/keep/b.txt", "new b")
+	s.uploadText(t, "inbox/done/c.txt", "c")
+	s.uploadText(t, "archive/keep/b.txt", "old b")
+	for _, folderPath := range []string{"inbox", "inbox/keep", "inbox/done"} {
+		if err := s.cloud.Cloud.CreateFolder(context.Background(), "b1", folderPath); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body := `{"src_folder":"inbox","dst_folder":"archive","on_conflict":"skip"}`
+	status, response := folderRequest(t, s, http.MethodPost, "/cloud/b1/folder/move", body)
+	if status != http.StatusOK || response.Skipped != 1 || response.Failed != 0 {
+		t.Fatalf("status %d, response %+v", status, response)
+	}
+
+	// Placeholders are kept for folders where skipped file is left.
+	if names := listFolder(t, s, "inbox"); names != "inbox/,inbox/keep/,inbox/keep/b.txt" {
+		t.Fatalf("source folder contains %q", names)
+	}
+	if content := s.readText(t, "archive/keep/b.txt"); content != "old b" {
+		t.Fatalf("skipped file has been replaced: %q", content)
+	}
+	if content := s.readText(t, "archive/a.txt"); content != "new a" {
+		t.Fatalf("moved file has content %q", content)
+	}
+}
+
+func TestRemoveFolder(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "reports/a.txt", "a")
+	s.uploadText(t, "reports/2024/b.txt", "b")
+	if err := s.cloud.Cloud.CreateFolder(context.Background(), "b1", "reports/2024"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := s.serve(t, http.MethodDelete, "/cloud/b1/folder/remove?progress=true", strings.NewReader(`{"folder_path":"reports"}`), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to remove folder: %s", rec.Body.String())
+	}
+
+	// Final report is the last JSON line of progress stream.
+	var lastLine string
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		lastLine = scanner.Text()
+	}
+	response := &FolderResponseForm{}
+	if err := json.Unmarshal([]byte(lastLine), response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != http.StatusOK || response.Total != 3 || response.Succeeded != 3 {
+		t.Fatalf("unexpected report %+v", response)
+	}
+
+	if names := listFolder(t, s, "reports"); names != "" {
+		t.Fatalf("removed folder contains %q", names)
+	}
+
+	page, err := s.trash.List(context.Background(), "b1", 10, "", nil)
+	if err != nil || len(page.Items) != 2 {
+		t.Fatalf("removed files are not kept into trash: %+v, error %v", page, err)
+	}
+
+	rec = s.serve(t, http.MethodDelete, "/cloud/b1/folder/remove", strings.NewReader(`{"folder_path":"reports"}`), nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("removed folder: status %d", rec.Code)
+	}
+}
@@ -1,15 +1,86 @@
 package httpserv
 
+import (
+	"net/http"
+	"time"
+
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/share"
+)
+
 func createStatusResponse(status int, msg string) *ResponseForm {
 	return &ResponseForm{Status: status, Message: msg}
 }
 
+func createFileResponse(status int, msg, filePath string) *FileResponseForm {
+	return &FileResponseForm{Status: status, Message: msg, FilePath: filePath}
+}
+
+func createUploadFilesResponse(results []*UploadFileResult) *UploadFilesForm {
+	response := &UploadFilesForm{Files: results}
+	for _, result := range results {
+		switch result.Status {
+		case UploadStatusUploaded:
+			response.Uploaded++
+		case UploadStatusSkipped:
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+	}
+
+	switch {
+	case response.Failed == 0:
+		response.Status = http.StatusOK
+	case response.Uploaded == 0:
+		response.Status = http.StatusBadRequest
+	default:
+		response.Status = http.StatusMultiStatus
+	}
+
+	return response
+}
+
+func createMoveFilesResponse(results []*MoveFileResult) *MoveFilesResponseForm {
+	response := &MoveFilesResponseForm{Files: results}
+	for _, result := range results {
+		switch result.Status {
+		case MoveStatusMoved:
+			response.Moved++
+		case MoveStatusSkipped:
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+	}
+
+	switch {
+	case response.Failed == 0:
+		response.Status = http.StatusOK
+	case response.Moved == 0:
+		response.Status = http.StatusBadRequest
+	default:
+		response.Status = http.StatusMultiStatus
+	}
+
+	return response
+}
+
 // ResponseForm example
 type ResponseForm struct {
 	Status  int    `json:"status" example:"200"`
 	Message string `json:"message" example:"Done"`
 }
 
+// FileResponseForm example
+type FileResponseForm struct {
+	Status   int    `json:"status" example:"200"`
+	Message  string `json:"message" example:"Ok"`
+	FilePath string `json:"file_path" example:"test-document (1).docx"`
+}
+
 // BadRequestForm example
 type BadRequestForm struct {
 	Status  int    `json:"status" example:"400"`
@@ -25,6 +96,17 @@ type ServerErrorForm struct {
 // CreateBucketForm example
 type CreateBucketForm struct {
 	BucketName string `json:"bucket_name" example:"test-bucket"`
+	Versioning string `json:"versioning,omitempty" example:"Enabled" enums:"Enabled,Suspended"`
+}
+
+// BucketVersioningForm example
+type BucketVersioningForm struct {
+	Status string `json:"status" example:"Enabled" enums:"Enabled,Suspended"`
+}
+
+// BucketLifecycleForm example
+type BucketLifecycleForm struct {
+	Rules []*cloud.LifecycleRule `json:"rules"`
 }
 
 // MoveFilesForm example
@@ -32,6 +114,79 @@ type MoveFilesForm struct {
 	TargetDirectory string   `json:"location" example:"common-folder"`
 	SourceDirectory string   `json:"src_folder_id" example:"unrecognized"`
 	DocumentPaths   []string `json:"document_ids" example:"./indexer/watcher/test.txt"`
+	TargetBucket    string   `json:"target_bucket" example:"archive"`
+	OnConflict      string   `json:"on_conflict" example:"rename" enums:"overwrite,skip,rename,fail" default:"fail"`
+}
+
+const (
+	MoveStatusMoved   = "moved"
+	MoveStatusSkipped = "skipped"
+	MoveStatusFailed  = "failed"
+)
+
+// MoveFilesResponseForm example
+type MoveFilesResponseForm struct {
+	Status  int               `json:"status" example:"207"`
+	Moved   int               `json:"moved" example:"1"`
+	Skipped int               `json:"skipped" example:"0"`
+	Failed  int               `json:"failed" example:"1"`
+	Files   []*MoveFileResult `json:"files"`
+}
+
+// MoveFileResult example
+type MoveFileResult struct {
+	DocumentPath string `json:"document_id" example:"./indexer/watcher/test.txt"`
+	SrcPath      string `json:"src_path" example:"unrecognized/indexer/watcher/test.txt"`
+	DstPath      string `json:"dst_path" example:"common-folder/indexer/watcher/test.txt"`
+	Status       string `json:"status" example:"moved" enums:"moved,skipped,failed"`
+	Error        string `json:"error,omitempty" example:"file already exists"`
+}
+
+// CreateFolderForm example
+type CreateFolderForm struct {
+	FolderPath string `json:"folder_path" example:"reports/2024"`
+}
+
+// CopyFolderForm example
+type CopyFolderForm struct {
+	SrcFolder  string `json:"src_folder" example:"reports/2024"`
+	DstFolder  string `json:"dst_folder" example:"reports/archive/2024"`
+	DstBucket  string `json:"dst_bucket,omitempty" example:"archive"`
+	OnConflict string `json:"on_conflict" example:"rename" enums:"overwrite,skip,rename,fail" default:"fail"`
+}
+
+// RemoveFolderForm example
+type RemoveFolderForm struct {
+	FolderPath string `json:"folder_path" example:"reports/2024"`
+}
+
+// FolderResponseForm example
+type FolderResponseForm struct {
+	Status    int                  `json:"status" example:"207"`
+	Total     int                  `json:"total" example:"3"`
+	Processed int                  `json:"processed" example:"3"`
+	Succeeded int                  `json:"succeeded" example:"1"`
+	Skipped   int                  `json:"skipped" example:"1"`
+	Failed    int                  `json:"failed" example:"1"`
+	Errors    []*FolderObjectError `json:"errors"`
+}
+
+// FolderProgressForm example
+type FolderProgressForm struct {
+	Total     int `json:"total" example:"3"`
+	Processed int `json:"processed" example:"2"`
+	Failed    int `json:"failed" example:"0"`
+}
+
+// FolderObjectError example
+type FolderObjectError struct {
+	FilePath string `json:"file_path" example:"reports/2024/summary.xlsx"`
+	Error    string `json:"error" example:"file already exists"`
+}
+
+// RestoreTrashForm example
+type RestoreTrashForm struct {
+	OnConflict string `json:"on_conflict" example:"rename" enums:"overwrite,skip,rename,fail" default:"fail"`
 }
 
 // RemoveFileForm example
@@ -39,11 +194,59 @@ type RemoveFileForm struct {
 	FileName string `json:"file_name" example:"test-file.docx"`
 }
 
+const (
+	UploadStatusUploaded   = "uploaded"
+	UploadStatusFailed     = "failed"
+	UploadStatusSkipped    = "skipped"
+	UploadStatusRolledBack = "rolled_back"
+)
+
+// UploadFilesForm example
+type UploadFilesForm struct {
+	Status   int                 `json:"status" example:"207"`
+	Uploaded int                 `json:"uploaded" example:"1"`
+	Skipped  int                 `json:"skipped" example:"0"`
+	Failed   int                 `json:"failed" example:"1"`
+	Files    []*UploadFileResult `json:"files"`
+}
+
+// UploadFileResult example
+type UploadFileResult struct {
+	FileName string `json:"file_name" example:"test-file.docx"`
+	Status   string `json:"status" example:"uploaded" enums:"uploaded,failed,skipped,rolled_back"`
+	FilePath string `json:"file_path" example:"test-file.docx"`
+	Size     int64  `json:"size" example:"1024"`
+	ETag     string `json:"etag,omitempty" example:"d41d8cd98f00b204e9800998ecf8427e"`
+	Error    string `json:"error,omitempty" example:"failed to upload file"`
+}
+
 // DownloadFileForm example
 type DownloadFileForm struct {
 	FileName string `json:"file_name" example:"test-file.docx"`
 }
 
+// FileVersionsForm example
+type FileVersionsForm struct {
+	FileName string `json:"file_name" example:"contracts/supply-2024.docx"`
+}
+
+// FileVersionForm example
+type FileVersionForm struct {
+	FileName  string `json:"file_name" example:"contracts/supply-2024.docx"`
+	VersionID string `json:"version_id" example:"3f1d0c5e9a7b4e21b6a8d2c4f0e9a1b7"`
+}
+
+// StatFileForm example
+type StatFileForm struct {
+	FileName string `json:"file_name" example:"test-file.docx"`
+}
+
+// FileExpiryForm example
+type FileExpiryForm struct {
+	FileName  string     `json:"file_name" example:"test-file.docx"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2025-01-01T12:01:01Z"`
+}
+
 // ShareFileForm example
 type ShareFileForm struct {
 	FileName    string `json:"file_name" example:"test-file.docx"`
@@ -51,13 +254,136 @@ type ShareFileForm struct {
 	ExpiredSecs int32  `json:"expired_secs" example:"3600"`
 }
 
+// ShareFilesForm example
+type ShareFilesForm struct {
+	Folder      string   `json:"folder,omitempty" example:"2024/"`
+	FileNames   []string `json:"file_names,omitempty" example:"2024/supply.docx,2024/invoice.pdf"`
+	ExpiredSecs int32    `json:"expired_secs" example:"3600"`
+}
+
 // GetFilesForm example
 type GetFilesForm struct {
-	DirectoryName string `json:"directory" example:"test-folder/"`
+	DirectoryName string     `json:"directory" example:"test-folder/"`
+	Recursive     bool       `json:"recursive" example:"false"`
+	Limit         int        `json:"limit" example:"100"`
+	Cursor        string     `json:"cursor" example:""`
+	Extensions    []string   `json:"extensions" example:"docx,pdf"`
+	MinSize       *int64     `json:"min_size" example:"1024"`
+	MaxSize       *int64     `json:"max_size" example:"1048576"`
+	ModifiedAfter *time.Time `json:"modified_after" example:"2025-01-01T12:01:01Z"`
+	NameGlob      string     `json:"name_glob" example:"report-*"`
+	SortBy        string     `json:"sort_by" example:"name" enums:"name,size,modified"`
+	SortDesc      bool       `json:"sort_desc" example:"false"`
 }
 
 // CopyFileForm example
 type CopyFileForm struct {
-	SrcPath string `json:"src_path" example:"old-test-document.docx"`
-	DstPath string `json:"dst_path" example:"test-document.docx"`
+	SrcPath    string `json:"src_path" example:"old-test-document.docx"`
+	DstPath    string `json:"dst_path" example:"test-document.docx"`
+	DstBucket  string `json:"dst_bucket,omitempty" example:"archive"`
+	OnConflict string `json:"on_conflict" example:"rename" enums:"overwrite,skip,rename,fail" default:"fail"`
+}
+
+// CreateUploadForm example
+type CreateUploadForm struct {
+	FilePath string `json:"file_path" example:"archives/field-data.zip"`
+}
+
+// UploadSessionForm example
+type UploadSessionForm struct {
+	UploadID string              `json:"upload_id" example:"eyJwIjoiYXJjaGl2ZXMvZmllbGQtZGF0YS56aXAifQ"`
+	FilePath string              `json:"file_path" example:"archives/field-data.zip"`
+	Parts    []*cloud.UploadPart `json:"parts"`
+}
+
+// PolicyBindingsForm example
+type PolicyBindingsForm struct {
+	Bindings []*rbac.Binding `json:"bindings"`
+}
+
+// CreateAPIKeyForm example
+type CreateAPIKeyForm struct {
+	Name       string             `json:"name" example:"indexer"`
+	Buckets    []string           `json:"buckets" example:"contracts,archive"`
+	Prefixes   []string           `json:"prefixes,omitempty" example:"2024/"`
+	Operations []apikey.Operation `json:"operations" example:"read,write" enums:"read,write,admin"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty" example:"2026-01-01T12:01:01Z"`
+}
+
+// APIKeyCreatedForm example
+type APIKeyCreatedForm struct {
+	Key   *apikey.Key `json:"key"`
+	Value string      `json:"value" example:"dhk_9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822c"`
+}
+
+// APIKeysForm example
+type APIKeysForm struct {
+	Keys []*apikey.Key `json:"keys"`
+}
+
+// AuditVerifyForm example
+type AuditVerifyForm struct {
+	IsValid bool   `json:"is_valid" example:"true"`
+	Checked int    `json:"checked" example:"1024"`
+	Error   string `json:"error,omitempty" example:"event 12 of request Jf3bQ2sL breaks hash chain"`
+}
+
+// CreateShareLinkForm example
+type CreateShareLinkForm struct {
+	FileName     string     `json:"file_name,omitempty" example:"2024/supply.docx"`
+	Folder       string     `json:"folder,omitempty" example:"2024/"`
+	FileNames    []string   `json:"file_names,omitempty" example:"2024/supply.docx,2024/invoice.pdf"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" example:"2025-01-08T12:01:01Z"`
+	MaxDownloads int        `json:"max_downloads,omitempty" example:"5"`
+	Password     string     `json:"password,omitempty" example:"s3cr3t-phrase"`
+}
+
+// ShareLinkCreatedForm example
+type ShareLinkCreatedForm struct {
+	Link  *share.Link `json:"link"`
+	Token string      `json:"token" example:"q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"`
+	URL   string      `json:"url" example:"https://docs.example.com/s/q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"`
+}
+
+// ShareLinksForm example
+type ShareLinksForm struct {
+	Links []*share.Link `json:"links"`
+}
+
+// PresignUploadForm example
+type PresignUploadForm struct {
+	Method      string `json:"method" example:"POST" enums:"PUT,POST"`
+	FilePath    string `json:"file_path,omitempty" example:"incoming/report.pdf"`
+	Folder      string `json:"folder,omitempty" example:"incoming/"`
+	ContentType string `json:"content_type,omitempty" example:"application/pdf"`
+	MaxSize     int64  `json:"max_size,omitempty" example:"104857600"`
+	ExpiredSecs int32  `json:"expired_secs" example:"3600"`
+}
+
+// CreateUploadLinkForm example
+type CreateUploadLinkForm struct {
+	Folder    string     `json:"folder" example:"incoming/acme/"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-01-08T12:01:01Z"`
+	MaxFiles  int        `json:"max_files,omitempty" example:"10"`
+	MaxSize   int64      `json:"max_size,omitempty" example:"104857600"`
+}
+
+// UploadLinkCreatedForm example
+type UploadLinkCreatedForm struct {
+	Link  *share.UploadLink `json:"link"`
+	Token string            `json:"token" example:"q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"`
+	URL   string            `json:"url" example:"https://docs.example.com/u/q8Zx3LkP0vT7mN2bR5yW1cJ4hF6dS9aE"`
+}
+
+// UploadLinksForm example
+type UploadLinksForm struct {
+	Links []*share.UploadLink `json:"links"`
+}
+
+// UploadLinkInfoForm example
+type UploadLinkInfoForm struct {
+	Folder         string     `json:"folder" example:"incoming/acme/"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" example:"2025-01-08T12:01:01Z"`
+	MaxSize        int64      `json:"max_size,omitempty" example:"104857600"`
+	RemainingFiles int        `json:"remaining_files" example:"7"`
 }
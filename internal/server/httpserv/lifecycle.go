@@ -0,0 +1,86 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+// GetBucketLifecycle
+// @Summary Get bucket lifecycle rules
+// @Description Get lifecycle rules of bucket, empty list means no rule is set
+// @ID get-bucket-lifecycle
+// @Tags buckets
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Success 200 {object} BucketLifecycleForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/lifecycle [get]
+func (s *ServerHttp) GetBucketLifecycle(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if err := s.authorize(c, bucket, "", rbac.RoleReader); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	rules, err := s.cloud.Cloud.GetBucketLifecycle(ctx, bucket)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, &BucketLifecycleForm{Rules: rules})
+}
+
+// SetBucketLifecycle
+// @Summary Set bucket lifecycle rules
+// @Description Replace lifecycle rules of bucket, empty rules list removes all rules.
+// @Description Each rule applies to objects under its prefix: expire objects, remove
+// @Description noncurrent versions, abort incomplete uploads or transition objects
+// @Description to another storage class after given number of days.
+// @ID set-bucket-lifecycle
+// @Tags buckets
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param jsonQuery body BucketLifecycleForm true "Lifecycle rules"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/lifecycle [put]
+func (s *ServerHttp) SetBucketLifecycle(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &BucketLifecycleForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationSetLifecycle, bucket, "")
+	if err := s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+		return err
+	}
+
+	if err := cloud.CheckLifecycleRules(jsonForm.Rules); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for _, rule := range jsonForm.Rules {
+		if rule.Status == "" {
+			rule.Status = cloud.LifecycleEnabled
+		}
+	}
+
+	ctx := c.Request().Context()
+	if err := s.cloud.Cloud.SetBucketLifecycle(ctx, bucket, jsonForm.Rules); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
@@ -0,0 +1,51 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-hub/internal/rbac"
+)
+
+func TestBucketLifecycle(t *testing.T) {
+	s := newAccessTestServer(t,
+		&rbac.Binding{Subject: "alice", Bucket: "b1", Role: rbac.RoleWriter},
+		&rbac.Binding{Subject: "root", Bucket: "b1", Role: rbac.RoleAdmin},
+	)
+	alice, root := bearer(t, "alice"), bearer(t, "root")
+	rules := `{"rules":[{"id":"tmp","prefix":"tmp/","expiration_days":1}]}`
+
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "set by writer", body: rules, headers: alice, status: http.StatusForbidden},
+		{name: "invalid rule", body: `{"rules":[{"id":"tmp","prefix":"tmp/"}]}`, headers: root, status: http.StatusBadRequest},
+		{name: "set by admin", body: rules, headers: root, status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPut, "/cloud/b1/lifecycle", strings.NewReader(test.body), test.headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	rec := s.serve(t, http.MethodGet, "/cloud/b1/lifecycle", nil, alice)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to get lifecycle: %s", rec.Body.String())
+	}
+	stored := &BucketLifecycleForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), stored); err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Rules) != 1 || stored.Rules[0].ID != "tmp" || stored.Rules[0].ExpirationDays != 1 {
+		t.Fatalf("stored rules %+v", stored.Rules)
+	}
+}
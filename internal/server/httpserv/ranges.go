@@ -0,0 +1,139 @@
+package httpserv
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"github.com/labstack/echo/v4"
+)
+
+var errRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+type byteRange struct {
+	offset int64
+	length int64
+}
+
+func (br *byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", br.offset, br.offset+br.length-1, size)
+}
+
+// pathParam returns unescaped value of wildcard route param.
+func pathParam(c echo.Context) (string, error) {
+	param := c.Param("*")
+	if c.Request().URL.RawPath == "" {
+		return param, nil
+	}
+
+	return url.PathUnescape(param)
+}
+
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+
+	return `"` + etag + `"`
+}
+
+// isNotModified checks If-None-Match and If-Modified-Since request
+// headers against object etag and modification time.
+func isNotModified(req *http.Request, item *cloud.StorageItem) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, item.ETag)
+	}
+
+	ifModifiedSince := req.Header.Get(echo.HeaderIfModifiedSince)
+	if ifModifiedSince == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	return !item.LastModified.Truncate(time.Second).After(since)
+}
+
+// isRangeFresh checks If-Range header which allows range request
+// only when object has not been changed since client cached it.
+func isRangeFresh(req *http.Request, item *cloud.StorageItem) bool {
+	ifRange := req.Header.Get("If-Range")
+	if ifRange == "" {
+		return true
+	}
+
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, `W/"`) {
+		return !strings.HasPrefix(ifRange, "W/") && ifRange == quoteETag(item.ETag)
+	}
+
+	since, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+
+	return item.LastModified.Truncate(time.Second).Equal(since)
+}
+
+func matchETag(header, etag string) bool {
+	quoted := strings.TrimPrefix(quoteETag(etag), "W/")
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == quoted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRange parses Range header with single byte range. Multiple ranges
+// are not supported and whole file is returned for them instead.
+func parseRange(header string, size int64) (*byteRange, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+
+	startValue, endValue, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return nil, errRangeNotSatisfiable
+	}
+
+	if startValue == "" {
+		suffix, err := strconv.ParseInt(endValue, 10, 64)
+		if err != nil || suffix <= 0 || size == 0 {
+			return nil, errRangeNotSatisfiable
+		}
+
+		suffix = min(suffix, size)
+		return &byteRange{offset: size - suffix, length: suffix}, nil
+	}
+
+	start, err := strconv.ParseInt(startValue, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return nil, errRangeNotSatisfiable
+	}
+
+	end := size - 1
+	if endValue != "" {
+		end, err = strconv.ParseInt(endValue, 10, 64)
+		if err != nil || end < start {
+			return nil, errRangeNotSatisfiable
+		}
+		end = min(end, size-1)
+	}
+
+	return &byteRange{offset: start, length: end - start + 1}, nil
+}
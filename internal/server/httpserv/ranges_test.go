@@ -0,0 +1,133 @@
+package httpserv
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		size     int64
+		expected *byteRange
+		err      error
+	}{
+		{name: "no header", header: "", size: 100},
+		{name: "whole range", header: "bytes=0-99", size: 100, expected: &byteRange{offset: 0, length: 100}},
+		{name: "first bytes", header: "bytes=0-9", size: 100, expected: &byteRange{offset: 0, length: 10}},
+		{name: "open end", header: "bytes=90-", size: 100, expected: &byteRange{offset: 90, length: 10}},
+		{name: "end beyond size", header: "bytes=50-1000", size: 100, expected: &byteRange{offset: 50, length: 50}},
+		{name: "single byte", header: "bytes=99-99", size: 100, expected: &byteRange{offset: 99, length: 1}},
+		{name: "suffix", header: "bytes=-10", size: 100, expected: &byteRange{offset: 90, length: 10}},
+		{name: "suffix beyond size", header: "bytes=-500", size: 100, expected: &byteRange{offset: 0, length: 100}},
+		{name: "spaces around spec", header: "bytes= 10-19 ", size: 100, expected: &byteRange{offset: 10, length: 10}},
+		{name: "multiple ranges", header: "bytes=0-9,20-29", size: 100},
+		{name: "other unit", header: "items=0-9", size: 100},
+		{name: "start beyond size", header: "bytes=100-", size: 100, err: errRangeNotSatisfiable},
+		{name: "end before start", header: "bytes=20-10", size: 100, err: errRangeNotSatisfiable},
+		{name: "negative start", header: "bytes=-1-5", size: 100, err: errRangeNotSatisfiable},
+		{name: "zero suffix", header: "bytes=-0", size: 100, err: errRangeNotSatisfiable},
+		{name: "suffix of empty file", header: "bytes=-10", size: 0, err: errRangeNotSatisfiable},
+		{name: "range of empty file", header: "bytes=0-", size: 0, err: errRangeNotSatisfiable},
+		{name: "no dash", header: "bytes=10", size: 100, err: errRangeNotSatisfiable},
+		{name: "not a number", header: "bytes=a-b", size: 100, err: errRangeNotSatisfiable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			byteRange, err := parseRange(test.header, test.size)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error %v, expected %v", err, test.err)
+			}
+
+			switch {
+			case test.expected == nil && byteRange != nil:
+				t.Fatalf("range %+v, expected whole file", *byteRange)
+			case test.expected != nil && (byteRange == nil || *byteRange != *test.expected):
+				t.Fatalf("range %+v, expected %+v", byteRange, *test.expected)
+			}
+		})
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	byteRange := &byteRange{offset: 10, length: 5}
+	if contentRange := byteRange.contentRange(100); contentRange != "bytes 10-14/100" {
+		t.Fatalf("content range %q", contentRange)
+	}
+}
+
+func TestIsRangeFresh(t *testing.T) {
+	modified := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	item := &cloud.StorageItem{ETag: "abc", LastModified: modified.Add(300 * time.Millisecond)}
+
+	tests := []struct {
+		name     string
+		ifRange  string
+		expected bool
+	}{
+		{name: "no header", ifRange: "", expected: true},
+		{name: "same etag", ifRange: `"abc"`, expected: true},
+		{name: "other etag", ifRange: `"xyz"`, expected: false},
+		{name: "weak etag", ifRange: `W/"abc"`, expected: false},
+		{name: "same date", ifRange: modified.Format(http.TimeFormat), expected: true},
+		{name: "older date", ifRange: modified.Add(-time.Hour).Format(http.TimeFormat), expected: false},
+		{name: "malformed date", ifRange: "yesterday", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if test.ifRange != "" {
+				req.Header.Set("If-Range", test.ifRange)
+			}
+			if fresh := isRangeFresh(req, item); fresh != test.expected {
+				t.Fatalf("isRangeFresh = %v, expected %v", fresh, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetFileRange(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "a.txt", "0123456789")
+
+	tests := []struct {
+		name         string
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{name: "whole file", status: http.StatusOK, body: "0123456789"},
+		{name: "first bytes", rangeHeader: "bytes=0-3", status: http.StatusPartialContent, body: "0123", contentRange: "bytes 0-3/10"},
+		{name: "suffix", rangeHeader: "bytes=-2", status: http.StatusPartialContent, body: "89", contentRange: "bytes 8-9/10"},
+		{name: "multiple ranges", rangeHeader: "bytes=0-1,4-5", status: http.StatusOK, body: "0123456789"},
+		{name: "not satisfiable", rangeHeader: "bytes=10-", status: http.StatusRequestedRangeNotSatisfiable, contentRange: "bytes */10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if test.rangeHeader != "" {
+				headers["Range"] = test.rangeHeader
+			}
+
+			rec := s.serve(t, http.MethodGet, "/cloud/b1/file/a.txt", nil, headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d", rec.Code, test.status)
+			}
+			if test.body != "" && rec.Body.String() != test.body {
+				t.Fatalf("body %q, expected %q", rec.Body.String(), test.body)
+			}
+			if contentRange := rec.Header().Get("Content-Range"); contentRange != test.contentRange {
+				t.Fatalf("content range %q, expected %q", contentRange, test.contentRange)
+			}
+		})
+	}
+}
@@ -1,31 +1,85 @@
 package httpserv
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/share"
+	"docs-hub/internal/trash"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	defaultFilesPageLimit = 1000
+	maxFilesPageLimit     = 10000
+	maxUploadParts        = 10000
+)
+
 func (s *ServerHttp) CreateCloudGroup() error {
 	group := s.server.Group("/cloud")
 
 	group.GET("/buckets", s.GetBuckets)
 	group.PUT("/bucket", s.CreateBucket)
 	group.DELETE("/:bucket", s.RemoveBucket)
+	group.POST("/:bucket/purge", s.PurgeBucket)
+	group.GET("/:bucket/versioning", s.GetBucketVersioning)
+	group.PUT("/:bucket/versioning", s.SetBucketVersioning)
+	group.GET("/:bucket/lifecycle", s.GetBucketLifecycle)
+	group.PUT("/:bucket/lifecycle", s.SetBucketLifecycle)
 
 	group.POST("/:bucket/files", s.GetFiles)
+	group.POST("/:bucket/files/move", s.MoveFiles)
 	group.POST("/:bucket/file/copy", s.CopyFile)
 	group.POST("/:bucket/file/move", s.MoveFile)
 	group.PUT("/:bucket/file/upload", s.UploadFile)
 	group.POST("/:bucket/file/download", s.DownloadFile)
+	group.POST("/:bucket/file/stat", s.StatFile)
+	group.PUT("/:bucket/file/expiry", s.SetFileExpiry)
+	group.GET("/:bucket/file/*", s.GetFile)
+	group.HEAD("/:bucket/file/*", s.GetFile)
 	group.DELETE("/:bucket/file/remove", s.RemoveFile)
+	group.POST("/:bucket/file/versions", s.ListFileVersions)
+	group.POST("/:bucket/file/version/download", s.DownloadVersion)
+	group.POST("/:bucket/file/version/restore", s.RestoreVersion)
+	group.DELETE("/:bucket/file/version/remove", s.RemoveVersion)
+
+	group.GET("/:bucket/trash", s.GetTrash)
+	group.POST("/:bucket/trash/:id/restore", s.RestoreTrash)
+	group.DELETE("/:bucket/trash", s.EmptyTrash)
+
+	group.PUT("/:bucket/folder", s.CreateFolder)
+	group.POST("/:bucket/folder/copy", s.CopyFolder)
+	group.POST("/:bucket/folder/move", s.MoveFolder)
+	group.DELETE("/:bucket/folder/remove", s.RemoveFolder)
+
+	group.POST("/:bucket/uploads", s.CreateUpload)
+	group.GET("/:bucket/uploads/:id", s.GetUpload)
+	group.PUT("/:bucket/uploads/:id/parts/:n", s.UploadPart)
+	group.POST("/:bucket/uploads/:id/complete", s.CompleteUpload)
+	group.DELETE("/:bucket/uploads/:id", s.AbortUpload)
 
 	group.POST("/:bucket/file/share", s.ShareFile)
+	group.POST("/:bucket/files/share", s.ShareFiles)
+	group.GET("/:bucket/share/*", s.DownloadSharedFile)
+	group.POST("/:bucket/shares", s.CreateShareLink)
+	group.GET("/:bucket/shares", s.GetShareLinks)
+	group.DELETE("/:bucket/shares/:id", s.RevokeShareLink)
+
+	group.POST("/:bucket/file/presign-upload", s.PresignUpload)
+	group.POST("/:bucket/upload-links", s.CreateUploadLink)
+	group.GET("/:bucket/upload-links", s.GetUploadLinks)
+	group.DELETE("/:bucket/upload-links/:id", s.RevokeUploadLink)
 
 	return nil
 }
@@ -46,7 +100,14 @@ func (s *ServerHttp) GetBuckets(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.JSON(200, watcherDirs)
+	visibleDirs := make([]string, 0, len(watcherDirs))
+	for _, bucket := range watcherDirs {
+		if s.canSeeBucket(c, bucket) {
+			visibleDirs = append(visibleDirs, bucket)
+		}
+	}
+
+	return c.JSON(200, visibleDirs)
 }
 
 // CreateBucket
@@ -56,7 +117,7 @@ func (s *ServerHttp) GetBuckets(c echo.Context) error {
 // @Tags buckets
 // @Accept  json
 // @Produce json
-// @Param jsonQuery body CreateBucketForm true "Bucket name to create"
+// @Param jsonQuery body CreateBucketForm true "Bucket name to create and its versioning status"
 // @Success 200 {object} ResponseForm "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
@@ -69,30 +130,67 @@ func (s *ServerHttp) CreateBucket(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	auditOperation(c, audit.OperationCreateBucket, jsonForm.BucketName, "")
+	if err = s.authorize(c, jsonForm.BucketName, "", rbac.RoleAdmin); err != nil {
+		return err
+	}
+
+	if jsonForm.Versioning != "" {
+		if err = cloud.CheckVersioningStatus(jsonForm.Versioning); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
 	ctx := c.Request().Context()
 	err = s.cloud.Cloud.CreateBucket(ctx, jsonForm.BucketName)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if jsonForm.Versioning != "" {
+		err = s.cloud.Cloud.SetBucketVersioning(ctx, jsonForm.BucketName, jsonForm.Versioning)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
 	return c.JSON(200, createStatusResponse(200, "Ok"))
 }
 
 // RemoveBucket
 // @Summary Remove bucket from cloud
-// @Description Remove bucket from cloud
+// @Description Remove bucket from cloud. Non-empty bucket is removed only with force flag,
+// @Description then all its objects, their versions and incomplete uploads are removed too.
 // @ID remove-bucket
 // @Tags buckets
 // @Produce  json
 // @Param bucket path string true "Bucket name to remove"
+// @Param force query bool false "Remove bucket with all its content"
 // @Success 200 {object} ResponseForm "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	409 {object} BadRequestForm "Bucket is not empty"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket} [delete]
 func (s *ServerHttp) RemoveBucket(c echo.Context) error {
 	bucket := c.Param("bucket")
+
+	auditOperation(c, audit.OperationRemoveBucket, bucket, "")
+	if err := s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+		return err
+	}
+
+	isForce, _ := strconv.ParseBool(c.QueryParam("force"))
 	ctx := c.Request().Context()
+	if isForce {
+		if err := s.cloud.Cloud.PurgeBucket(ctx, bucket); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
 	err := s.cloud.Cloud.RemoveBucket(ctx, bucket)
+	if errors.Is(err, cloud.ErrBucketNotEmpty) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -100,17 +198,45 @@ func (s *ServerHttp) RemoveBucket(c echo.Context) error {
 	return c.JSON(200, createStatusResponse(200, "Ok"))
 }
 
+// PurgeBucket
+// @Summary Remove all content of bucket
+// @Description Remove all objects of bucket, their versions and incomplete uploads but keep bucket
+// @ID purge-bucket
+// @Tags buckets
+// @Produce  json
+// @Param bucket path string true "Bucket name to purge"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/purge [post]
+func (s *ServerHttp) PurgeBucket(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	auditOperation(c, audit.OperationPurgeBucket, bucket, "")
+	if err := s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := s.cloud.Cloud.PurgeBucket(ctx, bucket); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
 // CopyFile
-// @Summary Copy file to another location into bucket
-// @Description Copy file to another location into bucket
+// @Summary Copy file to another location into bucket or another bucket
+// @Description Copy file server side to another location into bucket or another bucket of the same storage
 // @ID copy-file
 // @Tags files
 // @Accept  json
 // @Produce json
 // @Param bucket path string true "Bucket name of src file"
 // @Param jsonQuery body CopyFileForm true "Params to copy file"
-// @Success 200 {object} ResponseForm "Ok"
+// @Success 200 {object} FileResponseForm "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	409 {object} BadRequestForm "File already exists"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket}/file/copy [post]
 func (s *ServerHttp) CopyFile(c echo.Context) error {
@@ -123,26 +249,56 @@ func (s *ServerHttp) CopyFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if err = cloud.CheckConflictPolicy(jsonForm.OnConflict); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	dstBucket := jsonForm.DstBucket
+	if dstBucket == "" {
+		dstBucket = bucket
+	}
+
+	event := auditOperation(c, audit.OperationCopy, bucket, jsonForm.SrcPath)
+	event.DstBucket = dstBucket
+	event.DstPath = jsonForm.DstPath
+
+	if err = s.authorize(c, bucket, jsonForm.SrcPath, rbac.RoleReader); err != nil {
+		return err
+	}
+
+	if err = s.authorize(c, dstBucket, jsonForm.DstPath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
 	ctx := c.Request().Context()
-	err = s.cloud.Cloud.CopyFile(ctx, bucket, jsonForm.SrcPath, jsonForm.DstPath)
+	doc := s.cloud.Cloud
+	dstPath, err := cloud.CopyByPolicy(ctx, doc, bucket, jsonForm.SrcPath, dstBucket, jsonForm.DstPath, jsonForm.OnConflict)
+	if errors.Is(err, cloud.ErrFileSkipped) {
+		return c.JSON(200, createFileResponse(200, "Skipped", jsonForm.DstPath))
+	}
+	if errors.Is(err, cloud.ErrFileExists) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	event.DstPath = dstPath
 
-	return c.JSON(200, createStatusResponse(200, "Ok"))
+	return c.JSON(200, createFileResponse(200, "Ok", dstPath))
 }
 
 // MoveFile
-// @Summary Move file to another location into bucket
-// @Description Move file to another location into bucket
+// @Summary Move file to another location into bucket or another bucket
+// @Description Move file server side to another location into bucket or another bucket of the same storage
 // @ID move-file
 // @Tags files
 // @Accept  json
 // @Produce json
 // @Param bucket path string true "Bucket name of src file"
 // @Param jsonQuery body CopyFileForm true "Params to move file"
-// @Success 200 {object} ResponseForm "Ok"
+// @Success 200 {object} FileResponseForm "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	409 {object} BadRequestForm "File already exists"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket}/file/move [post]
 func (s *ServerHttp) MoveFile(c echo.Context) error {
@@ -155,33 +311,66 @@ func (s *ServerHttp) MoveFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if err = cloud.CheckConflictPolicy(jsonForm.OnConflict); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	dstBucket := jsonForm.DstBucket
+	if dstBucket == "" {
+		dstBucket = bucket
+	}
+
+	event := auditOperation(c, audit.OperationMove, bucket, jsonForm.SrcPath)
+	event.DstBucket = dstBucket
+	event.DstPath = jsonForm.DstPath
+
+	if err = s.authorize(c, bucket, jsonForm.SrcPath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	if err = s.authorize(c, dstBucket, jsonForm.DstPath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
 	ctx := c.Request().Context()
-	err = s.cloud.Cloud.CopyFile(ctx, bucket, jsonForm.SrcPath, jsonForm.DstPath)
+	doc := s.cloud.Cloud
+	dstPath, err := cloud.MoveByPolicy(ctx, doc, bucket, jsonForm.SrcPath, dstBucket, jsonForm.DstPath, jsonForm.OnConflict)
+	if errors.Is(err, cloud.ErrFileSkipped) {
+		return c.JSON(200, createFileResponse(200, "Skipped", jsonForm.DstPath))
+	}
+	if errors.Is(err, cloud.ErrFileExists) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	event.DstPath = dstPath
 
-	return c.JSON(200, createStatusResponse(200, "Ok"))
+	return c.JSON(200, createFileResponse(200, "Ok", dstPath))
 }
 
 // UploadFile
 // @Summary Upload files to cloud
-// @Description Upload files to cloud
+// @Description Upload files to cloud and report result of every file. With atomic mode
+// @Description files are uploaded to staging area and are moved to their paths only if
+// @Description every file has been uploaded, so existing files are never lost by rollback.
 // @ID upload-files
 // @Tags files
 // @Accept  multipart/form
 // @Produce  json
 // @Param bucket path string true "Bucket name to upload files"
-// @Param expired query string false "File datetime expired like 2025-01-01T12:01:01Z"
+// @Param expired query string false "File datetime expired like 2025-01-01T12:01:01Z, expired files are removed by sweeper"
+// @Param atomic query bool false "Rollback uploaded files if any file failed"
+// @Param directory query string false "Directory to upload files into"
+// @Param on_conflict query string false "Policy if file already exists" Enums(overwrite, skip, rename, fail) default(fail)
 // @Param files formData file true "Files multipart form"
-// @Success 200 {object} ResponseForm "Ok"
-// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Success 200 {object} UploadFilesForm "Ok"
+// @Success 207 {object} UploadFilesForm "Some files have not been uploaded"
+// @Failure	400 {object} UploadFilesForm "No file has been uploaded"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket}/file/upload [put]
 func (s *ServerHttp) UploadFile(c echo.Context) error {
-	var fileData bytes.Buffer
-
-	multipartForm, err := c.MultipartForm()
+	multipartReader, err := c.Request().MultipartReader()
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -192,52 +381,232 @@ func (s *ServerHttp) UploadFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
 	}
 
-	if multipartForm.File["files"] == nil {
-		err = fmt.Errorf("there are no files into multipart form")
+	var expiresAt *time.Time
+	expired := c.QueryParam("expired")
+	if timeVal, err := time.Parse(time.RFC3339, expired); err == nil {
+		expiresAt = &timeVal
+	} else {
+		log.Println("failed to parse expired time param: ", expired, err)
+	}
+
+	isAtomic, _ := strconv.ParseBool(c.QueryParam("atomic"))
+	directory := c.QueryParam("directory")
+	onConflict := c.QueryParam("on_conflict")
+	if err = cloud.CheckConflictPolicy(onConflict); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	expired := c.QueryParam("expired")
-	timeVal, timeParseErr := time.Parse(time.RFC3339, expired)
-	if timeParseErr != nil {
-		log.Println("failed to parse expired time param: ", expired, timeParseErr)
+	stagingDir := ""
+	if isAtomic {
+		stagingDir, err = cloud.NewStagingDir()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
 	}
 
+	hasFailed := false
+	results := make([]*UploadFileResult, 0)
 	ctx := c.Request().Context()
-	for _, fileForm := range multipartForm.File["files"] {
-		fileName := fileForm.Filename
-		fileHandler, err := fileForm.Open()
+	for {
+		part, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
-			log.Println("failed to open file form", err)
+			if isAtomic {
+				s.commitUploads(ctx, bucket, stagingDir, results, true, onConflict, nil)
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		fileName := part.FileName()
+		if part.FormName() != "files" || fileName == "" {
+			_ = part.Close()
 			continue
 		}
-		defer func() {
-			if err := fileHandler.Close(); err != nil {
-				log.Println("failed to close file handler: ", fileName, err)
-				return
-			}
-		}()
 
-		fileData.Reset()
-		_, err = fileData.ReadFrom(fileHandler)
-		if err != nil {
-			log.Println("failed to read file form", fileName, err)
+		filePath := cloud.JoinFilePath(directory, fileName)
+		result := &UploadFileResult{FileName: fileName, FilePath: filePath}
+		results = append(results, result)
+		if isAtomic && hasFailed {
+			result.Status = UploadStatusSkipped
+			_ = part.Close()
 			continue
 		}
 
-		if timeParseErr == nil {
-			err = s.cloud.Cloud.UploadExpired(ctx, bucket, fileName, timeVal, fileData)
-		} else {
-			err = s.cloud.Cloud.UploadFile(ctx, bucket, fileName, fileData)
+		err = s.checkAccess(c, bucket, filePath, rbac.RoleWriter)
+		if err == nil {
+			filePath, err = cloud.ResolveConflict(ctx, s.cloud.Cloud, bucket, filePath, onConflict)
+		}
+		if errors.Is(err, cloud.ErrFileSkipped) {
+			result.Status = UploadStatusSkipped
+			result.Error = err.Error()
+			_ = part.Close()
+			continue
+		}
+
+		// Part size is unknown until it has been read, so stream it as is.
+		// Atomic upload keeps file in staging area until commit, expiry is
+		// set on commit because it belongs to final path.
+		if err == nil {
+			result.FilePath = filePath
+			err = s.uploadPart(ctx, bucket, stagingDir, filePath, part, onConflict, expiresAt)
+		}
+		if errors.Is(err, cloud.ErrFileExists) && onConflict == cloud.ConflictSkip {
+			result.Status = UploadStatusSkipped
+			result.Error = err.Error()
+			_ = part.Close()
+			continue
+		}
+
+		if err := part.Close(); err != nil {
+			log.Println("failed to close file part: ", fileName, err)
 		}
 
 		if err != nil {
 			log.Println("failed to upload file to cloud: ", fileName, err)
+			result.Status = UploadStatusFailed
+			result.Error = err.Error()
+			hasFailed = true
 			continue
 		}
+
+		result.Status = UploadStatusUploaded
+		if !isAtomic {
+			s.statUpload(ctx, bucket, filePath, result)
+		}
 	}
 
-	return c.JSON(200, createStatusResponse(200, "Ok"))
+	if isAtomic {
+		s.commitUploads(ctx, bucket, stagingDir, results, hasFailed, onConflict, expiresAt)
+	}
+
+	if len(results) == 0 {
+		err = fmt.Errorf("there are no files into multipart form")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	s.auditUploads(c, bucket, results)
+
+	response := createUploadFilesResponse(results)
+	return c.JSON(response.Status, response)
+}
+
+// auditUploads records every file of upload request which has been tried
+// to upload, rolled back files are recorded as failed uploads.
+func (s *ServerHttp) auditUploads(c echo.Context, bucket string, results []*UploadFileResult) {
+	for _, result := range results {
+		if result.Status == UploadStatusSkipped {
+			continue
+		}
+
+		event := &audit.Event{
+			Operation: audit.OperationUpload,
+			Bucket:    bucket,
+			Path:      result.FilePath,
+			Size:      result.Size,
+		}
+
+		var err error
+		switch result.Status {
+		case UploadStatusFailed:
+			err = errors.New(result.Error)
+		case UploadStatusRolledBack:
+			err = errors.New("upload has been rolled back")
+		}
+
+		s.recordAudit(c, event, err)
+	}
+}
+
+// uploadPart stores file of multipart form. Atomic upload keeps it in staging
+// area until commit, expiry is set on commit because it belongs to final path.
+// File is stored only if its path is still free unless policy is overwrite,
+// part is streamed once, so path taken meanwhile fails upload even on rename.
+func (s *ServerHttp) uploadPart(
+	ctx context.Context,
+	bucket, stagingDir, filePath string,
+	part io.Reader,
+	onConflict string,
+	expiresAt *time.Time,
+) error {
+	doc := s.cloud.Cloud
+	switch {
+	case stagingDir != "":
+		return doc.UploadFile(ctx, bucket, stagingDir+filePath, part, -1)
+	case !cloud.IsOverwrite(onConflict):
+		if err := doc.UploadNewFile(ctx, bucket, filePath, part, -1); err != nil {
+			return err
+		}
+		if expiresAt != nil {
+			return doc.SetFileExpiry(ctx, bucket, filePath, expiresAt)
+		}
+		return nil
+	case expiresAt != nil:
+		return doc.UploadExpired(ctx, bucket, filePath, *expiresAt, part, -1)
+	default:
+		return doc.UploadFile(ctx, bucket, filePath, part, -1)
+	}
+}
+
+func (s *ServerHttp) statUpload(ctx context.Context, bucket, filePath string, result *UploadFileResult) {
+	if fileInfo, err := s.cloud.Cloud.StatFile(ctx, bucket, filePath); err == nil {
+		result.Size = fileInfo.Size
+		result.ETag = fileInfo.ETag
+	}
+}
+
+// commitUploads moves staged files of atomic upload to their paths if every
+// file has been uploaded, otherwise staged files are removed. Existing files
+// are replaced by commit only, so rollback never touches them. Conflict policy
+// is applied again on commit to files which have been stored meanwhile.
+func (s *ServerHttp) commitUploads(
+	ctx context.Context,
+	bucket, stagingDir string,
+	results []*UploadFileResult,
+	hasFailed bool,
+	onConflict string,
+	expiresAt *time.Time,
+) {
+	for _, result := range results {
+		if result.Status != UploadStatusUploaded {
+			continue
+		}
+
+		stagedPath := stagingDir + result.FilePath
+		if hasFailed {
+			if err := s.cloud.Cloud.RemoveFile(ctx, bucket, stagedPath); err != nil {
+				log.Println("failed to remove staged file: ", stagedPath, err)
+			}
+			result.Status = UploadStatusRolledBack
+			continue
+		}
+
+		doc := s.cloud.Cloud
+		filePath, err := cloud.MoveByPolicy(ctx, doc, bucket, stagedPath, bucket, result.FilePath, onConflict)
+		if errors.Is(err, cloud.ErrFileSkipped) {
+			if err := doc.RemoveFile(ctx, bucket, stagedPath); err != nil {
+				log.Println("failed to remove staged file: ", stagedPath, err)
+			}
+			result.Status = UploadStatusSkipped
+			result.Error = err.Error()
+			continue
+		}
+		if err == nil {
+			result.FilePath = filePath
+			if expiresAt != nil {
+				err = doc.SetFileExpiry(ctx, bucket, filePath, expiresAt)
+			}
+		}
+		if err != nil {
+			log.Println("failed to commit uploaded file: ", result.FilePath, err)
+			result.Status = UploadStatusFailed
+			result.Error = fmt.Sprintf("failed to commit: %s", err.Error())
+			continue
+		}
+
+		s.statUpload(ctx, bucket, result.FilePath, result)
+	}
 }
 
 // DownloadFile
@@ -262,26 +631,204 @@ func (s *ServerHttp) DownloadFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	auditOperation(c, audit.OperationDownload, bucket, jsonForm.FileName)
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleReader); err != nil {
+		return err
+	}
+
 	ctx := c.Request().Context()
 	fileData, err := s.cloud.Cloud.DownloadFile(ctx, bucket, jsonForm.FileName)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	defer fileData.Reset()
+	defer closeFileData(jsonForm.FileName, fileData)
+
+	return c.Stream(200, echo.MIMEMultipartForm, fileData)
+}
+
+// GetFile
+// @Summary Get file content from cloud
+// @Description Get file content with support of byte ranges and conditional requests
+// @ID get-file
+// @Tags files
+// @Produce octet-stream
+// @Param bucket path string true "Bucket name to get file"
+// @Param path path string true "File path into bucket"
+// @Param Range header string false "Byte range like bytes=0-1023"
+// @Param If-None-Match header string false "Cached file etag"
+// @Param If-Modified-Since header string false "Cached file modification time"
+// @Success 200 {file} io.Writer "Ok"
+// @Success 206 {file} io.Writer "Partial Content"
+// @Success 304 "Not Modified"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	404 {object} BadRequestForm "Not Found message"
+// @Failure	416 {object} BadRequestForm "Range Not Satisfiable message"
+// @Router /cloud/{bucket}/file/{path} [get]
+// @Router /cloud/{bucket}/file/{path} [head]
+func (s *ServerHttp) GetFile(c echo.Context) error {
+	bucket := c.Param("bucket")
+	filePath, err := pathParam(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Metadata requests are not recorded, content is not sent by them.
+	if c.Request().Method == http.MethodGet {
+		auditOperation(c, audit.OperationDownload, bucket, filePath)
+	}
+
+	if err = s.authorize(c, bucket, filePath, rbac.RoleReader); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	fileInfo, err := s.cloud.Cloud.StatFile(ctx, bucket, filePath)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderLastModified, fileInfo.LastModified.UTC().Format(http.TimeFormat))
+	header.Set("Accept-Ranges", "bytes")
+	if fileInfo.ETag != "" {
+		header.Set("ETag", quoteETag(fileInfo.ETag))
+	}
+
+	if isNotModified(c.Request(), fileInfo) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	contentType := fileInfo.ContentType
+	if contentType == "" || contentType == echo.MIMEOctetStream {
+		contentType = cloud.ContentTypeOf(filePath)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		header.Set(echo.HeaderContentType, contentType)
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(fileInfo.Size, 10))
+		return c.NoContent(http.StatusOK)
+	}
+
+	var fileRange *byteRange
+	if isRangeFresh(c.Request(), fileInfo) {
+		fileRange, err = parseRange(c.Request().Header.Get("Range"), fileInfo.Size)
+		if err != nil {
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", fileInfo.Size))
+			return echo.NewHTTPError(http.StatusRequestedRangeNotSatisfiable, err.Error())
+		}
+	}
+
+	if fileRange == nil {
+		fileData, err := s.cloud.Cloud.DownloadFile(ctx, bucket, filePath)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		defer closeFileData(filePath, fileData)
+
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(fileInfo.Size, 10))
+		return c.Stream(http.StatusOK, contentType, fileData)
+	}
+
+	fileData, err := s.cloud.Cloud.DownloadRange(ctx, bucket, filePath, fileRange.offset, fileRange.length)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	defer closeFileData(filePath, fileData)
+
+	header.Set("Content-Range", fileRange.contentRange(fileInfo.Size))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(fileRange.length, 10))
+	return c.Stream(http.StatusPartialContent, contentType, fileData)
+}
+
+// StatFile
+// @Summary Get file metadata
+// @Description Get file metadata without downloading file content
+// @ID stat-file
+// @Tags files
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of file"
+// @Param jsonQuery body StatFileForm true "Parameters to get file metadata"
+// @Success 200 {object} cloud.StorageItem "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	404 {object} BadRequestForm "Not Found message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/stat [post]
+func (s *ServerHttp) StatFile(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &StatFileForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleReader); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	fileInfo, err := s.cloud.Cloud.StatFile(ctx, bucket, jsonForm.FileName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	return c.JSON(200, fileInfo)
+}
+
+// SetFileExpiry
+// @Summary Set file expiry
+// @Description Extend or shorten expiry of existing file, null expires_at clears expiry
+// @Description so file is kept until it is removed explicitly
+// @ID set-file-expiry
+// @Tags files
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of file"
+// @Param jsonQuery body FileExpiryForm true "File and its new expiry"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/expiry [put]
+func (s *ServerHttp) SetFileExpiry(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &FileExpiryForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationSetExpiry, bucket, jsonForm.FileName)
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	if jsonForm.ExpiresAt != nil && !jsonForm.ExpiresAt.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "expiry time has already passed")
+	}
+
+	ctx := c.Request().Context()
+	err := s.cloud.Cloud.SetFileExpiry(ctx, bucket, jsonForm.FileName, jsonForm.ExpiresAt)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
-	return c.Blob(200, echo.MIMEMultipartForm, fileData.Bytes())
+	return c.JSON(200, createStatusResponse(200, "Ok"))
 }
 
 // RemoveFile
 // @Summary Remove file from cloud
-// @Description Remove file from cloud
+// @Description Move file into trash of bucket when trash is enabled, otherwise remove it permanently
 // @ID remove-file
 // @Tags files
 // @Produce  json
 // @Param bucket path string true "Bucket name to remove file"
+// @Param permanent query bool false "Remove file permanently bypassing trash, admin role is required"
 // @Param jsonQuery body RemoveFileForm true "Parameters to remove file"
 // @Success 200 {object} ResponseForm "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket}/file/remove [delete]
 func (s *ServerHttp) RemoveFile(c echo.Context) error {
@@ -293,7 +840,22 @@ func (s *ServerHttp) RemoveFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	isPermanent, _ := strconv.ParseBool(c.QueryParam("permanent"))
+	auditOperation(c, audit.OperationRemove, bucket, jsonForm.FileName)
+	if err := s.authorize(c, bucket, jsonForm.FileName, removeRole(jsonForm.FileName, isPermanent)); err != nil {
+		return err
+	}
+
 	ctx := c.Request().Context()
+	if s.trash.IsEnabled() && !isPermanent && !trash.IsTrashPath(jsonForm.FileName) {
+		_, err := s.trash.MoveToTrash(ctx, bucket, jsonForm.FileName, requestActor(c))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		return c.JSON(200, createStatusResponse(200, "Moved to trash"))
+	}
+
 	if err := s.cloud.Cloud.RemoveFile(ctx, bucket, jsonForm.FileName); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -303,14 +865,18 @@ func (s *ServerHttp) RemoveFile(c echo.Context) error {
 
 // GetFiles
 // @Summary Get files list into bucket
-// @Description Get files list into bucket
+// @Description Get paginated files list into bucket filtered and sorted by parameters.
+// @Description Listing sorted by name ascending continues after last listed file. Other sort orders
+// @Description load and sort whole listing for every page, so they are limited to 10000 files matching
+// @Description directory and filters, larger listing is rejected with 400. Their cursor is offset,
+// @Description so pages may skip or repeat files if listing is changed between requests.
 // @ID get-list-files
 // @Tags files
 // @Accept  json
 // @Produce json
 // @Param bucket path string true "Bucket name to get list files"
 // @Param jsonQuery body GetFilesForm true "Parameters to get list files"
-// @Success 200 {object} ResponseForm "Ok"
+// @Success 200 {object} cloud.FilesPage "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket}/files [post]
@@ -324,18 +890,60 @@ func (s *ServerHttp) GetFiles(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	limit := jsonForm.Limit
+	if limit <= 0 {
+		limit = defaultFilesPageLimit
+	}
+	limit = min(limit, maxFilesPageLimit)
+
+	params := &cloud.ListFilesParams{
+		Prefix:    jsonForm.DirectoryName,
+		Recursive: jsonForm.Recursive,
+		Limit:     limit,
+		Cursor:    jsonForm.Cursor,
+		Filter: cloud.ListFilesFilter{
+			Extensions:    jsonForm.Extensions,
+			MinSize:       jsonForm.MinSize,
+			MaxSize:       jsonForm.MaxSize,
+			ModifiedAfter: jsonForm.ModifiedAfter,
+			NameGlob:      jsonForm.NameGlob,
+		},
+		SortBy:   jsonForm.SortBy,
+		SortDesc: jsonForm.SortDesc,
+	}
+
+	if !s.canBrowse(c, bucket, params.Prefix) {
+		retErr := fmt.Errorf("%w: listing of %s/%s", errAccessDenied, bucket, params.Prefix)
+		return echo.NewHTTPError(http.StatusForbidden, retErr.Error())
+	}
+
+	// Trash area is listed by its own endpoint only, staging area is
+	// never listed, and items which caller has no access to are hidden.
+	isTrashListed := trash.IsTrashPath(params.Prefix)
+	params.Filter.Visible = func(item *cloud.StorageItem) bool {
+		if !isTrashListed && trash.IsTrashPath(item.FileName) {
+			return false
+		}
+		if cloud.IsStagingPath(item.FileName) {
+			return false
+		}
+		return s.canBrowse(c, bucket, item.FileName)
+	}
+
 	ctx := c.Request().Context()
-	listObjects, err := s.cloud.Cloud.GetFiles(ctx, bucket, jsonForm.DirectoryName)
+	filesPage, err := s.cloud.Cloud.GetFiles(ctx, bucket, params)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return c.JSON(200, listObjects)
+	return c.JSON(200, filesPage)
 }
 
 // ShareFile
 // @Summary Get share URL for file
-// @Description Get share URL for file
+// @Description Get share URL for file. When share links are enabled URL is managed share link
+// @Description of docs-hub which expires after given seconds, otherwise it is cloud presigned URL.
+// @Description Managed link without expired_secs expires after default expiry of share links.
 // @ID share-file
 // @Tags share
 // @Accept  json
@@ -344,6 +952,7 @@ func (s *ServerHttp) GetFiles(c echo.Context) error {
 // @Param jsonQuery body ShareFileForm true "Parameters to share file"
 // @Success 200 {object} ResponseForm "Ok"
 // @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	500 {object} ServerErrorForm "Base URL of links is not configured"
 // @Failure	503 {object} ServerErrorForm "Server does not available"
 // @Router /cloud/{bucket}/file/share [post]
 func (s *ServerHttp) ShareFile(c echo.Context) error {
@@ -356,7 +965,30 @@ func (s *ServerHttp) ShareFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if jsonForm.ExpiredSecs < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "expired_secs must not be negative")
+	}
+
 	expired := time.Second * time.Duration(jsonForm.ExpiredSecs)
+	if s.shares.IsEnabled() {
+		params := &share.LinkParams{Bucket: bucket, FilePath: jsonForm.FileName}
+		if expired > 0 {
+			expiresAt := time.Now().Add(expired)
+			params.ExpiresAt = &expiresAt
+		}
+
+		response, err := s.createShareLink(c, params)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(200, createStatusResponse(200, response.URL))
+	}
+
+	auditOperation(c, audit.OperationShare, bucket, jsonForm.FileName)
+	if err = s.authorize(c, bucket, jsonForm.FileName, rbac.RoleReader); err != nil {
+		return err
+	}
 
 	ctx := c.Request().Context()
 	url, err := s.cloud.Cloud.GetShareURL(ctx, bucket, jsonForm.FileName, expired)
@@ -364,5 +996,67 @@ func (s *ServerHttp) ShareFile(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	// URLs signed by docs-hub itself are relative to its address.
+	if strings.HasPrefix(url, "/") {
+		baseURL, err := s.linkBaseURL()
+		if err != nil {
+			return err
+		}
+		url = baseURL + url
+	}
+
 	return c.JSON(200, createStatusResponse(200, url))
 }
+
+// DownloadSharedFile
+// @Summary Download file by signed share URL
+// @Description Download file by share URL signed by docs-hub for clouds without presigning
+// @ID download-shared-file
+// @Tags share
+// @Produce octet-stream
+// @Param bucket path string true "Bucket name of shared file"
+// @Param path path string true "Shared file path"
+// @Param expires query int true "Share URL expiration unix timestamp"
+// @Param signature query string true "Share URL signature"
+// @Success 200 {file} io.Writer "Ok"
+// @Failure	403 {object} BadRequestForm "Forbidden message"
+// @Failure	404 {object} BadRequestForm "Not Found message"
+// @Router /cloud/{bucket}/share/{path} [get]
+func (s *ServerHttp) DownloadSharedFile(c echo.Context) error {
+	verifier, ok := s.cloud.Cloud.(cloud.IShareVerifier)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "cloud does not serve share urls")
+	}
+
+	bucket := c.Param("bucket")
+	filePath, err := pathParam(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationDownload, bucket, filePath)
+	expires, err := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, "invalid share url expires param")
+	}
+
+	ctx := c.Request().Context()
+	signature := c.QueryParam("signature")
+	if err = verifier.VerifyShareURL(ctx, bucket, filePath, expires, signature); err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	fileData, err := s.cloud.Cloud.DownloadFile(ctx, bucket, filePath)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	defer closeFileData(filePath, fileData)
+
+	return c.Stream(200, echo.MIMEOctetStream, fileData)
+}
+
+func closeFileData(fileName string, fileData io.Closer) {
+	if err := fileData.Close(); err != nil {
+		log.Println("failed to close file data: ", fileName, err)
+	}
+}
@@ -0,0 +1,335 @@
+package httpserv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+const testBoundary = "test-boundary"
+
+// multipartBody builds upload form of files, form is cut in the middle
+// of extra file if truncated so that reading of it fails.
+func multipartBody(files map[string]string, names []string, truncated bool) *bytes.Buffer {
+	body := &bytes.Buffer{}
+	for _, name := range names {
+		fmt.Fprintf(body, "--%s\r\nContent-Disposition: form-data; name=\"files\"; filename=\"%s\"\r\n\r\n%s\r\n",
+			testBoundary, name, files[name])
+	}
+
+	if truncated {
+		fmt.Fprintf(body, "--%s\r\nContent-Disposition: form-data; name=\"files\"; filename=\"broken.txt\"\r\n\r\npartial",
+			testBoundary)
+	} else {
+		fmt.Fprintf(body, "--%s--\r\n", testBoundary)
+	}
+
+	return body
+}
+
+func TestGetFileRejectsInvalidPath(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "docs/a.txt", "hello")
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+	}{
+		{name: "plain path", target: "/cloud/b1/file/docs/a.txt", status: http.StatusOK},
+		{name: "escaped slash", target: "/cloud/b1/file/docs%2Fa.txt", status: http.StatusOK},
+		{name: "escaped parent", target: "/cloud/b1/file/docs%2F..%2Fdocs%2Fa.txt", status: http.StatusBadRequest},
+		{name: "escaped dot", target: "/cloud/b1/file/.%2Fdocs%2Fa.txt", status: http.StatusBadRequest},
+		{name: "empty segment", target: "/cloud/b1/file/docs%2F%2Fa.txt", status: http.StatusBadRequest},
+		{name: "missing file", target: "/cloud/b1/file/docs/b.txt", status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodGet, test.target, nil, nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetFilesHidesTrashBeforePaging(t *testing.T) {
+	s := newTestServer(t)
+	for _, filePath := range []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"} {
+		s.uploadText(t, filePath, filePath)
+	}
+	for _, filePath := range []string{"a.txt", "b.txt"} {
+		rec := s.serve(t, http.MethodDelete, "/cloud/b1/file/remove", strings.NewReader(`{"file_name":"`+filePath+`"}`), nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("failed to remove %s: %s", filePath, rec.Body.String())
+		}
+	}
+
+	listed := make([]string, 0)
+	cursor := ""
+	for page := 1; ; page++ {
+		form := fmt.Sprintf(`{"recursive":true,"limit":2,"cursor":%q}`, cursor)
+		rec := s.serve(t, http.MethodPost, "/cloud/b1/files", strings.NewReader(form), nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("listing failed: %s", rec.Body.String())
+		}
+
+		filesPage := &cloud.FilesPage{}
+		if err := json.Unmarshal(rec.Body.Bytes(), filesPage); err != nil {
+			t.Fatal(err)
+		}
+		if filesPage.NextCursor != "" && len(filesPage.Items) != 2 {
+			t.Fatalf("page %d has %d items and next cursor", page, len(filesPage.Items))
+		}
+		for _, item := range filesPage.Items {
+			listed = append(listed, item.FileName)
+		}
+
+		if cursor = filesPage.NextCursor; cursor == "" || page > 3 {
+			break
+		}
+	}
+
+	if strings.Join(listed, ",") != "c.txt,d.txt,e.txt" {
+		t.Fatalf("listed %v", listed)
+	}
+}
+
+func TestUploadFileAtomic(t *testing.T) {
+	files := map[string]string{"a.txt": "new a", "b.txt": "new b"}
+
+	tests := []struct {
+		name      string
+		truncated bool
+		status    int
+		expected  map[string]string
+	}{
+		{
+			name:     "committed on success",
+			status:   http.StatusOK,
+			expected: map[string]string{"docs/a.txt": "new a", "docs/b.txt": "new b"},
+		},
+		{
+			name:      "existing file kept on rollback",
+			truncated: true,
+			status:    http.StatusBadRequest,
+			expected:  map[string]string{"docs/a.txt": "old a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.uploadText(t, "docs/a.txt", "old a")
+
+			body := multipartBody(files, []string{"a.txt", "b.txt"}, test.truncated)
+			target := "/cloud/b1/file/upload?directory=docs&atomic=true&on_conflict=overwrite"
+			headers := map[string]string{"Content-Type": "multipart/form-data; boundary=" + testBoundary}
+			rec := s.serve(t, http.MethodPut, target, body, headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+
+			items, err := cloud.ListFolder(context.Background(), s.cloud.Cloud, "b1", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != len(test.expected) {
+				t.Fatalf("bucket has %d files, expected %d", len(items), len(test.expected))
+			}
+			for filePath, content := range test.expected {
+				if actual := s.readText(t, filePath); actual != content {
+					t.Fatalf("%s has content %q, expected %q", filePath, actual, content)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadFileResults(t *testing.T) {
+	files := map[string]string{"a.txt": "new a", "b.txt": "new b"}
+
+	tests := []struct {
+		name       string
+		onConflict string
+		status     int
+		statuses   string
+		content    string
+	}{
+		{name: "failed file", onConflict: cloud.ConflictFail, status: http.StatusMultiStatus, statuses: "failed,uploaded", content: "old a"},
+		{name: "skipped file", onConflict: cloud.ConflictSkip, status: http.StatusOK, statuses: "skipped,uploaded", content: "old a"},
+		{name: "overwritten file", onConflict: cloud.ConflictOverwrite, status: http.StatusOK, statuses: "uploaded,uploaded", content: "new a"},
+		{name: "renamed file", onConflict: cloud.ConflictRename, status: http.StatusOK, statuses: "uploaded,uploaded", content: "old a"},
+		{name: "default policy", onConflict: "", status: http.StatusMultiStatus, statuses: "failed,uploaded", content: "old a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.uploadText(t, "docs/a.txt", "old a")
+
+			body := multipartBody(files, []string{"a.txt", "b.txt"}, false)
+			target := "/cloud/b1/file/upload?directory=docs&on_conflict=" + test.onConflict
+			headers := map[string]string{"Content-Type": "multipart/form-data; boundary=" + testBoundary}
+			rec := s.serve(t, http.MethodPut, target, body, headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+
+			response := &UploadFilesForm{}
+			if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+				t.Fatal(err)
+			}
+			statuses := make([]string, 0, len(response.Files))
+			for _, result := range response.Files {
+				statuses = append(statuses, result.Status)
+			}
+			if strings.Join(statuses, ",") != test.statuses || response.Status != test.status {
+				t.Fatalf("results %v with status %d", statuses, response.Status)
+			}
+			if result := response.Files[1]; result.FilePath != "docs/b.txt" || result.Size != 5 || result.ETag == "" {
+				t.Fatalf("unexpected result %+v", result)
+			}
+
+			if content := s.readText(t, "docs/a.txt"); content != test.content {
+				t.Fatalf("existing file has content %q, expected %q", content, test.content)
+			}
+		})
+	}
+}
+
+func TestCopyAndMoveFileToOtherBucket(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	if err := s.cloud.Cloud.CreateBucket(ctx, "b2"); err != nil {
+		t.Fatal(err)
+	}
+	s.uploadText(t, "a.txt", "a")
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{name: "copy", target: "/cloud/b1/file/copy", body: `{"src_path":"a.txt","dst_path":"in/a.txt","dst_bucket":"b2"}`, status: http.StatusOK},
+		{name: "copy to missing bucket", target: "/cloud/b1/file/copy", body: `{"src_path":"a.txt","dst_path":"a.txt","dst_bucket":"b9"}`, status: http.StatusBadRequest},
+		{name: "move over copy", target: "/cloud/b1/file/move", body: `{"src_path":"a.txt","dst_path":"in/a.txt","dst_bucket":"b2"}`, status: http.StatusConflict},
+		{name: "move", target: "/cloud/b1/file/move", body: `{"src_path":"a.txt","dst_path":"a.txt","dst_bucket":"b2"}`, status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPost, test.target, strings.NewReader(test.body), nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	for _, filePath := range []string{"in/a.txt", "a.txt"} {
+		if _, err := s.cloud.Cloud.StatFile(ctx, "b2", filePath); err != nil {
+			t.Fatalf("file %s is missing in other bucket: %v", filePath, err)
+		}
+	}
+	if _, err := s.cloud.Cloud.StatFile(ctx, "b1", "a.txt"); err == nil {
+		t.Fatal("moved file still exists")
+	}
+}
+
+func TestRemoveBucket(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	for _, bucket := range []string{"b2", "b3"} {
+		if err := s.cloud.Cloud.CreateBucket(ctx, bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.uploadText(t, "a.txt", "a")
+	for _, bucket := range []string{"b2", "b3"} {
+		if err := s.cloud.Cloud.UploadFile(ctx, bucket, "a.txt", strings.NewReader("a"), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+	}{
+		{name: "not empty", method: http.MethodDelete, target: "/cloud/b1", status: http.StatusConflict},
+		{name: "purge", method: http.MethodPost, target: "/cloud/b2/purge", status: http.StatusOK},
+		{name: "force", method: http.MethodDelete, target: "/cloud/b3?force=true", status: http.StatusOK},
+		{name: "missing", method: http.MethodDelete, target: "/cloud/b9?force=true", status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, test.method, test.target, nil, nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	for bucket, expected := range map[string]bool{"b1": true, "b2": true, "b3": false} {
+		if exist, _ := s.cloud.Cloud.IsBucketExist(ctx, bucket); exist != expected {
+			t.Fatalf("bucket %s exists: %v", bucket, exist)
+		}
+	}
+	if _, err := s.cloud.Cloud.StatFile(ctx, "b2", "a.txt"); err == nil {
+		t.Fatal("file of purged bucket still exists")
+	}
+}
+
+func TestSetFileExpiry(t *testing.T) {
+	s := newTestServer(t)
+
+	body := multipartBody(map[string]string{"a.txt": "a"}, []string{"a.txt"}, false)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	target := "/cloud/b1/file/upload?directory=docs&expired=" + expiresAt.Format(time.RFC3339)
+	headers := map[string]string{"Content-Type": "multipart/form-data; boundary=" + testBoundary}
+	if rec := s.serve(t, http.MethodPut, target, body, headers); rec.Code != http.StatusOK {
+		t.Fatalf("failed to upload file: %s", rec.Body.String())
+	}
+
+	item, err := s.cloud.Cloud.StatFile(context.Background(), "b1", "docs/a.txt")
+	if err != nil || item.ExpiresAt == nil || !item.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("uploaded file has expiry %+v, error %v", item, err)
+	}
+
+	passed := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		expires bool
+	}{
+		{name: "passed expiry", body: `{"file_name":"docs/a.txt","expires_at":"` + passed + `"}`, status: http.StatusBadRequest, expires: true},
+		{name: "missing file", body: `{"file_name":"docs/b.txt","expires_at":null}`, status: http.StatusBadRequest, expires: true},
+		{name: "cleared expiry", body: `{"file_name":"docs/a.txt","expires_at":null}`, status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPut, "/cloud/b1/file/expiry", strings.NewReader(test.body), nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+
+			item, err := s.cloud.Cloud.StatFile(context.Background(), "b1", "docs/a.txt")
+			if err != nil || (item.ExpiresAt != nil) != test.expires {
+				t.Fatalf("file has expiry %v, error %v", item.ExpiresAt, err)
+			}
+		})
+	}
+}
@@ -2,9 +2,18 @@ package httpserv
 
 import (
 	"context"
-	"docs-hub/internal/cloud"
+	"fmt"
+	"net"
+	"net/http"
 
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/audit"
+	"docs-hub/internal/auth"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
 	"docs-hub/internal/server"
+	"docs-hub/internal/share"
+	"docs-hub/internal/trash"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
@@ -13,35 +22,114 @@ import (
 )
 
 type ServerHttp struct {
-	config *server.Config
-	cloud  *cloud.DocumentHub
-	server *echo.Echo
+	config      *server.Config
+	cloud       *cloud.DocumentHub
+	trash       *trash.Trash
+	auth        *auth.Authenticator
+	policy      *rbac.Policy
+	apiKeys     *apikey.Store
+	audit       *audit.Log
+	shares      *share.Store
+	uploadLinks *share.UploadStore
+	server      *echo.Echo
 }
 
-func Init(conf *server.Config, cloud *cloud.DocumentHub) *server.Server {
+func Init(
+	conf *server.Config,
+	cloud *cloud.DocumentHub,
+	trash *trash.Trash,
+	auth *auth.Authenticator,
+	policy *rbac.Policy,
+	apiKeys *apikey.Store,
+	auditLog *audit.Log,
+	shares *share.Store,
+	uploadLinks *share.UploadStore,
+) *server.Server {
 	httpServer := &ServerHttp{
-		config: conf,
-		cloud:  cloud,
-		server: echo.New(),
+		config:      conf,
+		cloud:       cloud,
+		trash:       trash,
+		auth:        auth,
+		policy:      policy,
+		apiKeys:     apiKeys,
+		audit:       auditLog,
+		shares:      shares,
+		uploadLinks: uploadLinks,
+		server:      echo.New(),
 	}
 
 	return &server.Server{Server: httpServer}
 }
 
-func (s *ServerHttp) setupServer() {
+func (s *ServerHttp) setupServer() error {
 	s.server = echo.New()
 
-	s.server.Use(middleware.CORS())
+	ipExtractor, err := newIPExtractor(s.config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	s.server.IPExtractor = ipExtractor
+
+	s.server.Use(middleware.RequestID())
+	if len(s.config.AllowOrigins) > 0 {
+		s.server.Use(middleware.CORSWithConfig(middleware.CORSConfig{AllowOrigins: s.config.AllowOrigins}))
+	}
 	s.server.Use(middleware.Recover())
 	s.server.Use(InitLogger(s.config))
+	s.server.Use(s.Audit)
+	s.server.Use(s.Authenticate)
 
 	_ = s.CreateCloudGroup()
+	_ = s.CreateAdminGroup()
 
+	s.server.GET("/health", s.Health)
+	s.server.GET("/s/:token", s.DownloadShareLink)
+	s.server.POST("/s/:token", s.DownloadShareLink)
+	s.server.GET("/s/:token/zip", s.DownloadShareLinkZip)
+	s.server.POST("/s/:token/zip", s.DownloadShareLinkZip)
+	s.server.GET("/s/:token/file/*", s.DownloadShareLinkFile)
+	s.server.POST("/s/:token/file/*", s.DownloadShareLinkFile)
+	s.server.GET("/u/:token", s.GetUploadLinkInfo)
+	s.server.POST("/u/:token", s.UploadByLink)
 	s.server.GET("/swagger/*", echoSwagger.WrapHandler)
+
+	if proxy, ok := s.cloud.Cloud.(cloud.IPresignProxy); ok {
+		if handler := proxy.PresignProxy(); handler != nil {
+			proxyHandler := echo.WrapHandler(http.StripPrefix(cloud.PresignProxyPath, handler))
+			s.server.Any(cloud.PresignProxyPath+"/*", proxyHandler)
+		}
+	}
+
+	return nil
+}
+
+// newIPExtractor trusts X-Forwarded-For header only from given proxies,
+// client could put any address into it otherwise.
+func newIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	trustOptions := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %s: %w", proxy, err)
+		}
+		trustOptions = append(trustOptions, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(trustOptions...), nil
 }
 
 func (s *ServerHttp) Start(_ context.Context) error {
-	s.setupServer()
+	if err := s.setupServer(); err != nil {
+		return err
+	}
 	return s.server.Start(s.config.Address)
 }
 
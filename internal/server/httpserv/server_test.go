@@ -0,0 +1,212 @@
+package httpserv
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/apikey"
+	"docs-hub/internal/audit"
+	"docs-hub/internal/auth"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/cloud/inmemory"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/server"
+	"docs-hub/internal/share"
+	"docs-hub/internal/trash"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	testAuthSecret = "test"
+	testBaseURL    = "https://docs.example.com"
+)
+
+// newTestServer returns server on in-memory cloud with bucket b1, trash and
+// share links enabled, authentication and access control disabled.
+func newTestServer(t *testing.T) *ServerHttp {
+	t.Helper()
+
+	return buildTestServer(t, &auth.Config{}, &rbac.Config{})
+}
+
+// newAccessTestServer returns test server which requires HS256 bearer
+// tokens and grants access by bindings only, see bearer.
+func newAccessTestServer(t *testing.T, bindings ...*rbac.Binding) *ServerHttp {
+	t.Helper()
+
+	data, err := json.Marshal(map[string][]*rbac.Binding{"bindings": bindings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	if err = os.WriteFile(policyFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	authConfig := &auth.Config{Enabled: true, Algorithm: auth.AlgorithmHS256, Secret: testAuthSecret}
+	return buildTestServer(t, authConfig, &rbac.Config{Enabled: true, PolicyFile: policyFile})
+}
+
+// bearer returns headers of request made by subject with token
+// accepted by newAccessTestServer.
+func bearer(t *testing.T, subject string, groups ...string) map[string]string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":    subject,
+		"groups": groups,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString([]byte(testAuthSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]string{echo.HeaderAuthorization: "Bearer " + signed}
+}
+
+func buildTestServer(t *testing.T, authConfig *auth.Config, rbacConfig *rbac.Config) *ServerHttp {
+	t.Helper()
+
+	hub := inmemory.New(&cloud.CloudConfig{ShareSecret: "test"})
+	if err := hub.Cloud.CreateBucket(context.Background(), "b1"); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := auth.New(authConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := rbac.New(rbacConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiKeys, err := apikey.New(&apikey.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog, err := audit.New(&audit.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storeDir := t.TempDir()
+	sharesConfig := &share.Config{
+		Enabled:             true,
+		StoreFile:           filepath.Join(storeDir, "links.json"),
+		UploadStoreFile:     filepath.Join(storeDir, "upload-links.json"),
+		BaseURL:             testBaseURL,
+		DefaultExpiry:       time.Hour,
+		MaxExpiry:           24 * time.Hour,
+		MaxPasswordAttempts: 3,
+		PasswordLockout:     time.Hour,
+	}
+	shares, err := share.New(sharesConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = shares.Close() })
+	uploadLinks, err := share.NewUploadStore(sharesConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := &ServerHttp{
+		config:      &server.Config{LoggerLevel: "INFO"},
+		cloud:       hub,
+		trash:       trash.New(&trash.Config{Enabled: true}, hub),
+		auth:        authenticator,
+		policy:      policy,
+		apiKeys:     apiKeys,
+		audit:       auditLog,
+		shares:      shares,
+		uploadLinks: uploadLinks,
+	}
+	if err = httpServer.setupServer(); err != nil {
+		t.Fatal(err)
+	}
+
+	return httpServer
+}
+
+func (s *ServerHttp) serve(t *testing.T, method, target string, body io.Reader, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, body)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	rec := httptest.NewRecorder()
+	s.server.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *ServerHttp) uploadText(t *testing.T, filePath, content string) {
+	t.Helper()
+
+	err := s.cloud.Cloud.UploadFile(context.Background(), "b1", filePath, strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *ServerHttp) readText(t *testing.T, filePath string) string {
+	t.Helper()
+
+	fileData, err := s.cloud.Cloud.DownloadFile(context.Background(), "b1", filePath)
+	if err != nil {
+		t.Fatalf("failed to download %s: %v", filePath, err)
+	}
+	defer func() { _ = fileData.Close() }()
+
+	data, _ := io.ReadAll(fileData)
+	return string(data)
+}
+
+// proxyCloud is cloud which forwards presigned URLs by handler.
+type proxyCloud struct {
+	cloud.ICloud
+	handler http.Handler
+}
+
+func (c *proxyCloud) PresignProxy() http.Handler {
+	return c.handler
+}
+
+func TestPresignProxyRoute(t *testing.T) {
+	s := newAccessTestServer(t)
+
+	var proxiedPath string
+	s.cloud = &cloud.DocumentHub{Cloud: &proxyCloud{
+		ICloud: s.cloud.Cloud,
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxiedPath = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	}}
+	if err := s.setupServer(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Presigned URLs are opened without token of docs-hub.
+	rec := s.serve(t, http.MethodPut, cloud.PresignProxyPath+"/b1/in/a.txt?X-Amz-Signature=abc", strings.NewReader("a"), nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if proxiedPath != "/b1/in/a.txt" {
+		t.Fatalf("proxy gets path %s", proxiedPath)
+	}
+}
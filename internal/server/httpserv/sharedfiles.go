@@ -0,0 +1,366 @@
+package httpserv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"html/template"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/share"
+	"github.com/labstack/echo/v4"
+)
+
+// shareZipName is name of archive of set of files which have no common folder.
+const shareZipName = "shared-files.zip"
+
+var shareListingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { padding: 0.3em 1em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .ExpiresAt}}<p>Link expires at {{.ExpiresAt.UTC.Format "2006-01-02 15:04 MST"}}</p>{{end}}
+{{if .Files}}{{if .Protected}}<form method="post">
+<p><input type="password" name="password" placeholder="Password"></p>
+<p><button type="submit" formaction="{{.ZipURL}}">Download all as ZIP</button></p>
+{{else}}<p><a href="{{.ZipURL}}">Download all as ZIP</a></p>
+{{end}}<table>
+<tr><th>File</th><th>Size</th><th>Modified</th></tr>
+{{range .Files}}<tr><td>{{if $.Protected}}<button type="submit" formaction="{{.URL}}">{{.Name}}</button>{{else}}<a href="{{.URL}}">{{.Name}}</a>{{end}}</td><td>{{.Size}} B</td><td>{{.Modified.UTC.Format "2006-01-02 15:04"}}</td></tr>
+{{end}}</table>
+{{if .Protected}}</form>
+{{end}}{{else}}<p>There are no files.</p>
+{{end}}</body>
+</html>
+`))
+
+// shareListingPage lists files of link, files of protected link are
+// downloaded by form which posts password typed into page again.
+type shareListingPage struct {
+	Title     string
+	ExpiresAt *time.Time
+	Protected bool
+	ZipURL    string
+	Files     []*shareListingFile
+}
+
+type shareListingFile struct {
+	Name     string
+	URL      string
+	Size     int64
+	Modified time.Time
+}
+
+// ShareFiles
+// @Summary Get share URL for folder or set of files
+// @Description Get managed share link of folder or set of files which expires after given seconds.
+// @Description Link opens page listing shared files which are downloaded one by one or as ZIP.
+// @Description Exactly one of folder and file_names is shared, share links must be enabled.
+// @Description Link without expired_secs expires after default expiry of share links.
+// @ID share-files
+// @Tags share
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name to share files"
+// @Param jsonQuery body ShareFilesForm true "Parameters to share files"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	404 {object} BadRequestForm "File does not exist"
+// @Failure	500 {object} ServerErrorForm "Base URL of links is not configured"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/files/share [post]
+func (s *ServerHttp) ShareFiles(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.shares.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	jsonForm := &ShareFilesForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if jsonForm.ExpiredSecs < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "expired_secs must not be negative")
+	}
+
+	params := &share.LinkParams{Bucket: bucket, FilePaths: jsonForm.FileNames}
+	if jsonForm.Folder != "" {
+		params.Folder = cloud.FolderKey(jsonForm.Folder)
+	}
+
+	if expired := time.Second * time.Duration(jsonForm.ExpiredSecs); expired > 0 {
+		expiresAt := time.Now().Add(expired)
+		params.ExpiresAt = &expiresAt
+	}
+
+	response, err := s.createShareLink(c, params)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, createStatusResponse(200, response.URL))
+}
+
+// DownloadShareLinkFile
+// @Summary Download file of shared folder or set of files
+// @Description Download single file listed by share link, the route does not require authentication
+// @ID download-share-link-file
+// @Tags share
+// @Accept x-www-form-urlencoded
+// @Produce octet-stream
+// @Param token path string true "Share link token"
+// @Param path path string true "Shared file path"
+// @Param password formData string false "Share link password"
+// @Param X-Share-Password header string false "Share link password"
+// @Success 200 {file} io.Writer "Ok"
+// @Failure	400 {object} BadRequestForm "Invalid file path"
+// @Failure	401 {object} BadRequestForm "Password is required or wrong"
+// @Failure	404 {object} BadRequestForm "Share link or file does not exist"
+// @Failure	410 {object} BadRequestForm "Share link is revoked, expired or exhausted"
+// @Failure	429 {object} BadRequestForm "Share link is locked after wrong passwords"
+// @Router /s/{token}/file/{path} [get]
+// @Router /s/{token}/file/{path} [post]
+func (s *ServerHttp) DownloadShareLinkFile(c echo.Context) error {
+	link, err := s.checkShareLink(c)
+	if err != nil {
+		return err
+	}
+
+	filePath, err := pathParam(c)
+	if err == nil {
+		err = cloud.CheckFilePath(filePath)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if !link.Contains(filePath) {
+		return echo.NewHTTPError(http.StatusNotFound, "file is not shared by link")
+	}
+
+	return s.downloadShareLinkFile(c, link, filePath)
+}
+
+// DownloadShareLinkZip
+// @Summary Download shared files as ZIP
+// @Description Download all files shared by link as single ZIP archive, it is counted as one
+// @Description download. Archive is limited by number and total size of files by config.
+// @Description The route does not require authentication.
+// @ID download-share-link-zip
+// @Tags share
+// @Accept x-www-form-urlencoded
+// @Produce application/zip
+// @Param token path string true "Share link token"
+// @Param password formData string false "Share link password"
+// @Param X-Share-Password header string false "Share link password"
+// @Success 200 {file} io.Writer "Ok"
+// @Failure	401 {object} BadRequestForm "Password is required or wrong"
+// @Failure	404 {object} BadRequestForm "Share link does not exist or has no files"
+// @Failure	410 {object} BadRequestForm "Share link is revoked, expired or exhausted"
+// @Failure	413 {object} BadRequestForm "Shared files exceed limits of archive"
+// @Failure	429 {object} BadRequestForm "Share link is locked after wrong passwords"
+// @Failure	500 {object} ServerErrorForm "Shared files can not be listed"
+// @Router /s/{token}/zip [get]
+// @Router /s/{token}/zip [post]
+func (s *ServerHttp) DownloadShareLinkZip(c echo.Context) error {
+	link, err := s.checkShareLink(c)
+	if err != nil {
+		return err
+	}
+
+	event := auditOperation(c, audit.OperationDownload, link.Bucket, link.SharedPath())
+
+	ctx := c.Request().Context()
+	files, err := s.shareLinkFiles(ctx, link)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if len(files) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "there are no files shared by link")
+	}
+
+	// Files are checked by their listing before download is counted and
+	// response is committed, archive opens them one by one while writing.
+	var totalSize int64
+	for _, item := range files {
+		totalSize += item.Size
+	}
+	if err = s.shares.CheckArchive(len(files), totalSize); err != nil {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	}
+
+	if err = s.shares.Count(link.Bucket, link.ID); err != nil {
+		return shareLinkError(err)
+	}
+
+	zipName := shareZipName
+	if link.Folder != "" {
+		zipName = path.Base(link.Folder) + ".zip"
+	}
+
+	header := c.Response().Header()
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": zipName})
+	header.Set(echo.HeaderContentDisposition, disposition)
+	header.Set(echo.HeaderContentType, "application/zip")
+	c.Response().WriteHeader(http.StatusOK)
+
+	// Response is already sent, so archive is left truncated on failure,
+	// error is logged and audited but not returned to be written into it.
+	zipWriter := zip.NewWriter(c.Response())
+	for _, item := range files {
+		if err = s.writeZipEntry(ctx, zipWriter, link, item); err != nil {
+			log.Println("failed to write shared file into zip: ", item.FileName, err)
+			event.Error = err.Error()
+			return nil
+		}
+	}
+
+	if err = zipWriter.Close(); err != nil {
+		log.Println("failed to close zip of shared files: ", link.ID, err)
+		event.Error = err.Error()
+	}
+
+	return nil
+}
+
+// writeZipEntry streams file into archive under its path relative to shared
+// folder, file is opened only while it is written.
+func (s *ServerHttp) writeZipEntry(ctx context.Context, zipWriter *zip.Writer, link *share.Link, item *cloud.StorageItem) error {
+	fileData, err := s.cloud.Cloud.DownloadFile(ctx, link.Bucket, item.FileName)
+	if err != nil {
+		return err
+	}
+	defer closeFileData(item.FileName, fileData)
+
+	entryHeader := &zip.FileHeader{
+		Name:     shareEntryName(link, item.FileName),
+		Method:   zip.Deflate,
+		Modified: item.LastModified,
+	}
+
+	entry, err := zipWriter.CreateHeader(entryHeader)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(entry, fileData)
+	return err
+}
+
+// renderShareListing renders page listing files shared by link.
+func (s *ServerHttp) renderShareListing(c echo.Context, link *share.Link) error {
+	ctx := c.Request().Context()
+	files, err := s.shareLinkFiles(ctx, link)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	baseURL, err := s.linkBaseURL()
+	if err != nil {
+		return err
+	}
+
+	linkURL := baseURL + "/s/" + c.Param("token")
+	page := &shareListingPage{
+		Title:     "Shared files",
+		ExpiresAt: link.ExpiresAt,
+		Protected: link.HasPassword,
+		ZipURL:    linkURL + "/zip",
+		Files:     make([]*shareListingFile, len(files)),
+	}
+	if link.Folder != "" {
+		page.Title = path.Base(link.Folder)
+	}
+
+	for index, item := range files {
+		page.Files[index] = &shareListingFile{
+			Name:     shareEntryName(link, item.FileName),
+			URL:      linkURL + "/file/" + escapeFilePath(item.FileName),
+			Size:     item.Size,
+			Modified: item.LastModified,
+		}
+	}
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	response.WriteHeader(http.StatusOK)
+	return shareListingTemplate.Execute(response, page)
+}
+
+// shareLinkFiles returns files shared by link, files of set
+// which have been removed since link was created are skipped.
+func (s *ServerHttp) shareLinkFiles(ctx context.Context, link *share.Link) ([]*cloud.StorageItem, error) {
+	if link.Folder != "" {
+		items, err := cloud.ListFolder(ctx, s.cloud.Cloud, link.Bucket, link.Folder)
+		if err != nil {
+			return nil, err
+		}
+
+		files := make([]*cloud.StorageItem, 0, len(items))
+		for _, item := range items {
+			if !cloud.IsFolderKey(item.FileName) {
+				files = append(files, item)
+			}
+		}
+		cloud.SortFiles(files, cloud.SortByName, false)
+		return files, nil
+	}
+
+	filePaths := link.FilePaths
+	if link.FilePath != "" {
+		filePaths = []string{link.FilePath}
+	}
+
+	files := make([]*cloud.StorageItem, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		fileInfo, err := s.cloud.Cloud.StatFile(ctx, link.Bucket, filePath)
+		if err != nil {
+			continue
+		}
+		fileInfo.FileName = filePath
+		files = append(files, fileInfo)
+	}
+
+	return files, nil
+}
+
+// shareEntryName returns name of shared file relative to shared folder.
+func shareEntryName(link *share.Link, filePath string) string {
+	if link.Folder != "" {
+		return strings.TrimPrefix(filePath, link.Folder)
+	}
+
+	return filePath
+}
+
+// escapeFilePath escapes every segment of file path to be put into URL.
+func escapeFilePath(filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for index, segment := range segments {
+		segments[index] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
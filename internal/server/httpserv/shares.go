@@ -0,0 +1,369 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/share"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerSharePassword = "X-Share-Password"
+	sharePasswordField  = "password"
+)
+
+var sharePasswordTemplate = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Protected link</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+</style>
+</head>
+<body>
+<h1>Protected link</h1>
+{{if .Error}}<p>{{.Error}}</p>{{end}}
+<form method="post">
+<input type="password" name="password" placeholder="Password" autofocus>
+<button type="submit">Open</button>
+</form>
+</body>
+</html>
+`))
+
+type sharePasswordPage struct {
+	Error string
+}
+
+var (
+	errSharesDisabled     = errors.New("share links are disabled")
+	errLinkBaseURLMissing = errors.New("base URL of links is not configured")
+)
+
+// CreateShareLink
+// @Summary Create managed share link
+// @Description Create share link of file, folder or set of files served by docs-hub, link may
+// @Description be limited by expiry, number of downloads and password and can be revoked.
+// @Description Exactly one of file_name, folder and file_names is shared. Link token is returned
+// @Description only once, recipients download file or list shared files by GET /s/{token}.
+// @ID create-share-link
+// @Tags share
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of shared file"
+// @Param jsonQuery body CreateShareLinkForm true "File and limits of link"
+// @Success 200 {object} ShareLinkCreatedForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	404 {object} BadRequestForm "File does not exist"
+// @Failure	500 {object} ServerErrorForm "Base URL of links is not configured"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/shares [post]
+func (s *ServerHttp) CreateShareLink(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.shares.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	jsonForm := &CreateShareLinkForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	params := &share.LinkParams{
+		Bucket:       bucket,
+		FilePath:     jsonForm.FileName,
+		FilePaths:    jsonForm.FileNames,
+		ExpiresAt:    jsonForm.ExpiresAt,
+		MaxDownloads: jsonForm.MaxDownloads,
+		Password:     jsonForm.Password,
+	}
+	if jsonForm.Folder != "" {
+		params.Folder = cloud.FolderKey(jsonForm.Folder)
+	}
+
+	response, err := s.createShareLink(c, params)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, response)
+}
+
+// createShareLink creates link of existing files or folder
+// for caller who is able to read all of them.
+func (s *ServerHttp) createShareLink(c echo.Context, params *share.LinkParams) (*ShareLinkCreatedForm, error) {
+	sharedPath := params.FilePath
+	if params.Folder != "" {
+		sharedPath = params.Folder
+	}
+
+	auditOperation(c, audit.OperationShare, params.Bucket, sharedPath)
+	if err := s.checkSharedFiles(c, params); err != nil {
+		return nil, err
+	}
+
+	baseURL, err := s.linkBaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	params.Creator = requestActor(c)
+	link, token, err := s.shares.Create(params)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return &ShareLinkCreatedForm{Link: link, Token: token, URL: baseURL + "/s/" + token}, nil
+}
+
+// checkSharedFiles checks that caller reads files to share and that they exist,
+// folder is not required to exist because files may be added to it later.
+func (s *ServerHttp) checkSharedFiles(c echo.Context, params *share.LinkParams) error {
+	if params.Folder != "" {
+		return s.authorize(c, params.Bucket, params.Folder, rbac.RoleReader)
+	}
+
+	filePaths := params.FilePaths
+	if params.FilePath != "" {
+		filePaths = []string{params.FilePath}
+	}
+
+	ctx := c.Request().Context()
+	for _, filePath := range filePaths {
+		if err := s.authorize(c, params.Bucket, filePath, rbac.RoleReader); err != nil {
+			return err
+		}
+
+		if _, err := s.cloud.Cloud.StatFile(ctx, params.Bucket, filePath); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// linkBaseURL returns configured base address of links, address of request
+// is never used because client could put any host into link by Host header.
+func (s *ServerHttp) linkBaseURL() (string, error) {
+	baseURL := strings.TrimSuffix(s.shares.BaseURL(), "/")
+	if baseURL == "" {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, errLinkBaseURLMissing.Error())
+	}
+
+	return baseURL, nil
+}
+
+// GetShareLinks
+// @Summary Get share links of bucket
+// @Description Get managed share links of bucket including revoked ones, expired links are
+// @Description pruned. Bucket admins get all links, other callers get links they have created.
+// @ID get-share-links
+// @Tags share
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Success 200 {object} ShareLinksForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/shares [get]
+func (s *ServerHttp) GetShareLinks(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.shares.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	isAdmin := s.allows(c, bucket, "", rbac.RoleAdmin)
+	actor := requestActor(c)
+
+	links := make([]*share.Link, 0)
+	for _, link := range s.shares.Links(bucket) {
+		if isAdmin || link.Creator == actor {
+			links = append(links, link)
+		}
+	}
+
+	return c.JSON(200, &ShareLinksForm{Links: links})
+}
+
+// RevokeShareLink
+// @Summary Revoke share link
+// @Description Revoke managed share link, only its creator or bucket admin can revoke it
+// @ID revoke-share-link
+// @Tags share
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param id path string true "Share link id"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	404 {object} BadRequestForm "Share link does not exist"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/shares/{id} [delete]
+func (s *ServerHttp) RevokeShareLink(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.shares.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	event := auditOperation(c, audit.OperationRevokeShare, bucket, "")
+	link, err := s.shares.Link(bucket, c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	event.Path = link.SharedPath()
+
+	if link.Creator != requestActor(c) {
+		if err = s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	if err = s.shares.Revoke(bucket, link.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// DownloadShareLink
+// @Summary Download file by share link
+// @Description Download file shared by managed link, the route does not require authentication.
+// @Description Link of folder or set of files renders page listing them, listing is not counted
+// @Description as download. Password of protected link is passed by X-Share-Password header
+// @Description or by password field of POST form, browser is asked for it by form page.
+// @Description Link is locked for a while after several wrong passwords.
+// @ID download-share-link
+// @Tags share
+// @Accept x-www-form-urlencoded
+// @Produce octet-stream,html
+// @Param token path string true "Share link token"
+// @Param password formData string false "Share link password"
+// @Param X-Share-Password header string false "Share link password"
+// @Success 200 {file} io.Writer "Ok"
+// @Failure	401 {object} BadRequestForm "Password is required or wrong"
+// @Failure	404 {object} BadRequestForm "Share link does not exist"
+// @Failure	410 {object} BadRequestForm "Share link is revoked, expired or exhausted"
+// @Failure	429 {object} BadRequestForm "Share link is locked after wrong passwords"
+// @Router /s/{token} [get]
+// @Router /s/{token} [post]
+func (s *ServerHttp) DownloadShareLink(c echo.Context) error {
+	link, err := s.checkShareLink(c)
+	httpErr := &echo.HTTPError{}
+	if errors.As(err, &httpErr) && httpErr.Code == http.StatusUnauthorized && acceptsHTML(c) {
+		return renderSharePasswordForm(c, httpErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if link.IsCollection() {
+		return s.renderShareListing(c, link)
+	}
+
+	return s.downloadShareLinkFile(c, link, link.FilePath)
+}
+
+// checkShareLink returns available link of request token checking password
+// passed by header or POST form. Password is never taken from query, which
+// would leave it in browser history and logs of proxies.
+func (s *ServerHttp) checkShareLink(c echo.Context) (*share.Link, error) {
+	if !s.shares.IsEnabled() {
+		return nil, echo.NewHTTPError(http.StatusNotFound, errSharesDisabled.Error())
+	}
+
+	password := c.Request().Header.Get(headerSharePassword)
+	if password == "" && c.Request().Method == http.MethodPost {
+		password = c.Request().PostFormValue(sharePasswordField)
+	}
+
+	link, err := s.shares.Check(c.Param("token"), password)
+	if err != nil {
+		return nil, shareLinkError(err)
+	}
+
+	return link, nil
+}
+
+// renderSharePasswordForm asks browser for password of link, password
+// is posted back to the same address by form.
+func renderSharePasswordForm(c echo.Context, httpErr *echo.HTTPError) error {
+	page := &sharePasswordPage{}
+	if c.Request().Method == http.MethodPost {
+		page.Error = fmt.Sprint(httpErr.Message)
+	}
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	response.WriteHeader(http.StatusUnauthorized)
+	return sharePasswordTemplate.Execute(response, page)
+}
+
+// acceptsHTML reports whether request is made by browser.
+func acceptsHTML(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMETextHTML)
+}
+
+// shareLinkError maps errors of share store into http errors.
+func shareLinkError(err error) error {
+	switch {
+	case errors.Is(err, share.ErrLinkNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, share.ErrLinkUnavailable):
+		return echo.NewHTTPError(http.StatusGone, err.Error())
+	case errors.Is(err, share.ErrPasswordNeeded), errors.Is(err, share.ErrWrongPassword):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, share.ErrLinkLocked):
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// downloadShareLinkFile counts download of link and streams file shared by it.
+func (s *ServerHttp) downloadShareLinkFile(c echo.Context, link *share.Link, filePath string) error {
+	auditOperation(c, audit.OperationDownload, link.Bucket, filePath)
+
+	ctx := c.Request().Context()
+	fileInfo, err := s.cloud.Cloud.StatFile(ctx, link.Bucket, filePath)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err = s.shares.Count(link.Bucket, link.ID); err != nil {
+		return shareLinkError(err)
+	}
+
+	fileData, err := s.cloud.Cloud.DownloadFile(ctx, link.Bucket, filePath)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	defer closeFileData(filePath, fileData)
+
+	contentType := fileInfo.ContentType
+	if contentType == "" || contentType == echo.MIMEOctetStream {
+		contentType = cloud.ContentTypeOf(filePath)
+	}
+
+	header := c.Response().Header()
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(filePath)})
+	header.Set(echo.HeaderContentDisposition, disposition)
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(fileInfo.Size, 10))
+	header.Set(echo.HeaderLastModified, fileInfo.LastModified.UTC().Format(http.TimeFormat))
+	return c.Stream(http.StatusOK, contentType, fileData)
+}
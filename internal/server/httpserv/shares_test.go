@@ -0,0 +1,263 @@
+package httpserv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/share"
+	"github.com/labstack/echo/v4"
+)
+
+func createLink(t *testing.T, s *ServerHttp, form string) *ShareLinkCreatedForm {
+	t.Helper()
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/shares", strings.NewReader(form), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to create share link: %s", rec.Body.String())
+	}
+
+	created := &ShareLinkCreatedForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), created); err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
+
+func TestShareLinkLimits(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "docs/a.txt", "shared")
+
+	created := createLink(t, s, `{"file_name":"docs/a.txt","max_downloads":1,"password":"pw"}`)
+	if created.Link.ExpiresAt == nil {
+		t.Fatal("share link has been created without expiry")
+	}
+	if created.URL != testBaseURL+"/s/"+created.Token {
+		t.Fatalf("link URL %s is not built from base URL", created.URL)
+	}
+
+	// Steps run in order, failed attempts must not use up download.
+	steps := []struct {
+		name     string
+		target   string
+		password string
+		status   int
+		body     string
+	}{
+		{name: "no password", status: http.StatusUnauthorized},
+		{name: "password in query", target: "?password=pw", status: http.StatusUnauthorized},
+		{name: "wrong password", password: "guess", status: http.StatusUnauthorized},
+		{name: "download", password: "pw", status: http.StatusOK, body: "shared"},
+		{name: "exhausted", password: "pw", status: http.StatusGone},
+	}
+
+	for _, step := range steps {
+		headers := map[string]string{}
+		if step.password != "" {
+			headers[headerSharePassword] = step.password
+		}
+
+		rec := s.serve(t, http.MethodGet, "/s/"+created.Token+step.target, nil, headers)
+		if rec.Code != step.status {
+			t.Fatalf("%s: status %d, expected %d: %s", step.name, rec.Code, step.status, rec.Body.String())
+		}
+		if step.body != "" && rec.Body.String() != step.body {
+			t.Fatalf("%s: body %q", step.name, rec.Body.String())
+		}
+	}
+}
+
+func TestShareLinkPasswordForm(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "docs/a.txt", "a")
+	s.uploadText(t, "docs/b.txt", "b")
+
+	created := createLink(t, s, `{"folder":"docs","password":"pw"}`)
+	browser := map[string]string{echo.HeaderAccept: "text/html,application/xhtml+xml"}
+
+	rec := s.serve(t, http.MethodGet, "/s/"+created.Token, nil, browser)
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), `<form method="post">`) {
+		t.Fatalf("browser is not asked for password: status %d, %s", rec.Code, rec.Body.String())
+	}
+
+	post := func(target, password string) (int, string) {
+		req := map[string]string{
+			echo.HeaderAccept:      "text/html",
+			echo.HeaderContentType: echo.MIMEApplicationForm,
+		}
+		body := strings.NewReader(url.Values{"password": {password}}.Encode())
+		rec := s.serve(t, http.MethodPost, target, body, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	status, body := post("/s/"+created.Token, "guess")
+	if status != http.StatusUnauthorized || !strings.Contains(body, share.ErrWrongPassword.Error()) {
+		t.Fatalf("wrong password: status %d, %s", status, body)
+	}
+
+	status, body = post("/s/"+created.Token, "pw")
+	if status != http.StatusOK {
+		t.Fatalf("listing: status %d, %s", status, body)
+	}
+	fileURL := testBaseURL + "/s/" + created.Token + "/file/docs/a.txt"
+	if !strings.Contains(body, `formaction="`+fileURL+`"`) || strings.Contains(body, "pw") {
+		t.Fatalf("listing does not post password to file or contains it: %s", body)
+	}
+
+	if status, body = post("/s/"+created.Token+"/file/docs/a.txt", "pw"); status != http.StatusOK || body != "a" {
+		t.Fatalf("file: status %d, %q", status, body)
+	}
+}
+
+func TestShareLinkLockout(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "a.txt", "shared")
+
+	created := createLink(t, s, `{"file_name":"a.txt","password":"pw"}`)
+	for attempt := 1; attempt <= 3; attempt++ {
+		rec := s.serve(t, http.MethodGet, "/s/"+created.Token, nil, map[string]string{headerSharePassword: "guess"})
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: status %d", attempt, rec.Code)
+		}
+	}
+
+	rec := s.serve(t, http.MethodGet, "/s/"+created.Token, nil, map[string]string{headerSharePassword: "pw"})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("locked link: status %d, expected %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestShareFileExpiry(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "a.txt", "shared")
+
+	tests := []struct {
+		name   string
+		form   string
+		status int
+	}{
+		{name: "default expiry", form: `{"file_name":"a.txt"}`, status: http.StatusOK},
+		{name: "given expiry", form: `{"file_name":"a.txt","expired_secs":60}`, status: http.StatusOK},
+		{name: "negative expiry", form: `{"file_name":"a.txt","expired_secs":-1}`, status: http.StatusBadRequest},
+		{name: "missing file", form: `{"file_name":"b.txt"}`, status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPost, "/cloud/b1/file/share", strings.NewReader(test.form), nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	for _, link := range s.shares.Links("b1") {
+		if link.ExpiresAt == nil || link.ExpiresAt.After(time.Now().Add(time.Hour)) {
+			t.Fatalf("link %s expires at %v", link.ID, link.ExpiresAt)
+		}
+	}
+}
+
+func TestShareFileRequiresBaseURL(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "a.txt", "shared")
+
+	// Cloud signed URL is used while share links are disabled, it is
+	// relative to docs-hub and must not be completed by Host header.
+	shares, err := share.New(&share.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.shares = shares
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/file/share", strings.NewReader(`{"file_name":"a.txt"}`), nil)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestShareLinkZip(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "docs/a.txt", "a")
+	s.uploadText(t, "docs/sub/b.txt", "bb")
+	s.uploadText(t, "other.txt", "other")
+
+	created := createLink(t, s, `{"folder":"docs","max_downloads":1}`)
+	rec := s.serve(t, http.MethodGet, "/s/"+created.Token+"/zip", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string]string{}
+	for _, entry := range archive.File {
+		entryData, err := entry.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(entryData)
+		_ = entryData.Close()
+		entries[entry.Name] = string(data)
+	}
+	if !reflect.DeepEqual(entries, map[string]string{"a.txt": "a", "sub/b.txt": "bb"}) {
+		t.Fatalf("archive has entries %v", entries)
+	}
+
+	if rec = s.serve(t, http.MethodGet, "/s/"+created.Token+"/zip", nil, nil); rec.Code != http.StatusGone {
+		t.Fatalf("archive is downloaded twice: status %d", rec.Code)
+	}
+}
+
+func TestShareLinkZipLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		config share.Config
+		status int
+	}{
+		{name: "within limits", config: share.Config{MaxZipFiles: 2, MaxZipSize: 3}, status: http.StatusOK},
+		{name: "too many files", config: share.Config{MaxZipFiles: 1}, status: http.StatusRequestEntityTooLarge},
+		{name: "too large", config: share.Config{MaxZipSize: 2}, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.uploadText(t, "docs/a.txt", "a")
+			s.uploadText(t, "docs/b.txt", "bb")
+
+			test.config.Enabled = true
+			test.config.StoreFile = filepath.Join(t.TempDir(), "links.json")
+			test.config.BaseURL = testBaseURL
+			test.config.MaxExpiry = time.Hour
+			shares, err := share.New(&test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = shares.Close() })
+			s.shares = shares
+
+			created := createLink(t, s, `{"folder":"docs","max_downloads":1}`)
+			rec := s.serve(t, http.MethodGet, "/s/"+created.Token+"/zip", nil, nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+
+			// Rejected archive does not use up download of link.
+			if link := s.shares.Links("b1")[0]; link.Downloads != 0 && rec.Code != http.StatusOK {
+				t.Fatalf("rejected archive is counted: %d downloads", link.Downloads)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/trash"
+	"github.com/labstack/echo/v4"
+)
+
+// removeRole returns role required to remove object. Removal which bypasses
+// trash, or removes files kept into it, can not be undone, so it is left to admins.
+func removeRole(objPath string, isPermanent bool) rbac.Role {
+	if isPermanent || trash.IsTrashPath(objPath) {
+		return rbac.RoleAdmin
+	}
+
+	return rbac.RoleWriter
+}
+
+// GetTrash
+// @Summary Get trash entries of bucket
+// @Description Get paginated list of deleted files kept into trash of bucket, the oldest first.
+// @Description Only files which caller may read at their original paths are listed.
+// @ID get-trash
+// @Tags trash
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param limit query int false "Max entries count of page"
+// @Param cursor query string false "Continuation cursor from previous page"
+// @Success 200 {object} trash.EntriesPage "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/trash [get]
+func (s *ServerHttp) GetTrash(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	limit := defaultFilesPageLimit
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		value, err := strconv.Atoi(limitParam)
+		if err != nil || value <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit: "+limitParam)
+		}
+		limit = min(value, maxFilesPageLimit)
+	}
+
+	ctx := c.Request().Context()
+	// Entry is listed to callers which may read file at its original path.
+	page, err := s.trash.List(ctx, bucket, limit, c.QueryParam("cursor"), func(entry *trash.Entry) bool {
+		return s.allows(c, bucket, entry.OriginalPath, rbac.RoleReader)
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, page)
+}
+
+// RestoreTrash
+// @Summary Restore file from trash
+// @Description Move deleted file back to its original path, conflict policy is applied
+// @Description if another file has been stored there since deletion
+// @ID restore-trash
+// @Tags trash
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param id path string true "Trash entry id"
+// @Param jsonQuery body RestoreTrashForm false "Params to restore file"
+// @Success 200 {object} FileResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	409 {object} BadRequestForm "File already exists"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/trash/{id}/restore [post]
+func (s *ServerHttp) RestoreTrash(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	event := auditOperation(c, audit.OperationRestoreTrash, bucket, "")
+	jsonForm := &RestoreTrashForm{}
+	if c.Request().ContentLength != 0 {
+		decoder := json.NewDecoder(c.Request().Body)
+		if err := decoder.Decode(jsonForm); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := cloud.CheckConflictPolicy(jsonForm.OnConflict); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	entry, err := s.trash.GetEntry(ctx, bucket, c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	event.Path = entry.OriginalPath
+	if err = s.authorize(c, bucket, entry.OriginalPath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	filePath, err := s.trash.Restore(ctx, bucket, entry.ID, jsonForm.OnConflict)
+	event.Path = filePath
+	if errors.Is(err, cloud.ErrFileSkipped) {
+		return c.JSON(200, createFileResponse(200, "Skipped", filePath))
+	}
+	if errors.Is(err, cloud.ErrFileExists) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createFileResponse(200, "Ok", filePath))
+}
+
+// EmptyTrash
+// @Summary Empty trash of bucket
+// @Description Permanently remove all files kept into trash of bucket
+// @ID empty-trash
+// @Tags trash
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/trash [delete]
+func (s *ServerHttp) EmptyTrash(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	auditOperation(c, audit.OperationEmptyTrash, bucket, "")
+	if err := s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := s.trash.Empty(ctx, bucket); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
@@ -0,0 +1,117 @@
+package httpserv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/trash"
+)
+
+func TestTrashAccess(t *testing.T) {
+	ctx := context.Background()
+	s := newAccessTestServer(t,
+		&rbac.Binding{Subject: "alice", Bucket: "b1", Prefix: "public/", Role: rbac.RoleWriter},
+		&rbac.Binding{Subject: "root", Bucket: "b1", Role: rbac.RoleAdmin},
+	)
+	alice, root := bearer(t, "alice"), bearer(t, "root")
+
+	entries := make(map[string]*trash.Entry)
+	for _, filePath := range []string{"public/a.txt", "private/b.txt"} {
+		s.uploadText(t, filePath, filePath)
+		entry, err := s.trash.MoveToTrash(ctx, "b1", filePath, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[filePath] = entry
+	}
+
+	rec := s.serve(t, http.MethodGet, "/cloud/b1/trash", nil, alice)
+	page := &trash.EntriesPage{}
+	if err := json.Unmarshal(rec.Body.Bytes(), page); err != nil {
+		t.Fatalf("unexpected response %s: %v", rec.Body.String(), err)
+	}
+	if len(page.Items) != 1 || page.Items[0].OriginalPath != "public/a.txt" {
+		t.Fatalf("alice sees trash entries %+v", page.Items)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{
+			name:    "restore of denied path",
+			method:  http.MethodPost,
+			target:  "/cloud/b1/trash/" + entries["private/b.txt"].ID + "/restore",
+			headers: alice,
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "restore of allowed path",
+			method:  http.MethodPost,
+			target:  "/cloud/b1/trash/" + entries["public/a.txt"].ID + "/restore",
+			headers: alice,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "permanent file removal by writer",
+			method:  http.MethodDelete,
+			target:  "/cloud/b1/file/remove?permanent=true",
+			body:    `{"file_name":"public/a.txt"}`,
+			headers: alice,
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "trash file removal by writer",
+			method:  http.MethodDelete,
+			target:  "/cloud/b1/file/remove",
+			body:    `{"file_name":".trash/` + entries["private/b.txt"].ID + `.json"}`,
+			headers: alice,
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "permanent folder removal by writer",
+			method:  http.MethodDelete,
+			target:  "/cloud/b1/folder/remove?permanent=true",
+			body:    `{"folder_path":"public"}`,
+			headers: alice,
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "file removal into trash by writer",
+			method:  http.MethodDelete,
+			target:  "/cloud/b1/file/remove",
+			body:    `{"file_name":"public/a.txt"}`,
+			headers: alice,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "trash folder removal by admin",
+			method:  http.MethodDelete,
+			target:  "/cloud/b1/folder/remove",
+			body:    `{"folder_path":".trash"}`,
+			headers: root,
+			status:  http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, test.method, test.target, strings.NewReader(test.body), test.headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	if page, err := s.trash.List(ctx, "b1", 10, "", nil); err != nil || len(page.Items) != 0 {
+		t.Fatalf("trash is not emptied by admin: %+v, error %v", page, err)
+	}
+}
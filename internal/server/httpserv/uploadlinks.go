@@ -0,0 +1,393 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"docs-hub/internal/share"
+	"github.com/labstack/echo/v4"
+)
+
+var errFileTooLarge = errors.New("file exceeds max size of upload link")
+
+// sizeLimitReader fails upload once more than limit bytes have been read,
+// so that cloud discards file instead of storing it.
+type sizeLimitReader struct {
+	reader io.Reader
+	left   int64
+}
+
+func (r *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.left -= int64(n)
+	if r.left < 0 {
+		return n, errFileTooLarge
+	}
+
+	return n, err
+}
+
+// PresignUpload
+// @Summary Presign upload to cloud
+// @Description Sign upload which client sends directly to cloud. PUT uploads given file path
+// @Description and can be constrained by content type, POST form uploads file path or any file
+// @Description into folder and can be constrained by content type and max size.
+// @Description Clouds which can not presign uploads answer 400, use upload links for them.
+// @ID presign-upload
+// @Tags share
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name to upload into"
+// @Param jsonQuery body PresignUploadForm true "Upload constraints"
+// @Success 200 {object} cloud.PresignedUpload "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/presign-upload [post]
+func (s *ServerHttp) PresignUpload(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	presigner, ok := s.cloud.Cloud.(cloud.IUploadPresigner)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "cloud does not presign uploads, use upload links")
+	}
+
+	jsonForm := &PresignUploadForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	objPath := jsonForm.FilePath
+	if jsonForm.Folder != "" {
+		objPath = cloud.FolderKey(jsonForm.Folder)
+	}
+
+	auditOperation(c, audit.OperationPresignUpload, bucket, objPath)
+	if err := s.authorize(c, bucket, objPath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	policy := &cloud.UploadPolicy{
+		Method:      jsonForm.Method,
+		FilePath:    jsonForm.FilePath,
+		Folder:      jsonForm.Folder,
+		ContentType: jsonForm.ContentType,
+		MaxSize:     jsonForm.MaxSize,
+		Expired:     time.Second * time.Duration(jsonForm.ExpiredSecs),
+	}
+
+	ctx := c.Request().Context()
+	upload, err := presigner.PresignUpload(ctx, bucket, policy)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, upload)
+}
+
+// CreateUploadLink
+// @Summary Create upload link
+// @Description Create link which external contributor uploads files into folder by without
+// @Description account. Link may be limited by expiry, number of files and size of every file.
+// @Description Link token is returned only once, files are uploaded by POST /u/{token}.
+// @ID create-upload-link
+// @Tags share
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name to upload into"
+// @Param jsonQuery body CreateUploadLinkForm true "Folder and limits of link"
+// @Success 200 {object} UploadLinkCreatedForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	500 {object} ServerErrorForm "Base URL of links is not configured"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/upload-links [post]
+func (s *ServerHttp) CreateUploadLink(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.uploadLinks.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	jsonForm := &CreateUploadLinkForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	folderKey := cloud.FolderKey(jsonForm.Folder)
+	auditOperation(c, audit.OperationCreateUploadLink, bucket, folderKey)
+	if err := s.authorize(c, bucket, folderKey, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if exist, err := s.cloud.Cloud.IsBucketExist(ctx, bucket); err != nil || !exist {
+		retErr := fmt.Errorf("specified bucket %s does not exist", bucket)
+		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
+	}
+
+	baseURL, err := s.linkBaseURL()
+	if err != nil {
+		return err
+	}
+
+	params := &share.UploadLinkParams{
+		Bucket:    bucket,
+		Folder:    folderKey,
+		Creator:   requestActor(c),
+		ExpiresAt: jsonForm.ExpiresAt,
+		MaxFiles:  jsonForm.MaxFiles,
+		MaxSize:   jsonForm.MaxSize,
+	}
+
+	link, token, err := s.uploadLinks.Create(params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, &UploadLinkCreatedForm{Link: link, Token: token, URL: baseURL + "/u/" + token})
+}
+
+// GetUploadLinks
+// @Summary Get upload links of bucket
+// @Description Get upload links of bucket including revoked and expired ones.
+// @Description Bucket admins get all links, other callers get links they have created.
+// @ID get-upload-links
+// @Tags share
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Success 200 {object} UploadLinksForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/upload-links [get]
+func (s *ServerHttp) GetUploadLinks(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.uploadLinks.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	isAdmin := s.allows(c, bucket, "", rbac.RoleAdmin)
+	actor := requestActor(c)
+
+	links := make([]*share.UploadLink, 0)
+	for _, link := range s.uploadLinks.Links(bucket) {
+		if isAdmin || link.Creator == actor {
+			links = append(links, link)
+		}
+	}
+
+	return c.JSON(200, &UploadLinksForm{Links: links})
+}
+
+// RevokeUploadLink
+// @Summary Revoke upload link
+// @Description Revoke upload link, only its creator or bucket admin can revoke it
+// @ID revoke-upload-link
+// @Tags share
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param id path string true "Upload link id"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	403 {object} BadRequestForm "Access denied"
+// @Failure	404 {object} BadRequestForm "Upload link does not exist"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/upload-links/{id} [delete]
+func (s *ServerHttp) RevokeUploadLink(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if !s.uploadLinks.IsEnabled() {
+		return echo.NewHTTPError(http.StatusBadRequest, errSharesDisabled.Error())
+	}
+
+	event := auditOperation(c, audit.OperationRevokeUploadLink, bucket, "")
+	link, err := s.uploadLinks.Link(bucket, c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	event.Path = link.Folder
+
+	if link.Creator != requestActor(c) {
+		if err = s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	if err = s.uploadLinks.Revoke(bucket, link.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// GetUploadLinkInfo
+// @Summary Get upload link limits
+// @Description Get folder and remaining limits of upload link, the route does not require authentication
+// @ID get-upload-link-info
+// @Tags share
+// @Produce json
+// @Param token path string true "Upload link token"
+// @Success 200 {object} UploadLinkInfoForm "Ok"
+// @Failure	404 {object} BadRequestForm "Upload link does not exist"
+// @Failure	410 {object} BadRequestForm "Upload link is revoked, expired or exhausted"
+// @Router /u/{token} [get]
+func (s *ServerHttp) GetUploadLinkInfo(c echo.Context) error {
+	link, err := s.openUploadLink(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, &UploadLinkInfoForm{
+		Folder:         link.Folder,
+		ExpiresAt:      link.ExpiresAt,
+		MaxSize:        link.MaxSize,
+		RemainingFiles: link.RemainingFiles(),
+	})
+}
+
+// UploadByLink
+// @Summary Upload files by upload link
+// @Description Upload files into folder of upload link, the route does not require authentication.
+// @Description Existing files are never replaced, uploaded file is renamed instead.
+// @ID upload-by-link
+// @Tags share
+// @Accept  multipart/form
+// @Produce json
+// @Param token path string true "Upload link token"
+// @Param files formData file true "Files multipart form"
+// @Success 200 {object} UploadFilesForm "Ok"
+// @Success 207 {object} UploadFilesForm "Some files have not been uploaded"
+// @Failure	400 {object} UploadFilesForm "No file has been uploaded"
+// @Failure	404 {object} BadRequestForm "Upload link does not exist"
+// @Failure	410 {object} BadRequestForm "Upload link is revoked, expired or exhausted"
+// @Router /u/{token} [post]
+func (s *ServerHttp) UploadByLink(c echo.Context) error {
+	link, err := s.openUploadLink(c)
+	if err != nil {
+		return err
+	}
+
+	multipartReader, err := c.Request().MultipartReader()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	token := c.Param("token")
+	results := make([]*UploadFileResult, 0)
+	ctx := c.Request().Context()
+	for {
+		part, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		fileName := path.Base(part.FileName())
+		if part.FormName() != "files" || fileName == "." || fileName == "/" {
+			_ = part.Close()
+			continue
+		}
+
+		filePath := cloud.JoinFilePath(link.Folder, fileName)
+		result := &UploadFileResult{FileName: fileName, FilePath: filePath}
+		results = append(results, result)
+
+		err = s.uploadByLink(c, token, link, result, part)
+		if err := part.Close(); err != nil {
+			log.Println("failed to close file part: ", fileName, err)
+		}
+
+		if err != nil {
+			result.Status = UploadStatusFailed
+			result.Error = err.Error()
+		} else {
+			result.Status = UploadStatusUploaded
+			if fileInfo, err := s.cloud.Cloud.StatFile(ctx, link.Bucket, result.FilePath); err == nil {
+				result.Size = fileInfo.Size
+				result.ETag = fileInfo.ETag
+			}
+		}
+
+		event := &audit.Event{
+			Operation: audit.OperationUpload,
+			Bucket:    link.Bucket,
+			Path:      result.FilePath,
+			Size:      result.Size,
+		}
+		s.recordAudit(c, event, err)
+	}
+
+	if len(results) == 0 {
+		err = fmt.Errorf("there are no files into multipart form")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	response := createUploadFilesResponse(results)
+	return c.JSON(response.Status, response)
+}
+
+// uploadByLink reserves file of upload link and uploads it
+// under free name, reserved file is released on failure.
+func (s *ServerHttp) uploadByLink(
+	c echo.Context,
+	token string,
+	link *share.UploadLink,
+	result *UploadFileResult,
+	data io.Reader,
+) error {
+	if _, err := s.uploadLinks.Reserve(token); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	filePath, err := cloud.ResolveConflict(ctx, s.cloud.Cloud, link.Bucket, result.FilePath, cloud.ConflictRename)
+	if err == nil {
+		result.FilePath = filePath
+		if link.MaxSize > 0 {
+			data = &sizeLimitReader{reader: data, left: link.MaxSize}
+		}
+		err = s.cloud.Cloud.UploadNewFile(ctx, link.Bucket, filePath, data, -1)
+	}
+
+	if err != nil {
+		if releaseErr := s.uploadLinks.Release(token); releaseErr != nil {
+			log.Println("failed to release file of upload link: ", link.ID, releaseErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// openUploadLink returns available upload link of request token.
+func (s *ServerHttp) openUploadLink(c echo.Context) (*share.UploadLink, error) {
+	if !s.uploadLinks.IsEnabled() {
+		return nil, echo.NewHTTPError(http.StatusNotFound, errSharesDisabled.Error())
+	}
+
+	link, err := s.uploadLinks.Open(c.Param("token"))
+	switch {
+	case errors.Is(err, share.ErrUploadLinkNotFound):
+		return nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, share.ErrLinkUnavailable):
+		return nil, echo.NewHTTPError(http.StatusGone, err.Error())
+	case err != nil:
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return link, nil
+}
@@ -0,0 +1,173 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-hub/internal/rbac"
+)
+
+func createUploadLink(t *testing.T, s *ServerHttp, form string, headers map[string]string) *UploadLinkCreatedForm {
+	t.Helper()
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/upload-links", strings.NewReader(form), headers)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to create upload link: %s", rec.Body.String())
+	}
+
+	created := &UploadLinkCreatedForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), created); err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
+
+func TestUploadByLink(t *testing.T) {
+	s := newTestServer(t)
+	s.uploadText(t, "in/a.txt", "existing")
+
+	created := createUploadLink(t, s, `{"folder":"in","max_files":2,"max_size":5}`, nil)
+	if created.URL != testBaseURL+"/u/"+created.Token || created.Link.Folder != "in/" {
+		t.Fatalf("unexpected created link %+v, URL %s", created.Link, created.URL)
+	}
+
+	info := func() *UploadLinkInfoForm {
+		rec := s.serve(t, http.MethodGet, "/u/"+created.Token, nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("link info: status %d: %s", rec.Code, rec.Body.String())
+		}
+		form := &UploadLinkInfoForm{}
+		if err := json.Unmarshal(rec.Body.Bytes(), form); err != nil {
+			t.Fatal(err)
+		}
+		return form
+	}
+	if form := info(); form.Folder != "in/" || form.MaxSize != 5 || form.RemainingFiles != 2 {
+		t.Fatalf("unexpected link info %+v", form)
+	}
+
+	upload := func(files map[string]string, names ...string) (int, *UploadFilesForm) {
+		headers := map[string]string{"Content-Type": "multipart/form-data; boundary=" + testBoundary}
+		rec := s.serve(t, http.MethodPost, "/u/"+created.Token, multipartBody(files, names, false), headers)
+		response := &UploadFilesForm{}
+		_ = json.Unmarshal(rec.Body.Bytes(), response)
+		return rec.Code, response
+	}
+
+	// Too large file is not stored and does not use up files of link.
+	status, response := upload(map[string]string{"a.txt": "new a", "big.txt": "too large"}, "a.txt", "big.txt")
+	if status != http.StatusMultiStatus {
+		t.Fatalf("status %d of partial upload", status)
+	}
+	if response.Files[0].Status != UploadStatusUploaded || response.Files[0].FilePath == "in/a.txt" {
+		t.Fatalf("existing file is not kept: %+v", response.Files[0])
+	}
+	if response.Files[1].Status != UploadStatusFailed {
+		t.Fatalf("too large file is uploaded: %+v", response.Files[1])
+	}
+	if content := s.readText(t, "in/a.txt"); content != "existing" {
+		t.Fatalf("existing file is replaced by %q", content)
+	}
+	if content := s.readText(t, response.Files[0].FilePath); content != "new a" {
+		t.Fatalf("renamed file has content %q", content)
+	}
+	if form := info(); form.RemainingFiles != 1 {
+		t.Fatalf("remaining files %d after one upload", form.RemainingFiles)
+	}
+
+	if status, _ = upload(map[string]string{"b.txt": "b"}, "b.txt"); status != http.StatusOK {
+		t.Fatalf("status %d of last file", status)
+	}
+
+	if status, _ = upload(map[string]string{"c.txt": "c"}, "c.txt"); status != http.StatusGone {
+		t.Fatalf("status %d of exhausted link", status)
+	}
+	if rec := s.serve(t, http.MethodGet, "/u/unknown", nil, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d of unknown link", rec.Code)
+	}
+}
+
+func TestRevokeUploadLink(t *testing.T) {
+	s := newAccessTestServer(t,
+		&rbac.Binding{Subject: "alice", Bucket: "b1", Role: rbac.RoleWriter},
+		&rbac.Binding{Subject: "bob", Bucket: "b1", Role: rbac.RoleWriter},
+		&rbac.Binding{Subject: "root", Bucket: "b1", Role: rbac.RoleAdmin},
+	)
+	alice, bob, root := bearer(t, "alice"), bearer(t, "bob"), bearer(t, "root")
+
+	created := createUploadLink(t, s, `{"folder":"in"}`, alice)
+
+	links := func(headers map[string]string) []string {
+		rec := s.serve(t, http.MethodGet, "/cloud/b1/upload-links", nil, headers)
+		form := &UploadLinksForm{}
+		if err := json.Unmarshal(rec.Body.Bytes(), form); err != nil {
+			t.Fatalf("unexpected response %s: %v", rec.Body.String(), err)
+		}
+		ids := make([]string, 0, len(form.Links))
+		for _, link := range form.Links {
+			ids = append(ids, link.ID)
+		}
+		return ids
+	}
+	if ids := links(bob); len(ids) != 0 {
+		t.Fatalf("bob sees links of alice: %v", ids)
+	}
+	if ids := links(root); len(ids) != 1 || ids[0] != created.Link.ID {
+		t.Fatalf("admin sees links %v", ids)
+	}
+
+	target := "/cloud/b1/upload-links/" + created.Link.ID
+	if rec := s.serve(t, http.MethodDelete, target, nil, bob); rec.Code != http.StatusForbidden {
+		t.Fatalf("link is revoked by other writer: status %d", rec.Code)
+	}
+	if rec := s.serve(t, http.MethodDelete, target, nil, alice); rec.Code != http.StatusOK {
+		t.Fatalf("link is not revoked by creator: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := s.serve(t, http.MethodGet, "/u/"+created.Token, nil, nil); rec.Code != http.StatusGone {
+		t.Fatalf("revoked link: status %d", rec.Code)
+	}
+	if rec := s.serve(t, http.MethodDelete, "/cloud/b1/upload-links/missing", nil, root); rec.Code != http.StatusNotFound {
+		t.Fatalf("missing link: status %d", rec.Code)
+	}
+}
+
+func TestCreateUploadLinkChecksAccess(t *testing.T) {
+	s := newAccessTestServer(t,
+		&rbac.Binding{Subject: "alice", Bucket: "b1", Prefix: "in/", Role: rbac.RoleWriter},
+		&rbac.Binding{Subject: "carol", Bucket: "b1", Role: rbac.RoleReader},
+	)
+
+	tests := []struct {
+		name    string
+		form    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "writer of folder", form: `{"folder":"in/acme"}`, headers: bearer(t, "alice"), status: http.StatusOK},
+		{name: "writer of other folder", form: `{"folder":"out"}`, headers: bearer(t, "alice"), status: http.StatusForbidden},
+		{name: "reader", form: `{"folder":"in"}`, headers: bearer(t, "carol"), status: http.StatusForbidden},
+		{name: "negative max files", form: `{"folder":"in","max_files":-1}`, headers: bearer(t, "alice"), status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPost, "/cloud/b1/upload-links", strings.NewReader(test.form), test.headers)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPresignUploadUnsupported(t *testing.T) {
+	s := newTestServer(t)
+
+	form := `{"method":"PUT","file_path":"in/a.txt","expired_secs":60}`
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/file/presign-upload", strings.NewReader(form), nil)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "upload links") {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+}
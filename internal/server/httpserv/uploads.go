@@ -0,0 +1,233 @@
+package httpserv
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+// uploadToken is encoded into upload id returned to client, so that
+// session does not need to be stored by docs-hub between requests.
+type uploadToken struct {
+	FilePath string `json:"p"`
+	UploadID string `json:"u"`
+}
+
+func (ut *uploadToken) encode() string {
+	data, _ := json.Marshal(ut)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeUploadToken(value string) (*uploadToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload id: %w", err)
+	}
+
+	token := &uploadToken{}
+	if err = json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("invalid upload id: %w", err)
+	}
+
+	if token.FilePath == "" || token.UploadID == "" {
+		return nil, errors.New("invalid upload id")
+	}
+
+	return token, nil
+}
+
+// CreateUpload
+// @Summary Start resumable upload session
+// @Description Start resumable multipart upload session of single file
+// @ID create-upload
+// @Tags uploads
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name to upload file"
+// @Param jsonQuery body CreateUploadForm true "Parameters to start upload"
+// @Success 200 {object} UploadSessionForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/uploads [post]
+func (s *ServerHttp) CreateUpload(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &CreateUploadForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.authorize(c, bucket, jsonForm.FilePath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	uploadID, err := s.cloud.Cloud.CreateUpload(ctx, bucket, jsonForm.FilePath)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	token := &uploadToken{FilePath: jsonForm.FilePath, UploadID: uploadID}
+	return c.JSON(200, &UploadSessionForm{
+		UploadID: token.encode(),
+		FilePath: jsonForm.FilePath,
+	})
+}
+
+// GetUpload
+// @Summary Get resumable upload session state
+// @Description Get already uploaded parts of session to resume upload
+// @ID get-upload
+// @Tags uploads
+// @Produce json
+// @Param bucket path string true "Bucket name of upload"
+// @Param id path string true "Upload session id"
+// @Success 200 {object} UploadSessionForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	404 {object} BadRequestForm "Not Found message"
+// @Router /cloud/{bucket}/uploads/{id} [get]
+func (s *ServerHttp) GetUpload(c echo.Context) error {
+	bucket := c.Param("bucket")
+	token, err := decodeUploadToken(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err = s.authorize(c, bucket, token.FilePath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	parts, err := s.cloud.Cloud.ListUploadParts(ctx, bucket, token.FilePath, token.UploadID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	return c.JSON(200, &UploadSessionForm{
+		UploadID: c.Param("id"),
+		FilePath: token.FilePath,
+		Parts:    parts,
+	})
+}
+
+// UploadPart
+// @Summary Upload part of file
+// @Description Upload numbered chunk of file, every part except last must be at least 5MiB
+// @ID upload-part
+// @Tags uploads
+// @Accept  octet-stream
+// @Produce json
+// @Param bucket path string true "Bucket name of upload"
+// @Param id path string true "Upload session id"
+// @Param n path int true "Part number from 1 to 10000"
+// @Success 200 {object} cloud.UploadPart "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	411 {object} BadRequestForm "Length Required message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/uploads/{id}/parts/{n} [put]
+func (s *ServerHttp) UploadPart(c echo.Context) error {
+	bucket := c.Param("bucket")
+	token, err := decodeUploadToken(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err = s.authorize(c, bucket, token.FilePath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	partNumber, err := strconv.Atoi(c.Param("n"))
+	if err != nil || partNumber < 1 || partNumber > maxUploadParts {
+		retErr := fmt.Errorf("part number must be between 1 and %d", maxUploadParts)
+		return echo.NewHTTPError(http.StatusBadRequest, retErr.Error())
+	}
+
+	size := c.Request().ContentLength
+	if size < 0 {
+		return echo.NewHTTPError(http.StatusLengthRequired, "content length of part is required")
+	}
+
+	ctx := c.Request().Context()
+	body := c.Request().Body
+	part, err := s.cloud.Cloud.UploadPart(ctx, bucket, token.FilePath, token.UploadID, partNumber, body, size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, part)
+}
+
+// CompleteUpload
+// @Summary Complete resumable upload session
+// @Description Concatenate uploaded parts into file and close session
+// @ID complete-upload
+// @Tags uploads
+// @Produce json
+// @Param bucket path string true "Bucket name of upload"
+// @Param id path string true "Upload session id"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/uploads/{id}/complete [post]
+func (s *ServerHttp) CompleteUpload(c echo.Context) error {
+	bucket := c.Param("bucket")
+	token, err := decodeUploadToken(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	event := auditOperation(c, audit.OperationUpload, bucket, token.FilePath)
+	if err = s.authorize(c, bucket, token.FilePath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err = s.cloud.Cloud.CompleteUpload(ctx, bucket, token.FilePath, token.UploadID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if fileInfo, err := s.cloud.Cloud.StatFile(ctx, bucket, token.FilePath); err == nil {
+		event.Size = fileInfo.Size
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// AbortUpload
+// @Summary Abort resumable upload session
+// @Description Abort upload session and remove already uploaded parts
+// @ID abort-upload
+// @Tags uploads
+// @Produce json
+// @Param bucket path string true "Bucket name of upload"
+// @Param id path string true "Upload session id"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/uploads/{id} [delete]
+func (s *ServerHttp) AbortUpload(c echo.Context) error {
+	bucket := c.Param("bucket")
+	token, err := decodeUploadToken(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err = s.authorize(c, bucket, token.FilePath, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err = s.cloud.Cloud.AbortUpload(ctx, bucket, token.FilePath, token.UploadID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
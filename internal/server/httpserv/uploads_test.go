@@ -0,0 +1,110 @@
+package httpserv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadSession(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/uploads", strings.NewReader(`{"file_path":"docs/big.bin"}`), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to create upload: %s", rec.Body.String())
+	}
+	session := &UploadSessionForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), session); err != nil {
+		t.Fatal(err)
+	}
+	sessionURL := "/cloud/b1/uploads/" + session.UploadID
+
+	for _, part := range []struct {
+		number string
+		data   string
+		status int
+	}{
+		{number: "2", data: "world", status: http.StatusOK},
+		{number: "1", data: "hello ", status: http.StatusOK},
+		{number: "0", data: "x", status: http.StatusBadRequest},
+		{number: "10001", data: "x", status: http.StatusBadRequest},
+	} {
+		rec = s.serve(t, http.MethodPut, sessionURL+"/parts/"+part.number, strings.NewReader(part.data), nil)
+		if rec.Code != part.status {
+			t.Fatalf("part %s: status %d, expected %d: %s", part.number, rec.Code, part.status, rec.Body.String())
+		}
+	}
+
+	rec = s.serve(t, http.MethodGet, sessionURL, nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to get upload: %s", rec.Body.String())
+	}
+	resumed := &UploadSessionForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), resumed); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.FilePath != "docs/big.bin" || len(resumed.Parts) != 2 {
+		t.Fatalf("unexpected session %+v", resumed)
+	}
+
+	rec = s.serve(t, http.MethodPost, sessionURL+"/complete", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to complete upload: %s", rec.Body.String())
+	}
+	if content := s.readText(t, "docs/big.bin"); content != "hello world" {
+		t.Fatalf("content %q", content)
+	}
+
+	rec = s.serve(t, http.MethodGet, sessionURL, nil, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("completed upload: status %d, expected %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestUploadSessionAbort(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/uploads", strings.NewReader(`{"file_path":"a.bin"}`), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to create upload: %s", rec.Body.String())
+	}
+	session := &UploadSessionForm{}
+	if err := json.Unmarshal(rec.Body.Bytes(), session); err != nil {
+		t.Fatal(err)
+	}
+	sessionURL := "/cloud/b1/uploads/" + session.UploadID
+
+	if rec = s.serve(t, http.MethodPut, sessionURL+"/parts/1", strings.NewReader("a"), nil); rec.Code != http.StatusOK {
+		t.Fatalf("failed to upload part: %s", rec.Body.String())
+	}
+	if rec = s.serve(t, http.MethodDelete, sessionURL, nil, nil); rec.Code != http.StatusOK {
+		t.Fatalf("failed to abort upload: %s", rec.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+	}{
+		{name: "complete aborted", method: http.MethodPost, target: sessionURL + "/complete", status: http.StatusBadRequest},
+		{name: "get aborted", method: http.MethodGet, target: sessionURL, status: http.StatusNotFound},
+		{name: "malformed id", method: http.MethodGet, target: "/cloud/b1/uploads/%25%25", status: http.StatusBadRequest},
+		{name: "id without session", method: http.MethodGet, target: "/cloud/b1/uploads/e30", status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, test.method, test.target, nil, nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	if _, err := s.cloud.Cloud.StatFile(context.Background(), "b1", "a.bin"); err == nil {
+		t.Fatal("aborted upload has created file")
+	}
+}
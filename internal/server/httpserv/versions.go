@@ -0,0 +1,219 @@
+package httpserv
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"docs-hub/internal/audit"
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+// GetBucketVersioning
+// @Summary Get bucket versioning status
+// @Description Get bucket versioning status, empty status means versioning has never been enabled
+// @ID get-bucket-versioning
+// @Tags versions
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Success 200 {object} BucketVersioningForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/versioning [get]
+func (s *ServerHttp) GetBucketVersioning(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	if err := s.authorize(c, bucket, "", rbac.RoleReader); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	status, err := s.cloud.Cloud.GetBucketVersioning(ctx, bucket)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, &BucketVersioningForm{Status: status})
+}
+
+// SetBucketVersioning
+// @Summary Enable or suspend bucket versioning
+// @Description Enable or suspend bucket versioning, versions kept so far are not removed on suspend
+// @ID set-bucket-versioning
+// @Tags versions
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param jsonQuery body BucketVersioningForm true "Versioning status"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/versioning [put]
+func (s *ServerHttp) SetBucketVersioning(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &BucketVersioningForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationSetVersioning, bucket, "")
+	if err := s.authorize(c, bucket, "", rbac.RoleAdmin); err != nil {
+		return err
+	}
+
+	if err := cloud.CheckVersioningStatus(jsonForm.Status); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	if err := s.cloud.Cloud.SetBucketVersioning(ctx, bucket, jsonForm.Status); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// ListFileVersions
+// @Summary Get versions of file
+// @Description Get all versions and delete markers of file, the newest first
+// @ID list-file-versions
+// @Tags versions
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of file"
+// @Param jsonQuery body FileVersionsForm true "File to get versions of"
+// @Success 200 {array} cloud.FileVersion "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/versions [post]
+func (s *ServerHttp) ListFileVersions(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &FileVersionsForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleReader); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	versions, err := s.cloud.Cloud.ListFileVersions(ctx, bucket, jsonForm.FileName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, versions)
+}
+
+// DownloadVersion
+// @Summary Download version of file
+// @Description Download content of specified file version
+// @ID download-file-version
+// @Tags versions
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of file"
+// @Param jsonQuery body FileVersionForm true "File version to download"
+// @Success 200 {file} io.Writer "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/version/download [post]
+func (s *ServerHttp) DownloadVersion(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &FileVersionForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationDownload, bucket, jsonForm.FileName)
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleReader); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	fileData, err := s.cloud.Cloud.DownloadVersion(ctx, bucket, jsonForm.FileName, jsonForm.VersionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	defer closeFileData(jsonForm.FileName, fileData)
+
+	return c.Stream(200, echo.MIMEOctetStream, fileData)
+}
+
+// RestoreVersion
+// @Summary Restore version of file
+// @Description Make copy of specified file version the current version, other versions are kept
+// @ID restore-file-version
+// @Tags versions
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of file"
+// @Param jsonQuery body FileVersionForm true "File version to restore"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/version/restore [post]
+func (s *ServerHttp) RestoreVersion(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &FileVersionForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationRestoreVersion, bucket, jsonForm.FileName)
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := s.cloud.Cloud.RestoreVersion(ctx, bucket, jsonForm.FileName, jsonForm.VersionID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
+
+// RemoveVersion
+// @Summary Remove version of file
+// @Description Permanently remove specified file version or delete marker
+// @ID remove-file-version
+// @Tags versions
+// @Accept  json
+// @Produce json
+// @Param bucket path string true "Bucket name of file"
+// @Param jsonQuery body FileVersionForm true "File version to remove"
+// @Success 200 {object} ResponseForm "Ok"
+// @Failure	400 {object} BadRequestForm "Bad Request message"
+// @Failure	503 {object} ServerErrorForm "Server does not available"
+// @Router /cloud/{bucket}/file/version/remove [delete]
+func (s *ServerHttp) RemoveVersion(c echo.Context) error {
+	bucket := c.Param("bucket")
+
+	jsonForm := &FileVersionForm{}
+	decoder := json.NewDecoder(c.Request().Body)
+	if err := decoder.Decode(jsonForm); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	auditOperation(c, audit.OperationRemoveVersion, bucket, jsonForm.FileName)
+	if err := s.authorize(c, bucket, jsonForm.FileName, rbac.RoleWriter); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := s.cloud.Cloud.RemoveVersion(ctx, bucket, jsonForm.FileName, jsonForm.VersionID); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(200, createStatusResponse(200, "Ok"))
+}
@@ -0,0 +1,105 @@
+package httpserv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"docs-hub/internal/cloud"
+)
+
+func (s *ServerHttp) listVersions(t *testing.T, filePath string) []*cloud.FileVersion {
+	t.Helper()
+
+	rec := s.serve(t, http.MethodPost, "/cloud/b1/file/versions", strings.NewReader(`{"file_name":"`+filePath+`"}`), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to list versions: %s", rec.Body.String())
+	}
+
+	versions := make([]*cloud.FileVersion, 0)
+	if err := json.Unmarshal(rec.Body.Bytes(), &versions); err != nil {
+		t.Fatal(err)
+	}
+
+	return versions
+}
+
+func TestCreateBucketWithVersioning(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "enabled", body: `{"bucket_name":"b2","versioning":"Enabled"}`, status: http.StatusOK},
+		{name: "unknown status", body: `{"bucket_name":"b3","versioning":"On"}`, status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.serve(t, http.MethodPut, "/cloud/bucket", strings.NewReader(test.body), nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, expected %d: %s", rec.Code, test.status, rec.Body.String())
+			}
+		})
+	}
+
+	rec := s.serve(t, http.MethodGet, "/cloud/b2/versioning", nil, nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"Enabled"`) {
+		t.Fatalf("versioning of created bucket: %s", rec.Body.String())
+	}
+	if exist, _ := s.cloud.Cloud.IsBucketExist(context.Background(), "b3"); exist {
+		t.Fatal("bucket with unknown versioning status has been created")
+	}
+}
+
+func TestFileVersions(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.serve(t, http.MethodPut, "/cloud/b1/versioning", strings.NewReader(`{"status":"Enabled"}`), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to enable versioning: %s", rec.Body.String())
+	}
+	s.uploadText(t, "contract.txt", "v1")
+	s.uploadText(t, "contract.txt", "v2")
+
+	versions := s.listVersions(t, "contract.txt")
+	if len(versions) != 2 {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+	firstVersion := `{"file_name":"contract.txt","version_id":"` + versions[1].VersionID + `"}`
+
+	rec = s.serve(t, http.MethodPost, "/cloud/b1/file/version/download", strings.NewReader(firstVersion), nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "v1" {
+		t.Fatalf("first version: status %d, content %q", rec.Code, rec.Body.String())
+	}
+
+	rec = s.serve(t, http.MethodPost, "/cloud/b1/file/version/restore", strings.NewReader(firstVersion), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to restore version: %s", rec.Body.String())
+	}
+	if content := s.readText(t, "contract.txt"); content != "v1" {
+		t.Fatalf("restored content %q", content)
+	}
+	if versions = s.listVersions(t, "contract.txt"); len(versions) != 3 {
+		t.Fatalf("restore has not added version: %+v", versions)
+	}
+
+	rec = s.serve(t, http.MethodDelete, "/cloud/b1/file/version/remove", strings.NewReader(firstVersion), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to remove version: %s", rec.Body.String())
+	}
+	if versions = s.listVersions(t, "contract.txt"); len(versions) != 2 {
+		t.Fatalf("removed version is listed: %+v", versions)
+	}
+
+	for _, target := range []string{"/cloud/b1/file/version/download", "/cloud/b1/file/version/restore"} {
+		rec = s.serve(t, http.MethodPost, target, strings.NewReader(firstVersion), nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s of removed version: status %d", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package share
+
+import "time"
+
+type Config struct {
+	Enabled bool
+	// StoreFile is JSON file keeping share links, tokens themselves
+	// are shown only once when link is created.
+	StoreFile string
+	// UploadStoreFile is JSON file keeping upload links
+	// which external contributors upload files by.
+	UploadStoreFile string
+	// BaseURL is docs-hub address recipients open links by, it is
+	// required because Host header of request can be forged by client.
+	BaseURL string
+	// DefaultExpiry is lifetime of link which is created without expiry.
+	DefaultExpiry time.Duration
+	// MaxExpiry limits lifetime of link, links are created only with
+	// expiry if both it and default expiry are zero.
+	MaxExpiry time.Duration
+	// MaxPasswordAttempts is number of wrong passwords after which
+	// link is locked for PasswordLockout, zero disables lockout.
+	MaxPasswordAttempts int
+	PasswordLockout     time.Duration
+	// MaxZipFiles and MaxZipSize limit number and total size of files
+	// downloaded as single archive of link, zero disables limit.
+	MaxZipFiles int
+	MaxZipSize  int64
+}
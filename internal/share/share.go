@@ -0,0 +1,607 @@
+package share
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	tokenLength = 24
+	// countSaveDelay collects downloads of links without download
+	// limit into single write of store file.
+	countSaveDelay = time.Minute
+)
+
+var (
+	ErrLinkNotFound    = errors.New("share link does not exist")
+	ErrLinkUnavailable = errors.New("link is revoked, expired or exhausted")
+	ErrLinkLocked      = errors.New("share link is locked after wrong passwords, try again later")
+	ErrPasswordNeeded  = errors.New("share link password is required")
+	ErrWrongPassword   = errors.New("wrong share link password")
+	ErrArchiveTooLarge = errors.New("shared files exceed limits of archive")
+)
+
+// Link is share of file, folder or set of files which is downloaded by
+// opaque token instead of cloud presigned URL, only hash of token is kept.
+type Link struct {
+	ID           string     `json:"id" example:"4b1e7d2a9c3f5e80"`
+	TokenHash    string     `json:"token_hash,omitempty" swaggerignore:"true"`
+	Bucket       string     `json:"bucket" example:"contracts"`
+	FilePath     string     `json:"file_path,omitempty" example:"2024/supply.docx"`
+	Folder       string     `json:"folder,omitempty" example:"2024/"`
+	FilePaths    []string   `json:"file_paths,omitempty" example:"2024/supply.docx,2024/invoice.pdf"`
+	Creator      string     `json:"creator" example:"alice"`
+	CreatedAt    time.Time  `json:"created_at" example:"2025-01-01T12:01:01Z"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" example:"2025-01-08T12:01:01Z"`
+	MaxDownloads int        `json:"max_downloads,omitempty" example:"5"`
+	Downloads    int        `json:"downloads" example:"2"`
+	PasswordHash string     `json:"password_hash,omitempty" swaggerignore:"true"`
+	HasPassword  bool       `json:"has_password" example:"true"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" example:"2025-01-02T09:00:00Z"`
+}
+
+// IsAvailable reports whether link can be downloaded at moment.
+func (l *Link) IsAvailable(now time.Time) bool {
+	switch {
+	case l.RevokedAt != nil:
+		return false
+	case l.ExpiresAt != nil && !now.Before(*l.ExpiresAt):
+		return false
+	case l.MaxDownloads > 0 && l.Downloads >= l.MaxDownloads:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsCollection reports whether link shares folder or set of files
+// which are listed by link instead of single file.
+func (l *Link) IsCollection() bool {
+	return l.FilePath == ""
+}
+
+// SharedPath returns file path or folder shared by link,
+// it is empty for set of files.
+func (l *Link) SharedPath() string {
+	if l.FilePath != "" {
+		return l.FilePath
+	}
+
+	return l.Folder
+}
+
+// Contains reports whether file can be downloaded by link.
+func (l *Link) Contains(filePath string) bool {
+	switch {
+	case l.FilePath != "":
+		return filePath == l.FilePath
+	case l.Folder != "":
+		return strings.HasPrefix(filePath, l.Folder) && !strings.HasSuffix(filePath, "/")
+	default:
+		return slices.Contains(l.FilePaths, filePath)
+	}
+}
+
+func (l *Link) withoutSecrets() *Link {
+	link := *l
+	link.TokenHash = ""
+	link.PasswordHash = ""
+	return &link
+}
+
+// LinkParams are settings of new link, zero values mean no limit
+// except expiry, which is set to default expiry of store.
+// Link shares exactly one of file path, folder or set of file paths.
+type LinkParams struct {
+	Bucket       string
+	FilePath     string
+	Folder       string
+	FilePaths    []string
+	Creator      string
+	ExpiresAt    *time.Time
+	MaxDownloads int
+	Password     string
+}
+
+type storeFile struct {
+	Links []*Link `json:"links"`
+}
+
+// passwordAttempts tracks wrong passwords of link, it is kept in memory
+// only, so lockout is lifted by restart at worst.
+type passwordAttempts struct {
+	failures    int
+	lockedUntil time.Time
+}
+
+type Store struct {
+	config *Config
+
+	mu       sync.Mutex
+	links    []*Link
+	attempts map[string]*passwordAttempts
+	// countTimer is set while downloads are waiting to be saved.
+	countTimer *time.Timer
+}
+
+// New loads store file, missing file means there are no links yet.
+func New(config *Config) (*Store, error) {
+	store := &Store{config: config, links: make([]*Link, 0), attempts: make(map[string]*passwordAttempts)}
+	if !config.Enabled {
+		return store, nil
+	}
+
+	if config.StoreFile == "" {
+		return nil, errors.New("store file is required for share links")
+	}
+
+	if config.BaseURL == "" {
+		return nil, errors.New("base URL is required for share links")
+	}
+
+	file := &storeFile{}
+	if err := readStoreFile(config.StoreFile, file); err != nil {
+		return nil, err
+	}
+
+	if file.Links != nil {
+		store.links = pruneExpired(file.Links, time.Now())
+	}
+
+	return store, nil
+}
+
+func (s *Store) IsEnabled() bool {
+	return s.config.Enabled
+}
+
+func (s *Store) BaseURL() string {
+	return s.config.BaseURL
+}
+
+// CheckArchive reports whether files of link fit into single archive.
+func (s *Store) CheckArchive(fileCount int, totalSize int64) error {
+	if s.config.MaxZipFiles > 0 && fileCount > s.config.MaxZipFiles {
+		return fmt.Errorf("%w: %d files, at most %d are allowed", ErrArchiveTooLarge, fileCount, s.config.MaxZipFiles)
+	}
+
+	if s.config.MaxZipSize > 0 && totalSize > s.config.MaxZipSize {
+		return fmt.Errorf("%w: %d bytes, at most %d are allowed", ErrArchiveTooLarge, totalSize, s.config.MaxZipSize)
+	}
+
+	return nil
+}
+
+// Create stores new link and returns token it is downloaded by,
+// token is returned only here and can not be restored later.
+func (s *Store) Create(params *LinkParams) (*Link, string, error) {
+	now := time.Now().UTC()
+	expiresAt, err := limitExpiry(s.config, now, params.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.MaxDownloads < 0 {
+		return nil, "", errors.New("max downloads must not be negative")
+	}
+
+	if err = checkShared(params); err != nil {
+		return nil, "", err
+	}
+
+	linkID, err := randomID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	link := &Link{
+		ID:           linkID,
+		TokenHash:    hashToken(token),
+		Bucket:       params.Bucket,
+		FilePath:     params.FilePath,
+		Folder:       params.Folder,
+		FilePaths:    params.FilePaths,
+		Creator:      params.Creator,
+		CreatedAt:    now,
+		ExpiresAt:    expiresAt,
+		MaxDownloads: params.MaxDownloads,
+	}
+
+	if params.Password != "" {
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", err
+		}
+		link.PasswordHash = string(passwordHash)
+		link.HasPassword = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.save(append(s.links, link)); err != nil {
+		return nil, "", err
+	}
+
+	return link.withoutSecrets(), token, nil
+}
+
+// Links returns links of bucket including revoked ones, expired
+// links are removed from store when it is saved.
+func (s *Store) Links(bucket string) []*Link {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := make([]*Link, 0)
+	for _, link := range s.links {
+		if link.Bucket == bucket {
+			links = append(links, link.withoutSecrets())
+		}
+	}
+
+	return links
+}
+
+// Link returns link of bucket by its id.
+func (s *Store) Link(bucket, linkID string) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findLink(bucket, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return link.withoutSecrets(), nil
+}
+
+// Revoke disables link, it is kept in store to show when it was revoked.
+func (s *Store) Revoke(bucket, linkID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findLink(bucket, linkID)
+	if err != nil {
+		return err
+	}
+
+	if link.RevokedAt == nil {
+		now := time.Now().UTC()
+		link.RevokedAt = &now
+	}
+
+	return s.save(s.links)
+}
+
+// Check checks token, password and availability of link,
+// downloads are counted by Count afterwards.
+func (s *Store) Check(token, password string) (*Link, error) {
+	link, err := s.findToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkLockout(link.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	err = checkPassword(link, password)
+	s.trackPassword(link.ID, err, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !link.IsAvailable(time.Now()) {
+		return nil, ErrLinkUnavailable
+	}
+
+	return link.withoutSecrets(), nil
+}
+
+// Count counts download of link checked before, download is counted
+// before file is streamed so limit can not be exceeded by parallel requests.
+// Downloads of limited link are saved at once to keep limit over restarts,
+// downloads of other links are saved in background after countSaveDelay.
+func (s *Store) Count(bucket, linkID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findLink(bucket, linkID)
+	if err != nil {
+		return err
+	}
+
+	if !link.IsAvailable(time.Now()) {
+		return ErrLinkUnavailable
+	}
+
+	link.Downloads++
+	if link.MaxDownloads == 0 {
+		if s.countTimer == nil {
+			s.countTimer = time.AfterFunc(countSaveDelay, s.saveCounts)
+		}
+		return nil
+	}
+
+	if err = s.save(s.links); err != nil {
+		link.Downloads--
+		return err
+	}
+
+	return nil
+}
+
+// Close saves downloads which are waiting to be saved.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.countTimer == nil {
+		return nil
+	}
+
+	s.countTimer.Stop()
+	s.countTimer = nil
+	return s.save(s.links)
+}
+
+// saveCounts writes downloads of links into store file, they are kept
+// in memory only if it fails until next write.
+func (s *Store) saveCounts() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.countTimer == nil {
+		return
+	}
+
+	s.countTimer = nil
+	if err := s.save(s.links); err != nil {
+		log.Println("failed to save downloads of share links: ", err)
+	}
+}
+
+// checkLockout rejects link which is locked after wrong passwords.
+func (s *Store) checkLockout(linkID string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempts := s.attempts[linkID]; attempts != nil && now.Before(attempts.lockedUntil) {
+		return ErrLinkLocked
+	}
+
+	return nil
+}
+
+// trackPassword counts wrong password of link and locks link once
+// wrong passwords reach MaxPasswordAttempts, right password resets count.
+func (s *Store) trackPassword(linkID string, err error, now time.Time) {
+	if s.config.MaxPasswordAttempts <= 0 || errors.Is(err, ErrPasswordNeeded) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		delete(s.attempts, linkID)
+		return
+	}
+
+	attempts := s.attempts[linkID]
+	if attempts == nil {
+		attempts = &passwordAttempts{}
+		s.attempts[linkID] = attempts
+	}
+
+	attempts.failures++
+	if attempts.failures >= s.config.MaxPasswordAttempts {
+		attempts.failures = 0
+		attempts.lockedUntil = now.Add(s.config.PasswordLockout)
+	}
+}
+
+// checkPassword checks password of protected link. Password hash is never
+// changed, so it is checked without lock which would hold other downloads
+// while bcrypt is running.
+func checkPassword(link *Link, password string) error {
+	if link.PasswordHash == "" {
+		return nil
+	}
+
+	if password == "" {
+		return ErrPasswordNeeded
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+		return ErrWrongPassword
+	}
+
+	return nil
+}
+
+// checkShared checks that link shares exactly one of file, folder or set of files.
+func checkShared(params *LinkParams) error {
+	shared := 0
+	for _, isSet := range []bool{params.FilePath != "", params.Folder != "", len(params.FilePaths) > 0} {
+		if isSet {
+			shared++
+		}
+	}
+
+	if shared != 1 {
+		return errors.New("link must share either file, folder or set of files")
+	}
+
+	for _, filePath := range params.FilePaths {
+		if filePath == "" || strings.HasSuffix(filePath, "/") {
+			return fmt.Errorf("shared file path is invalid: %q", filePath)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) findToken(token string) (*Link, error) {
+	hash := hashToken(token)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range s.links {
+		if link.TokenHash == hash {
+			return link, nil
+		}
+	}
+
+	return nil, ErrLinkNotFound
+}
+
+func (s *Store) findLink(bucket, linkID string) (*Link, error) {
+	for _, link := range s.links {
+		if link.ID == linkID && link.Bucket == bucket {
+			return link, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrLinkNotFound, linkID)
+}
+
+// save writes links into store file and applies them, expired links
+// are dropped. It must be called with lock held.
+func (s *Store) save(links []*Link) error {
+	links = pruneExpired(links, time.Now())
+	if err := writeStoreFile(s.config.StoreFile, &storeFile{Links: links}); err != nil {
+		return err
+	}
+
+	for linkID := range s.attempts {
+		if !slices.ContainsFunc(links, func(link *Link) bool { return link.ID == linkID }) {
+			delete(s.attempts, linkID)
+		}
+	}
+
+	s.links = links
+	return nil
+}
+
+// pruneExpired returns links which have not expired at moment,
+// expired link can never be downloaded again.
+func pruneExpired(links []*Link, now time.Time) []*Link {
+	kept := make([]*Link, 0, len(links))
+	for _, link := range links {
+		if link.ExpiresAt == nil || now.Before(*link.ExpiresAt) {
+			kept = append(kept, link)
+		}
+	}
+
+	return kept
+}
+
+// writeStoreFile replaces content of store file atomically.
+func writeStoreFile(storePath string, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(storePath), 0o755); err != nil {
+		return err
+	}
+
+	tmpPath := storePath + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, storePath)
+}
+
+// readStoreFile reads content of store file, missing file is kept
+// as empty store.
+func readStoreFile(storePath string, content interface{}) error {
+	data, err := os.ReadFile(storePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, content); err != nil {
+		return fmt.Errorf("failed to parse store file %s: %w", storePath, err)
+	}
+
+	return nil
+}
+
+// limitExpiry checks expiry of new link, sets default expiry if it is
+// not given and shortens it to max expiry, link never lives forever.
+func limitExpiry(config *Config, now time.Time, expiresAt *time.Time) (*time.Time, error) {
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, errors.New("expiry time must be in the future")
+	}
+
+	if expiresAt == nil && config.DefaultExpiry > 0 {
+		defaultExpiresAt := now.Add(config.DefaultExpiry)
+		expiresAt = &defaultExpiresAt
+	}
+
+	if config.MaxExpiry > 0 {
+		maxExpiresAt := now.Add(config.MaxExpiry)
+		if expiresAt == nil || expiresAt.After(maxExpiresAt) {
+			return &maxExpiresAt, nil
+		}
+	}
+
+	if expiresAt == nil {
+		return nil, errors.New("expiry time is required")
+	}
+
+	return expiresAt, nil
+}
+
+func newToken() (string, error) {
+	tokenBytes := make([]byte, tokenLength)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func randomID() (string, error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(idBytes), nil
+}
@@ -0,0 +1,380 @@
+package share
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	config := &Config{
+		Enabled:             true,
+		StoreFile:           filepath.Join(t.TempDir(), "links.json"),
+		BaseURL:             "https://docs.example.com",
+		DefaultExpiry:       time.Hour,
+		MaxExpiry:           24 * time.Hour,
+		MaxPasswordAttempts: 3,
+		PasswordLockout:     time.Hour,
+	}
+	store, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+func TestLimitExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) *time.Time {
+		value := now.Add(offset)
+		return &value
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		expiresAt *time.Time
+		expected  *time.Time
+		isInvalid bool
+	}{
+		{name: "given expiry", config: Config{MaxExpiry: 24 * time.Hour}, expiresAt: at(time.Hour), expected: at(time.Hour)},
+		{name: "shortened to max", config: Config{MaxExpiry: 24 * time.Hour}, expiresAt: at(48 * time.Hour), expected: at(24 * time.Hour)},
+		{name: "default expiry", config: Config{DefaultExpiry: time.Hour, MaxExpiry: 24 * time.Hour}, expected: at(time.Hour)},
+		{name: "default above max", config: Config{DefaultExpiry: 48 * time.Hour, MaxExpiry: 24 * time.Hour}, expected: at(24 * time.Hour)},
+		{name: "max without default", config: Config{MaxExpiry: 24 * time.Hour}, expected: at(24 * time.Hour)},
+		{name: "no limits", config: Config{}, expiresAt: at(time.Hour), expected: at(time.Hour)},
+		{name: "never expiring link", config: Config{}, isInvalid: true},
+		{name: "past expiry", config: Config{MaxExpiry: time.Hour}, expiresAt: at(-time.Minute), isInvalid: true},
+		{name: "expiry at now", config: Config{MaxExpiry: time.Hour}, expiresAt: at(0), isInvalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expiresAt, err := limitExpiry(&test.config, now, test.expiresAt)
+			if test.isInvalid {
+				if err == nil {
+					t.Fatalf("expected error, got expiry %v", expiresAt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expiresAt == nil || !expiresAt.Equal(*test.expected) {
+				t.Fatalf("expiry %v, expected %v", expiresAt, test.expected)
+			}
+		})
+	}
+}
+
+func TestLinkIsAvailable(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Second)
+	after := now.Add(time.Second)
+
+	tests := []struct {
+		name     string
+		link     Link
+		expected bool
+	}{
+		{name: "active", link: Link{ExpiresAt: &after}, expected: true},
+		{name: "expired", link: Link{ExpiresAt: &before}, expected: false},
+		{name: "expires now", link: Link{ExpiresAt: &now}, expected: false},
+		{name: "revoked", link: Link{ExpiresAt: &after, RevokedAt: &before}, expected: false},
+		{name: "downloads left", link: Link{MaxDownloads: 2, Downloads: 1}, expected: true},
+		{name: "downloads exhausted", link: Link{MaxDownloads: 2, Downloads: 2}, expected: false},
+		{name: "unlimited downloads", link: Link{Downloads: 100}, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if available := test.link.IsAvailable(now); available != test.expected {
+				t.Fatalf("IsAvailable = %v, expected %v", available, test.expected)
+			}
+		})
+	}
+}
+
+func TestLinkContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     Link
+		filePath string
+		expected bool
+	}{
+		{name: "shared file", link: Link{FilePath: "a.txt"}, filePath: "a.txt", expected: true},
+		{name: "other file", link: Link{FilePath: "a.txt"}, filePath: "b.txt", expected: false},
+		{name: "file of folder", link: Link{Folder: "docs/"}, filePath: "docs/sub/a.txt", expected: true},
+		{name: "folder placeholder", link: Link{Folder: "docs/"}, filePath: "docs/sub/", expected: false},
+		{name: "sibling folder", link: Link{Folder: "docs/"}, filePath: "docs2/a.txt", expected: false},
+		{name: "file of set", link: Link{FilePaths: []string{"a.txt", "b.txt"}}, filePath: "b.txt", expected: true},
+		{name: "file out of set", link: Link{FilePaths: []string{"a.txt"}}, filePath: "c.txt", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if contains := test.link.Contains(test.filePath); contains != test.expected {
+				t.Fatalf("Contains(%s) = %v, expected %v", test.filePath, contains, test.expected)
+			}
+		})
+	}
+}
+
+func TestStoreCheckPassword(t *testing.T) {
+	store := newTestStore(t)
+	_, token, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "a.txt", Password: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		password string
+		expected error
+	}{
+		{name: "right password", token: token, password: "secret"},
+		{name: "missing password", token: token, expected: ErrPasswordNeeded},
+		{name: "wrong password", token: token, password: "guess", expected: ErrWrongPassword},
+		{name: "unknown token", token: "unknown", password: "secret", expected: ErrLinkNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			link, err := store.Check(test.token, test.password)
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("error %v, expected %v", err, test.expected)
+			}
+			if err == nil && (link.PasswordHash != "" || link.TokenHash != "") {
+				t.Fatal("checked link exposes secrets")
+			}
+		})
+	}
+}
+
+func TestStoreCountLimitsDownloads(t *testing.T) {
+	store := newTestStore(t)
+	link, token, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "a.txt", MaxDownloads: 2})
+	if err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+	if link.ExpiresAt == nil {
+		t.Fatal("link without expiry has not got default expiry")
+	}
+
+	for download := 1; download <= 2; download++ {
+		if _, err = store.Check(token, ""); err != nil {
+			t.Fatalf("download %d: check failed: %v", download, err)
+		}
+		if err = store.Count("b1", link.ID); err != nil {
+			t.Fatalf("download %d: count failed: %v", download, err)
+		}
+	}
+
+	if _, err = store.Check(token, ""); !errors.Is(err, ErrLinkUnavailable) {
+		t.Fatalf("exhausted link: expected ErrLinkUnavailable, got %v", err)
+	}
+	if err = store.Count("b1", link.ID); !errors.Is(err, ErrLinkUnavailable) {
+		t.Fatalf("exhausted link: expected count to fail, got %v", err)
+	}
+
+	// Downloads are kept in store file and survive restart.
+	reloaded, err := New(store.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := reloaded.Link("b1", link.ID); err != nil || stored.Downloads != 2 {
+		t.Fatalf("reloaded link %+v, error %v", stored, err)
+	}
+}
+
+func TestStoreRevoke(t *testing.T) {
+	store := newTestStore(t)
+	link, token, err := store.Create(&LinkParams{Bucket: "b1", Folder: "docs/"})
+	if err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+
+	if err = store.Revoke("b2", link.ID); !errors.Is(err, ErrLinkNotFound) {
+		t.Fatalf("link of other bucket: expected ErrLinkNotFound, got %v", err)
+	}
+	if err = store.Revoke("b1", link.ID); err != nil {
+		t.Fatalf("failed to revoke link: %v", err)
+	}
+	if _, err = store.Check(token, ""); !errors.Is(err, ErrLinkUnavailable) {
+		t.Fatalf("revoked link: expected ErrLinkUnavailable, got %v", err)
+	}
+}
+
+func TestStoreCreateInvalidParams(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		params LinkParams
+	}{
+		{name: "nothing shared", params: LinkParams{Bucket: "b1"}},
+		{name: "file and folder", params: LinkParams{Bucket: "b1", FilePath: "a.txt", Folder: "docs/"}},
+		{name: "folder in set", params: LinkParams{Bucket: "b1", FilePaths: []string{"a.txt", "docs/"}}},
+		{name: "empty path in set", params: LinkParams{Bucket: "b1", FilePaths: []string{""}}},
+		{name: "negative downloads", params: LinkParams{Bucket: "b1", FilePath: "a.txt", MaxDownloads: -1}},
+		{name: "past expiry", params: LinkParams{Bucket: "b1", FilePath: "a.txt", ExpiresAt: &past}},
+	}
+
+	store := newTestStore(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := store.Create(&test.params); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestNewRequiresBaseURL(t *testing.T) {
+	config := &Config{Enabled: true, StoreFile: filepath.Join(t.TempDir(), "links.json")}
+	if _, err := New(config); err == nil {
+		t.Fatal("store is created without base URL")
+	}
+}
+
+func TestStorePasswordLockout(t *testing.T) {
+	store := newTestStore(t)
+	_, token, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "a.txt", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherToken, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "b.txt", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Steps run in order, right password resets count of wrong ones
+	// and missing password is not counted as attempt.
+	steps := []struct {
+		name     string
+		token    string
+		password string
+		expected error
+	}{
+		{name: "first wrong", token: token, password: "guess", expected: ErrWrongPassword},
+		{name: "second wrong", token: token, password: "guess", expected: ErrWrongPassword},
+		{name: "right resets count", token: token, password: "secret"},
+		{name: "missing", token: token, expected: ErrPasswordNeeded},
+		{name: "wrong after reset", token: token, password: "guess", expected: ErrWrongPassword},
+		{name: "wrong again", token: token, password: "guess", expected: ErrWrongPassword},
+		{name: "wrong locks link", token: token, password: "guess", expected: ErrWrongPassword},
+		{name: "right of locked link", token: token, password: "secret", expected: ErrLinkLocked},
+		{name: "other link", token: otherToken, password: "secret"},
+	}
+
+	for _, step := range steps {
+		if _, err = store.Check(step.token, step.password); !errors.Is(err, step.expected) {
+			t.Fatalf("%s: error %v, expected %v", step.name, err, step.expected)
+		}
+	}
+}
+
+func TestStorePrunesExpiredLinks(t *testing.T) {
+	store := newTestStore(t)
+	expiring, _, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "a.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, _, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "b.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Link expires while store is running, it is dropped by next save.
+	past := time.Now().Add(-time.Minute)
+	store.mu.Lock()
+	store.links[0].ExpiresAt = &past
+	store.mu.Unlock()
+
+	if err = store.Revoke("b1", kept.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Link("b1", expiring.ID); !errors.Is(err, ErrLinkNotFound) {
+		t.Fatalf("expired link is kept: %v", err)
+	}
+
+	reloaded, err := New(store.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if links := reloaded.Links("b1"); len(links) != 1 || links[0].ID != kept.ID {
+		t.Fatalf("unexpected stored links %+v", links)
+	}
+}
+
+func TestStoreCountSavesUnlimitedLinksLater(t *testing.T) {
+	store := newTestStore(t)
+	link, _, err := store.Create(&LinkParams{Bucket: "b1", FilePath: "a.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if err = store.Count("b1", link.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stored := func() int {
+		reloaded, err := New(store.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		storedLink, err := reloaded.Link("b1", link.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return storedLink.Downloads
+	}
+
+	if downloads := stored(); downloads != 0 {
+		t.Fatalf("downloads are saved at once: %d", downloads)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if downloads := stored(); downloads != 3 {
+		t.Fatalf("downloads are not saved on close: %d", downloads)
+	}
+}
+
+func TestStoreCheckArchive(t *testing.T) {
+	store := newTestStore(t)
+	store.config.MaxZipFiles, store.config.MaxZipSize = 2, 100
+
+	tests := []struct {
+		name      string
+		fileCount int
+		totalSize int64
+		isValid   bool
+	}{
+		{name: "within limits", fileCount: 2, totalSize: 100, isValid: true},
+		{name: "too many files", fileCount: 3, totalSize: 10},
+		{name: "too large", fileCount: 1, totalSize: 101},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := store.CheckArchive(test.fileCount, test.totalSize)
+			if test.isValid != (err == nil) || err != nil && !errors.Is(err, ErrArchiveTooLarge) {
+				t.Fatalf("unexpected error %v, expected valid %v", err, test.isValid)
+			}
+		})
+	}
+
+	store.config.MaxZipFiles, store.config.MaxZipSize = 0, 0
+	if err := store.CheckArchive(10000, 1<<40); err != nil {
+		t.Fatalf("archive is limited without limits: %v", err)
+	}
+}
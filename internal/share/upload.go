@@ -0,0 +1,282 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrUploadLinkNotFound = errors.New("upload link does not exist")
+
+// UploadLink lets external contributor upload files into folder
+// of bucket without account, only hash of its token is kept.
+type UploadLink struct {
+	ID        string     `json:"id" example:"8d3a1f6b2c7e4a90"`
+	TokenHash string     `json:"token_hash,omitempty" swaggerignore:"true"`
+	Bucket    string     `json:"bucket" example:"contracts"`
+	Folder    string     `json:"folder" example:"incoming/acme/"`
+	Creator   string     `json:"creator" example:"alice"`
+	CreatedAt time.Time  `json:"created_at" example:"2025-01-01T12:01:01Z"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-01-08T12:01:01Z"`
+	MaxFiles  int        `json:"max_files,omitempty" example:"10"`
+	MaxSize   int64      `json:"max_size,omitempty" example:"104857600"`
+	Uploads   int        `json:"uploads" example:"3"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" example:"2025-01-02T09:00:00Z"`
+}
+
+// IsAvailable reports whether file can be uploaded by link at moment.
+func (l *UploadLink) IsAvailable(now time.Time) bool {
+	switch {
+	case l.RevokedAt != nil:
+		return false
+	case l.ExpiresAt != nil && !now.Before(*l.ExpiresAt):
+		return false
+	case l.MaxFiles > 0 && l.Uploads >= l.MaxFiles:
+		return false
+	default:
+		return true
+	}
+}
+
+// RemainingFiles returns number of files which can still be
+// uploaded by link, -1 means there is no limit.
+func (l *UploadLink) RemainingFiles() int {
+	if l.MaxFiles <= 0 {
+		return -1
+	}
+
+	return max(l.MaxFiles-l.Uploads, 0)
+}
+
+func (l *UploadLink) withoutSecrets() *UploadLink {
+	link := *l
+	link.TokenHash = ""
+	return &link
+}
+
+// UploadLinkParams are settings of new upload link, zero values mean no limit.
+type UploadLinkParams struct {
+	Bucket    string
+	Folder    string
+	Creator   string
+	ExpiresAt *time.Time
+	MaxFiles  int
+	MaxSize   int64
+}
+
+type uploadStoreFile struct {
+	Links []*UploadLink `json:"links"`
+}
+
+type UploadStore struct {
+	config *Config
+
+	mu    sync.Mutex
+	links []*UploadLink
+}
+
+// NewUploadStore loads upload links, missing file means there are no links yet.
+func NewUploadStore(config *Config) (*UploadStore, error) {
+	store := &UploadStore{config: config, links: make([]*UploadLink, 0)}
+	if !config.Enabled {
+		return store, nil
+	}
+
+	if config.UploadStoreFile == "" {
+		return nil, errors.New("upload store file is required for upload links")
+	}
+
+	file := &uploadStoreFile{}
+	if err := readStoreFile(config.UploadStoreFile, file); err != nil {
+		return nil, err
+	}
+
+	if file.Links != nil {
+		store.links = file.Links
+	}
+
+	return store, nil
+}
+
+func (s *UploadStore) IsEnabled() bool {
+	return s.config.Enabled
+}
+
+// Create stores new upload link and returns token it is used by,
+// token is returned only here and can not be restored later.
+func (s *UploadStore) Create(params *UploadLinkParams) (*UploadLink, string, error) {
+	now := time.Now().UTC()
+	expiresAt, err := limitExpiry(s.config, now, params.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.MaxFiles < 0 || params.MaxSize < 0 {
+		return nil, "", errors.New("max files and max size must not be negative")
+	}
+
+	linkID, err := randomID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	link := &UploadLink{
+		ID:        linkID,
+		TokenHash: hashToken(token),
+		Bucket:    params.Bucket,
+		Folder:    params.Folder,
+		Creator:   params.Creator,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+		MaxFiles:  params.MaxFiles,
+		MaxSize:   params.MaxSize,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.save(append(s.links, link)); err != nil {
+		return nil, "", err
+	}
+
+	return link.withoutSecrets(), token, nil
+}
+
+// Links returns upload links of bucket including revoked and expired ones.
+func (s *UploadStore) Links(bucket string) []*UploadLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := make([]*UploadLink, 0)
+	for _, link := range s.links {
+		if link.Bucket == bucket {
+			links = append(links, link.withoutSecrets())
+		}
+	}
+
+	return links
+}
+
+// Link returns upload link of bucket by its id.
+func (s *UploadStore) Link(bucket, linkID string) (*UploadLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findLink(bucket, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return link.withoutSecrets(), nil
+}
+
+// Revoke disables upload link, it is kept in store to show when it was revoked.
+func (s *UploadStore) Revoke(bucket, linkID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findLink(bucket, linkID)
+	if err != nil {
+		return err
+	}
+
+	if link.RevokedAt == nil {
+		now := time.Now().UTC()
+		link.RevokedAt = &now
+	}
+
+	return s.save(s.links)
+}
+
+// Open returns available upload link by its token.
+func (s *UploadStore) Open(token string) (*UploadLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !link.IsAvailable(time.Now()) {
+		return nil, ErrLinkUnavailable
+	}
+
+	return link.withoutSecrets(), nil
+}
+
+// Reserve counts file which is about to be uploaded by link, so
+// that parallel uploads can not exceed max files. Reserved file is
+// released if it has not been uploaded.
+func (s *UploadStore) Reserve(token string) (*UploadLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !link.IsAvailable(time.Now()) {
+		return nil, ErrLinkUnavailable
+	}
+
+	link.Uploads++
+	if err = s.save(s.links); err != nil {
+		link.Uploads--
+		return nil, err
+	}
+
+	return link.withoutSecrets(), nil
+}
+
+func (s *UploadStore) Release(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.findToken(token)
+	if err != nil {
+		return err
+	}
+
+	link.Uploads = max(link.Uploads-1, 0)
+	return s.save(s.links)
+}
+
+func (s *UploadStore) findToken(token string) (*UploadLink, error) {
+	hash := hashToken(token)
+	for _, link := range s.links {
+		if link.TokenHash == hash {
+			return link, nil
+		}
+	}
+
+	return nil, ErrUploadLinkNotFound
+}
+
+func (s *UploadStore) findLink(bucket, linkID string) (*UploadLink, error) {
+	for _, link := range s.links {
+		if link.ID == linkID && link.Bucket == bucket {
+			return link, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUploadLinkNotFound, linkID)
+}
+
+// save writes upload links into store file and applies them,
+// must be called with lock held.
+func (s *UploadStore) save(links []*UploadLink) error {
+	if err := writeStoreFile(s.config.UploadStoreFile, &uploadStoreFile{Links: links}); err != nil {
+		return err
+	}
+
+	s.links = links
+	return nil
+}
@@ -0,0 +1,149 @@
+package share
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestUploadStore(t *testing.T) *UploadStore {
+	t.Helper()
+
+	config := &Config{
+		Enabled:         true,
+		UploadStoreFile: filepath.Join(t.TempDir(), "upload-links.json"),
+		DefaultExpiry:   time.Hour,
+		MaxExpiry:       24 * time.Hour,
+	}
+	store, err := NewUploadStore(config)
+	if err != nil {
+		t.Fatalf("failed to create upload store: %v", err)
+	}
+
+	return store
+}
+
+func TestUploadLinkIsAvailable(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Second)
+	after := now.Add(time.Second)
+
+	tests := []struct {
+		name      string
+		link      UploadLink
+		available bool
+		remaining int
+	}{
+		{name: "unlimited", link: UploadLink{}, available: true, remaining: -1},
+		{name: "not expired", link: UploadLink{ExpiresAt: &after}, available: true, remaining: -1},
+		{name: "expired", link: UploadLink{ExpiresAt: &before}, remaining: -1},
+		{name: "expires now", link: UploadLink{ExpiresAt: &now}, remaining: -1},
+		{name: "revoked", link: UploadLink{RevokedAt: &before}, remaining: -1},
+		{name: "files left", link: UploadLink{MaxFiles: 3, Uploads: 1}, available: true, remaining: 2},
+		{name: "exhausted", link: UploadLink{MaxFiles: 3, Uploads: 3}, remaining: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if available := test.link.IsAvailable(now); available != test.available {
+				t.Fatalf("IsAvailable() = %v, expected %v", available, test.available)
+			}
+			if remaining := test.link.RemainingFiles(); remaining != test.remaining {
+				t.Fatalf("RemainingFiles() = %d, expected %d", remaining, test.remaining)
+			}
+		})
+	}
+}
+
+func TestCreateUploadLink(t *testing.T) {
+	store := newTestUploadStore(t)
+
+	if _, _, err := store.Create(&UploadLinkParams{Bucket: "b1", Folder: "in/", MaxFiles: -1}); err == nil {
+		t.Fatal("negative max files is accepted")
+	}
+	past := time.Now().Add(-time.Minute)
+	if _, _, err := store.Create(&UploadLinkParams{Bucket: "b1", Folder: "in/", ExpiresAt: &past}); err == nil {
+		t.Fatal("past expiry is accepted")
+	}
+
+	link, token, err := store.Create(&UploadLinkParams{Bucket: "b1", Folder: "in/", Creator: "alice", MaxFiles: 2, MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" || link.TokenHash != "" || link.ExpiresAt == nil {
+		t.Fatalf("unexpected created link %+v, token %q", link, token)
+	}
+
+	data, err := os.ReadFile(store.config.UploadStoreFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), token) {
+		t.Fatal("token is stored in plain text")
+	}
+
+	reloaded, err := NewUploadStore(store.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := reloaded.Open(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened.ID != link.ID || opened.Folder != "in/" || opened.MaxSize != 10 {
+		t.Fatalf("unexpected reloaded link %+v", opened)
+	}
+
+	if _, err = reloaded.Open("unknown"); !errors.Is(err, ErrUploadLinkNotFound) {
+		t.Fatalf("unknown token: expected ErrUploadLinkNotFound, got %v", err)
+	}
+	if links := reloaded.Links("b2"); len(links) != 0 {
+		t.Fatalf("links of other bucket are returned: %+v", links)
+	}
+}
+
+func TestUploadLinkReserve(t *testing.T) {
+	store := newTestUploadStore(t)
+
+	link, token, err := store.Create(&UploadLinkParams{Bucket: "b1", Folder: "in/", MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if _, err = store.Reserve(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = store.Reserve(token); !errors.Is(err, ErrLinkUnavailable) {
+		t.Fatalf("exhausted link: expected ErrLinkUnavailable, got %v", err)
+	}
+
+	// Released file of failed upload can be uploaded again.
+	if err = store.Release(token); err != nil {
+		t.Fatal(err)
+	}
+	reserved, err := store.Reserve(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reserved.Uploads != 2 || reserved.RemainingFiles() != 0 {
+		t.Fatalf("unexpected uploads %d of link", reserved.Uploads)
+	}
+
+	if err = store.Revoke("b1", link.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Release(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Open(token); !errors.Is(err, ErrLinkUnavailable) {
+		t.Fatalf("revoked link: expected ErrLinkUnavailable, got %v", err)
+	}
+	if err = store.Revoke("b2", link.ID); !errors.Is(err, ErrUploadLinkNotFound) {
+		t.Fatalf("link is revoked by other bucket: %v", err)
+	}
+}
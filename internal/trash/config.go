@@ -0,0 +1,9 @@
+package trash
+
+import "time"
+
+type Config struct {
+	Enabled       bool
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
@@ -0,0 +1,326 @@
+package trash
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"docs-hub/internal/cloud"
+)
+
+// DirName is prefix of per-bucket trash area. Every deleted object is kept
+// under its entry directory with original path, next to entry metadata.
+const DirName = ".trash/"
+
+const (
+	defaultPurgeInterval = time.Hour
+	removeBatchSize      = 1000
+)
+
+var ErrInvalidEntry = errors.New("invalid trash entry id")
+
+type Entry struct {
+	ID           string    `json:"id"`
+	OriginalPath string    `json:"original_path"`
+	DeletedBy    string    `json:"deleted_by"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	Size         int64     `json:"size"`
+}
+
+type EntriesPage struct {
+	Items      []*Entry `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+type Trash struct {
+	config *Config
+	cloud  *cloud.DocumentHub
+}
+
+func New(config *Config, hub *cloud.DocumentHub) *Trash {
+	return &Trash{config: config, cloud: hub}
+}
+
+func (t *Trash) IsEnabled() bool {
+	return t.config.Enabled
+}
+
+// IsTrashPath reports whether object key belongs to trash area.
+func IsTrashPath(filePath string) bool {
+	return strings.HasPrefix(filePath, DirName)
+}
+
+// MoveToTrash moves file into trash of its bucket instead of removing it.
+func (t *Trash) MoveToTrash(ctx context.Context, bucket, filePath, deletedBy string) (*Entry, error) {
+	doc := t.cloud.Cloud
+	fileInfo, err := doc.StatFile(ctx, bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	entryID, err := newEntryID()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		ID:           entryID,
+		OriginalPath: filePath,
+		DeletedBy:    deletedBy,
+		DeletedAt:    time.Now().UTC(),
+		Size:         fileInfo.Size,
+	}
+
+	dataPath := entryDataPath(entry)
+	if err = doc.MoveFile(ctx, bucket, filePath, bucket, dataPath); err != nil {
+		return nil, err
+	}
+
+	if err = t.writeEntry(ctx, bucket, entry); err != nil {
+		if moveErr := doc.MoveFile(ctx, bucket, dataPath, bucket, filePath); moveErr != nil {
+			log.Println("failed to return file from trash: ", filePath, moveErr)
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// List returns page of trash entries, the oldest first. Entries which
+// visible rejects are skipped before paging, so they do not shorten pages.
+func (t *Trash) List(ctx context.Context, bucket string, limit int, cursor string, visible func(*Entry) bool) (*EntriesPage, error) {
+	entries := make(map[string]*Entry)
+	params := &cloud.ListFilesParams{
+		Prefix: DirName,
+		Limit:  limit,
+		Cursor: cursor,
+		Filter: cloud.ListFilesFilter{
+			NameGlob: "*.json",
+			Visible: func(item *cloud.StorageItem) bool {
+				if !strings.HasSuffix(item.FileName, ".json") {
+					return false
+				}
+
+				entryID := strings.TrimSuffix(strings.TrimPrefix(item.FileName, DirName), ".json")
+				entry, err := t.readEntry(ctx, bucket, entryID)
+				if err != nil {
+					log.Println("failed to read trash entry: ", item.FileName, err)
+					return false
+				}
+
+				entries[item.FileName] = entry
+				return visible == nil || visible(entry)
+			},
+		},
+	}
+
+	filesPage, err := t.cloud.Cloud.GetFiles(ctx, bucket, params)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &EntriesPage{
+		Items:      make([]*Entry, 0, len(filesPage.Items)),
+		NextCursor: filesPage.NextCursor,
+	}
+
+	for _, item := range filesPage.Items {
+		page.Items = append(page.Items, entries[item.FileName])
+	}
+
+	return page, nil
+}
+
+// GetEntry returns trash entry of bucket by its id.
+func (t *Trash) GetEntry(ctx context.Context, bucket, entryID string) (*Entry, error) {
+	return t.readEntry(ctx, bucket, entryID)
+}
+
+// Restore moves file of trash entry back to its original path and
+// returns path file has been restored to according to conflict policy.
+func (t *Trash) Restore(ctx context.Context, bucket, entryID, onConflict string) (string, error) {
+	entry, err := t.readEntry(ctx, bucket, entryID)
+	if err != nil {
+		return "", err
+	}
+
+	doc := t.cloud.Cloud
+	dstPath, err := cloud.MoveByPolicy(ctx, doc, bucket, entryDataPath(entry), bucket, entry.OriginalPath, onConflict)
+	if errors.Is(err, cloud.ErrFileSkipped) {
+		return entry.OriginalPath, err
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err = doc.RemoveFile(ctx, bucket, entryMetaPath(entryID)); err != nil {
+		log.Println("failed to remove trash entry: ", entryID, err)
+	}
+
+	return dstPath, nil
+}
+
+// Empty permanently removes all trash entries of bucket.
+func (t *Trash) Empty(ctx context.Context, bucket string) error {
+	return t.removeKeys(ctx, bucket, DirName)
+}
+
+// PurgeExpired permanently removes trash entries of all buckets
+// which have been deleted earlier than retention period ago.
+func (t *Trash) PurgeExpired(ctx context.Context) error {
+	if t.config.Retention <= 0 {
+		return nil
+	}
+
+	buckets, err := t.cloud.Cloud.GetBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	expiredAt := time.Now().Add(-t.config.Retention)
+	for _, bucket := range buckets {
+		if err = t.purgeBucket(ctx, bucket, expiredAt); err != nil {
+			log.Println("failed to purge trash of bucket: ", bucket, err)
+		}
+	}
+
+	return nil
+}
+
+// Run purges expired trash entries periodically until context is done.
+func (t *Trash) Run(ctx context.Context) {
+	interval := t.config.PurgeInterval
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.PurgeExpired(ctx); err != nil {
+			log.Println("failed to purge trash: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Trash) purgeBucket(ctx context.Context, bucket string, expiredAt time.Time) error {
+	cursor := ""
+	for {
+		page, err := t.List(ctx, bucket, removeBatchSize, cursor, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range page.Items {
+			if entry.DeletedAt.After(expiredAt) {
+				continue
+			}
+
+			if err = t.removeEntry(ctx, bucket, entry); err != nil {
+				log.Println("failed to remove trash entry: ", entry.ID, err)
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func (t *Trash) removeEntry(ctx context.Context, bucket string, entry *Entry) error {
+	if err := t.removeKeys(ctx, bucket, DirName+entry.ID); err != nil {
+		return err
+	}
+
+	return t.cloud.Cloud.RemoveFile(ctx, bucket, entryMetaPath(entry.ID))
+}
+
+func (t *Trash) removeKeys(ctx context.Context, bucket, folderPath string) error {
+	doc := t.cloud.Cloud
+	items, err := cloud.ListFolder(ctx, doc, bucket, folderPath)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(items); start += removeBatchSize {
+		batch := items[start:min(start+removeBatchSize, len(items))]
+		filePaths := make([]string, len(batch))
+		for index, item := range batch {
+			filePaths[index] = item.FileName
+		}
+
+		for filePath, err := range doc.RemoveFiles(ctx, bucket, filePaths) {
+			if err != nil {
+				return fmt.Errorf("failed to remove %s: %w", filePath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *Trash) readEntry(ctx context.Context, bucket, entryID string) (*Entry, error) {
+	if entryID == "" || strings.ContainsAny(entryID, `/\.`) {
+		return nil, ErrInvalidEntry
+	}
+
+	entryData, err := t.cloud.Cloud.DownloadFile(ctx, bucket, entryMetaPath(entryID))
+	if err != nil {
+		return nil, fmt.Errorf("trash entry %s does not exist", entryID)
+	}
+	defer func() {
+		if err := entryData.Close(); err != nil {
+			log.Println("failed to close trash entry: ", entryID, err)
+		}
+	}()
+
+	entry := &Entry{}
+	if err = json.NewDecoder(entryData).Decode(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (t *Trash) writeEntry(ctx context.Context, bucket string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	metaPath := entryMetaPath(entry.ID)
+	return t.cloud.Cloud.UploadFile(ctx, bucket, metaPath, bytes.NewReader(data), int64(len(data)))
+}
+
+// newEntryID returns id which orders entries by deletion time.
+func newEntryID() (string, error) {
+	randBytes := make([]byte, 4)
+	if _, err := rand.Read(randBytes); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x-%s", time.Now().UnixNano(), hex.EncodeToString(randBytes)), nil
+}
+
+func entryMetaPath(entryID string) string {
+	return DirName + entryID + ".json"
+}
+
+func entryDataPath(entry *Entry) string {
+	return DirName + entry.ID + "/" + entry.OriginalPath
+}
@@ -0,0 +1,263 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"docs-hub/internal/cloud"
+	"docs-hub/internal/cloud/inmemory"
+	"docs-hub/internal/cloud/localfs"
+)
+
+type testBackend struct {
+	name string
+	hub  *cloud.DocumentHub
+}
+
+func testBackends(t *testing.T) []*testBackend {
+	t.Helper()
+
+	backends := []*testBackend{
+		{name: "inmemory", hub: inmemory.New(&cloud.CloudConfig{ShareSecret: "test"})},
+		{name: "localfs", hub: localfs.New(&cloud.CloudConfig{ShareSecret: "test", RootPath: t.TempDir()})},
+	}
+
+	for _, backend := range backends {
+		if err := backend.hub.Cloud.CreateBucket(context.Background(), "b1"); err != nil {
+			t.Fatalf("%s: failed to create bucket: %v", backend.name, err)
+		}
+	}
+
+	return backends
+}
+
+func uploadText(t *testing.T, doc cloud.IDocument, filePath, content string) {
+	t.Helper()
+
+	err := doc.UploadFile(context.Background(), "b1", filePath, strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("failed to upload %s: %v", filePath, err)
+	}
+}
+
+func readText(t *testing.T, doc cloud.IDocument, filePath string) string {
+	t.Helper()
+
+	fileData, err := doc.DownloadFile(context.Background(), "b1", filePath)
+	if err != nil {
+		t.Fatalf("failed to download %s: %v", filePath, err)
+	}
+	defer func() { _ = fileData.Close() }()
+
+	data, err := io.ReadAll(fileData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestMoveToTrashAndRestore(t *testing.T) {
+	tests := []struct {
+		name string
+		// replacement is uploaded to original path after file is trashed.
+		replacement string
+		onConflict  string
+		restoredTo  string
+		err         error
+	}{
+		{name: "free original path", restoredTo: "docs/a.txt"},
+		{name: "rename on conflict", replacement: "new", onConflict: cloud.ConflictRename, restoredTo: "docs/a (1).txt"},
+		{name: "overwrite on conflict", replacement: "new", onConflict: cloud.ConflictOverwrite, restoredTo: "docs/a.txt"},
+		{name: "fail on conflict", replacement: "new", onConflict: cloud.ConflictFail, err: cloud.ErrFileExists},
+	}
+
+	for _, test := range tests {
+		for _, backend := range testBackends(t) {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				doc := backend.hub.Cloud
+				trashBin := New(&Config{Enabled: true}, backend.hub)
+				uploadText(t, doc, "docs/a.txt", "deleted")
+
+				entry, err := trashBin.MoveToTrash(ctx, "b1", "docs/a.txt", "alice")
+				if err != nil {
+					t.Fatalf("failed to move file to trash: %v", err)
+				}
+				if entry.OriginalPath != "docs/a.txt" || entry.DeletedBy != "alice" || entry.Size != 7 {
+					t.Fatalf("unexpected entry %+v", entry)
+				}
+				if _, err = doc.StatFile(ctx, "b1", "docs/a.txt"); err == nil {
+					t.Fatal("trashed file still exists at original path")
+				}
+
+				page, err := trashBin.List(ctx, "b1", 10, "", nil)
+				if err != nil || len(page.Items) != 1 || page.Items[0].ID != entry.ID {
+					t.Fatalf("trash listing %+v, error %v", page, err)
+				}
+
+				if test.replacement != "" {
+					uploadText(t, doc, "docs/a.txt", test.replacement)
+				}
+
+				restoredTo, err := trashBin.Restore(ctx, "b1", entry.ID, test.onConflict)
+				if !errors.Is(err, test.err) {
+					t.Fatalf("restore error %v, expected %v", err, test.err)
+				}
+				if test.err != nil {
+					if content := readText(t, doc, "docs/a.txt"); content != test.replacement {
+						t.Fatalf("failed restore changed existing file: %q", content)
+					}
+					return
+				}
+
+				if restoredTo != test.restoredTo {
+					t.Fatalf("restored to %q, expected %q", restoredTo, test.restoredTo)
+				}
+				if content := readText(t, doc, restoredTo); content != "deleted" {
+					t.Fatalf("restored content %q", content)
+				}
+				if page, err = trashBin.List(ctx, "b1", 10, "", nil); err != nil || len(page.Items) != 0 {
+					t.Fatalf("restored entry is still listed: %+v, %v", page, err)
+				}
+			})
+		}
+	}
+}
+
+func TestListPaginatesEntries(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			trashBin := New(&Config{Enabled: true}, backend.hub)
+			for _, filePath := range []string{"a.txt", "b.txt", "c.txt"} {
+				uploadText(t, backend.hub.Cloud, filePath, filePath)
+				if _, err := trashBin.MoveToTrash(ctx, "b1", filePath, "alice"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			removed := make([]string, 0)
+			cursor := ""
+			for range 3 {
+				page, err := trashBin.List(ctx, "b1", 2, cursor, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, entry := range page.Items {
+					removed = append(removed, entry.OriginalPath)
+				}
+				if cursor = page.NextCursor; cursor == "" {
+					break
+				}
+			}
+
+			if strings.Join(removed, ",") != "a.txt,b.txt,c.txt" {
+				t.Fatalf("entries %v are not listed oldest first", removed)
+			}
+		})
+	}
+}
+
+func TestListFiltersEntriesBeforePaging(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			trashBin := New(&Config{Enabled: true}, backend.hub)
+			for _, filePath := range []string{"private/a.txt", "public/b.txt", "private/c.txt", "public/d.txt"} {
+				uploadText(t, backend.hub.Cloud, filePath, filePath)
+				if _, err := trashBin.MoveToTrash(ctx, "b1", filePath, "alice"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			visible := func(entry *Entry) bool {
+				return strings.HasPrefix(entry.OriginalPath, "public/")
+			}
+			page, err := trashBin.List(ctx, "b1", 2, "", visible)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			listed := make([]string, 0, len(page.Items))
+			for _, entry := range page.Items {
+				listed = append(listed, entry.OriginalPath)
+			}
+			if strings.Join(listed, ",") != "public/b.txt,public/d.txt" {
+				t.Fatalf("listed entries %v", listed)
+			}
+		})
+	}
+}
+
+func TestRestoreInvalidEntry(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			trashBin := New(&Config{Enabled: true}, backend.hub)
+			for _, entryID := range []string{"", "../a", "a/b", "a.json"} {
+				if _, err := trashBin.Restore(context.Background(), "b1", entryID, ""); !errors.Is(err, ErrInvalidEntry) {
+					t.Errorf("entry %q: expected ErrInvalidEntry, got %v", entryID, err)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyAndPurge(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention time.Duration
+		empty     bool
+		remaining int
+	}{
+		{name: "empty trash", retention: time.Hour, empty: true, remaining: 0},
+		{name: "purge expired entries", retention: time.Nanosecond, remaining: 0},
+		{name: "keep entries within retention", retention: time.Hour, remaining: 2},
+		{name: "purge disabled", retention: 0, remaining: 2},
+	}
+
+	for _, test := range tests {
+		for _, backend := range testBackends(t) {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				ctx := context.Background()
+				doc := backend.hub.Cloud
+				trashBin := New(&Config{Enabled: true, Retention: test.retention}, backend.hub)
+				for _, filePath := range []string{"docs/a.txt", "docs/b.txt"} {
+					uploadText(t, doc, filePath, filePath)
+					if _, err := trashBin.MoveToTrash(ctx, "b1", filePath, "alice"); err != nil {
+						t.Fatal(err)
+					}
+				}
+				uploadText(t, doc, "kept.txt", "kept")
+				time.Sleep(time.Millisecond)
+
+				var err error
+				if test.empty {
+					err = trashBin.Empty(ctx, "b1")
+				} else {
+					err = trashBin.PurgeExpired(ctx)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				page, err := trashBin.List(ctx, "b1", 10, "", nil)
+				if err != nil || len(page.Items) != test.remaining {
+					t.Fatalf("trash has %+v entries with error %v, expected %d", page, err, test.remaining)
+				}
+
+				items, err := cloud.ListFolder(ctx, doc, "b1", DirName)
+				if test.remaining == 0 && (err != nil || len(items) != 0) {
+					t.Fatalf("trash keys are left: %d, %v", len(items), err)
+				}
+				if content := readText(t, doc, "kept.txt"); content != "kept" {
+					t.Fatal("file out of trash has been changed")
+				}
+			})
+		}
+	}
+}